@@ -0,0 +1,238 @@
+package batchx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/logx"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// ErrClosed is returned by Add and Flush after the batcher is closed
+var ErrClosed = errors.New("batchx: batcher is closed")
+
+// FlushFunc receives a batch of items, the slice is owned by the callee
+type FlushFunc[T any] func(ctx context.Context, items []T) error
+
+// ErrorHandler receives flush errors and recovered panics together with the size of the failed batch
+type ErrorHandler func(err error, size int)
+
+// Option batcher configuration structure
+type Option struct {
+	MaxSize      int           // Flush once this many items are pending, default 100
+	MaxWait      time.Duration // Flush once the oldest pending item is this old, default 1s
+	Buffer       int           // Items queued ahead of the batching goroutine, Add blocks when full, defaults to MaxSize
+	FlushTimeout time.Duration // Deadline of the context passed to each flush, 0 means none
+	OnError      ErrorHandler  // Flush error reporter, defaults to logging via logx
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithMaxSize sets the batch size that triggers a flush
+func WithMaxSize(n int) OptionFunc {
+	return func(o *Option) { o.MaxSize = n }
+}
+
+// WithMaxWait sets how long an item may wait before its batch is flushed
+func WithMaxWait(d time.Duration) OptionFunc {
+	return func(o *Option) { o.MaxWait = d }
+}
+
+// WithBuffer sets the number of items accepted ahead of the flusher before Add blocks
+func WithBuffer(n int) OptionFunc {
+	return func(o *Option) { o.Buffer = n }
+}
+
+// WithFlushTimeout bounds each flush call with a context deadline
+func WithFlushTimeout(d time.Duration) OptionFunc {
+	return func(o *Option) { o.FlushTimeout = d }
+}
+
+// WithErrorHandler sets the handler for failed flushes
+func WithErrorHandler(fn ErrorHandler) OptionFunc {
+	return func(o *Option) { o.OnError = fn }
+}
+
+// Batcher accumulates items and hands them to a flush callback in batches, either when
+// MaxSize items are pending or MaxWait has passed since the first of them, whichever comes first
+// Flushes run one at a time on a dedicated goroutine, so a slow callback applies backpressure to Add
+type Batcher[T any] struct {
+	opt      Option
+	flush    FlushFunc[T]
+	mu       sync.RWMutex // Guards closed against concurrent Add/Close
+	closed   bool
+	items    chan T
+	flushReq chan chan struct{}
+	done     chan struct{}
+}
+
+// New starts a batcher delivering batches to flush, call Close to flush the remainder and stop it
+func New[T any](flush FlushFunc[T], opts ...OptionFunc) *Batcher[T] {
+	o := Option{MaxSize: 100, MaxWait: time.Second}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.MaxSize <= 0 {
+		o.MaxSize = 100
+	}
+	if o.MaxWait <= 0 {
+		o.MaxWait = time.Second
+	}
+	if o.Buffer <= 0 {
+		o.Buffer = o.MaxSize
+	}
+	if o.OnError == nil {
+		o.OnError = func(err error, size int) {
+			logx.Error("batchx: flush of %d items failed: %v", size, err)
+		}
+	}
+	b := &Batcher[T]{
+		opt:      o,
+		flush:    flush,
+		items:    make(chan T, o.Buffer),
+		flushReq: make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	go b.run()
+	return b
+}
+
+// Add queues item for the next batch, blocking while the buffer is full
+// Returns ErrClosed after Close, or the context error if ctx is done first
+func (b *Batcher[T]) Add(ctx context.Context, item T) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return ErrClosed
+	}
+	select {
+	case b.items <- item:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// TryAdd queues item without blocking, reporting false when the buffer is full or the batcher is closed
+func (b *Batcher[T]) TryAdd(item T) bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return false
+	}
+	select {
+	case b.items <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+// Flush delivers every item added before the call and waits for the flush to finish
+func (b *Batcher[T]) Flush(ctx context.Context) error {
+	req := make(chan struct{})
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	select {
+	case b.flushReq <- req:
+		b.mu.RUnlock()
+	case <-ctx.Done():
+		b.mu.RUnlock()
+		return ctx.Err()
+	}
+	select {
+	case <-req:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting items, flushes everything pending and waits for the last flush
+// Returns the context error if ctx is done first, the remaining items are still flushed in the background
+func (b *Batcher[T]) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if !b.closed {
+		b.closed = true
+		close(b.items)
+	}
+	b.mu.Unlock()
+	select {
+	case <-b.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run collects items into batches until the item channel is closed
+func (b *Batcher[T]) run() {
+	defer close(b.done)
+	batch := make([]T, 0, b.opt.MaxSize)
+	timer := time.NewTimer(b.opt.MaxWait)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case item, ok := <-b.items:
+			if !ok {
+				b.deliver(batch)
+				return
+			}
+			batch = append(batch, item)
+			if len(batch) == 1 {
+				timer.Reset(b.opt.MaxWait)
+			}
+			if len(batch) >= b.opt.MaxSize {
+				timer.Stop()
+				batch = b.deliver(batch)
+			}
+		case <-timer.C:
+			batch = b.deliver(batch)
+		case req := <-b.flushReq:
+			timer.Stop()
+			// Items added before Flush are already buffered
+			for n := len(b.items); n > 0; n-- {
+				batch = append(batch, <-b.items)
+				if len(batch) >= b.opt.MaxSize {
+					batch = b.deliver(batch)
+				}
+			}
+			batch = b.deliver(batch)
+			close(req)
+		}
+	}
+}
+
+// deliver hands batch to the flush callback and returns a fresh empty batch
+func (b *Batcher[T]) deliver(batch []T) []T {
+	if len(batch) == 0 {
+		return batch
+	}
+	ctx := context.Background()
+	if b.opt.FlushTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, b.opt.FlushTimeout)
+		defer cancel()
+	}
+	if err := b.safeFlush(ctx, batch); err != nil {
+		b.opt.OnError(err, len(batch))
+	}
+	return make([]T, 0, b.opt.MaxSize)
+}
+
+func (b *Batcher[T]) safeFlush(ctx context.Context, batch []T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("batchx: flush panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return b.flush(ctx, batch)
+}
@@ -0,0 +1,37 @@
+package batchx
+
+import (
+	"sync"
+	"time"
+)
+
+// Debouncer runs a function once calls to Trigger have stopped for a quiet period
+type Debouncer struct {
+	mu    sync.Mutex
+	wait  time.Duration
+	fn    func()
+	timer *time.Timer
+}
+
+// NewDebouncer creates a debouncer running fn after wait without a new Trigger
+func NewDebouncer(wait time.Duration, fn func()) *Debouncer {
+	return &Debouncer{wait: wait, fn: fn}
+}
+
+// Trigger (re)starts the quiet period, fn runs on its own goroutine when it elapses
+func (d *Debouncer) Trigger() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.timer == nil {
+		d.timer = time.AfterFunc(d.wait, d.fn)
+		return
+	}
+	d.timer.Reset(d.wait)
+}
+
+// Stop cancels a pending run, reporting whether one was cancelled
+func (d *Debouncer) Stop() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.timer != nil && d.timer.Stop()
+}
@@ -0,0 +1,224 @@
+package bloomx
+
+import (
+	"encoding/binary"
+	"errors"
+	"github.com/chihqiang/gox/hashx"
+	"math"
+	"math/bits"
+	"sync/atomic"
+)
+
+// ErrIncompatible is returned when merging filters of different shapes
+var ErrIncompatible = errors.New("bloomx: filters have different size or hash count")
+
+// ErrInvalidData is returned when decoding malformed serialized filters
+var ErrInvalidData = errors.New("bloomx: invalid serialized filter")
+
+// maxK bounds the number of hash functions, more never pays off and decoded filters are checked against it
+const maxK = 64
+
+// Optimal returns the number of bits m and hash functions k for n expected items at false-positive rate fp
+func Optimal(n uint64, fp float64) (m uint64, k uint32) {
+	if n == 0 {
+		n = 1
+	}
+	if fp <= 0 || fp >= 1 {
+		fp = 0.01
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(fp) / (math.Ln2 * math.Ln2)))
+	k = uint32(math.Max(1, math.Round(float64(m)/float64(n)*math.Ln2)))
+	return max(m, 64), min(k, maxK)
+}
+
+// Filter is a Bloom filter safe for concurrent Add and Test without locks
+// A Test hit means "probably present", a miss means "definitely absent"
+type Filter struct {
+	b atomic.Pointer[filterBits] // Swapped as a whole by UnmarshalBinary
+}
+
+// filterBits is the shape and bit set of a Filter
+type filterBits struct {
+	m     uint64 // Number of bits
+	k     uint32 // Number of hash functions
+	words []atomic.Uint64
+}
+
+// New creates a filter sized for n items at false-positive rate fp, e.g. New(1_000_000, 0.001)
+func New(n uint64, fp float64) *Filter {
+	return NewWithSize(Optimal(n, fp))
+}
+
+// NewWithSize creates a filter of m bits (rounded up to a multiple of 64) using k hash functions,
+// at most 64
+func NewWithSize(m uint64, k uint32) *Filter {
+	m = max((m+63)/64*64, 64)
+	f := &Filter{}
+	f.b.Store(&filterBits{m: m, k: min(max(k, 1), maxK), words: make([]atomic.Uint64, m/64)})
+	return f
+}
+
+// Add inserts data into the filter
+func (f *Filter) Add(data []byte) {
+	b := f.b.Load()
+	h1, h2 := baseHashes(data)
+	for i := uint32(0); i < b.k; i++ {
+		bit := location(h1, h2, i, b.m)
+		b.words[bit/64].Or(1 << (bit % 64))
+	}
+}
+
+// AddString inserts s into the filter
+func (f *Filter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// Test reports whether data may have been added
+func (f *Filter) Test(data []byte) bool {
+	b := f.b.Load()
+	h1, h2 := baseHashes(data)
+	for i := uint32(0); i < b.k; i++ {
+		bit := location(h1, h2, i, b.m)
+		if b.words[bit/64].Load()&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString reports whether s may have been added
+func (f *Filter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// TestAndAdd inserts data and reports whether it may have been present before, for deduplication
+func (f *Filter) TestAndAdd(data []byte) bool {
+	b := f.b.Load()
+	h1, h2 := baseHashes(data)
+	present := true
+	for i := uint32(0); i < b.k; i++ {
+		bit := location(h1, h2, i, b.m)
+		mask := uint64(1) << (bit % 64)
+		if b.words[bit/64].Or(mask)&mask == 0 {
+			present = false
+		}
+	}
+	return present
+}
+
+// TestAndAddString is TestAndAdd for strings
+func (f *Filter) TestAndAddString(s string) bool {
+	return f.TestAndAdd([]byte(s))
+}
+
+// Cap returns the number of bits of the filter
+func (f *Filter) Cap() uint64 {
+	return f.b.Load().m
+}
+
+// K returns the number of hash functions
+func (f *Filter) K() uint32 {
+	return f.b.Load().k
+}
+
+// ApproxCount estimates the number of distinct items added from the number of set bits
+func (f *Filter) ApproxCount() uint64 {
+	b := f.b.Load()
+	x := float64(b.bitCount())
+	m, k := float64(b.m), float64(b.k)
+	if x >= m {
+		return math.MaxUint64
+	}
+	return uint64(math.Round(-m / k * math.Log(1-x/m)))
+}
+
+// FalsePositiveRate estimates the current false-positive probability from the fill ratio
+func (f *Filter) FalsePositiveRate() float64 {
+	b := f.b.Load()
+	return math.Pow(float64(b.bitCount())/float64(b.m), float64(b.k))
+}
+
+// Merge adds every item of other into f, both filters must have the same size and hash count
+func (f *Filter) Merge(other *Filter) error {
+	b, ob := f.b.Load(), other.b.Load()
+	if b.m != ob.m || b.k != ob.k {
+		return ErrIncompatible
+	}
+	for i := range b.words {
+		b.words[i].Or(ob.words[i].Load())
+	}
+	return nil
+}
+
+// Reset clears the filter
+func (f *Filter) Reset() {
+	b := f.b.Load()
+	for i := range b.words {
+		b.words[i].Store(0)
+	}
+}
+
+// MarshalBinary encodes the filter as m, k and the bit set, all big-endian
+func (f *Filter) MarshalBinary() ([]byte, error) {
+	fb := f.b.Load()
+	b := make([]byte, 12, 12+len(fb.words)*8)
+	binary.BigEndian.PutUint64(b, fb.m)
+	binary.BigEndian.PutUint32(b[8:], fb.k)
+	for i := range fb.words {
+		b = binary.BigEndian.AppendUint64(b, fb.words[i].Load())
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes a filter produced by MarshalBinary, replacing the contents of f
+// Concurrent calls on f see either the old or the new filter, never a mix
+func (f *Filter) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 {
+		return ErrInvalidData
+	}
+	m := binary.BigEndian.Uint64(data)
+	k := binary.BigEndian.Uint32(data[8:])
+	data = data[12:]
+	if m == 0 || m%64 != 0 || k == 0 || k > maxK || uint64(len(data)) != m/8 {
+		return ErrInvalidData
+	}
+	b := &filterBits{m: m, k: k, words: make([]atomic.Uint64, m/64)}
+	for i := range b.words {
+		b.words[i].Store(binary.BigEndian.Uint64(data[i*8:]))
+	}
+	f.b.Store(b)
+	return nil
+}
+
+// FromBytes decodes a filter produced by MarshalBinary
+func FromBytes(data []byte) (*Filter, error) {
+	f := &Filter{}
+	if err := f.UnmarshalBinary(data); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (b *filterBits) bitCount() int {
+	n := 0
+	for i := range b.words {
+		n += bits.OnesCount64(b.words[i].Load())
+	}
+	return n
+}
+
+// baseHashes derives the two hashes combined by double hashing (Kirsch-Mitzenmacher)
+func baseHashes(data []byte) (uint64, uint64) {
+	h1 := hashx.XXHash64Bytes(data)
+	// splitmix64 finalizer decorrelates the second hash, forcing it odd keeps probes distinct
+	h2 := h1 + 0x9e3779b97f4a7c15
+	h2 = (h2 ^ (h2 >> 30)) * 0xbf58476d1ce4e5b9
+	h2 = (h2 ^ (h2 >> 27)) * 0x94d049bb133111eb
+	h2 ^= h2 >> 31
+	return h1, h2 | 1
+}
+
+// location returns the bit probed by the i-th hash function
+func location(h1, h2 uint64, i uint32, m uint64) uint64 {
+	return (h1 + uint64(i)*h2) % m
+}
@@ -0,0 +1,128 @@
+package bloomx
+
+import (
+	"encoding/binary"
+	"slices"
+	"sync"
+)
+
+// CountingFilter is a Bloom filter with 8-bit counters instead of bits, so items can be removed
+// Counters saturate at 255 and then never decrease, trading exact removal for no false negatives
+type CountingFilter struct {
+	mu       sync.RWMutex
+	m        uint64
+	k        uint32
+	counters []uint8
+}
+
+// NewCounting creates a counting filter sized for n items at false-positive rate fp
+func NewCounting(n uint64, fp float64) *CountingFilter {
+	m, k := Optimal(n, fp)
+	return &CountingFilter{m: m, k: k, counters: make([]uint8, m)}
+}
+
+// Add inserts data into the filter
+func (f *CountingFilter) Add(data []byte) {
+	h1, h2 := baseHashes(data)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, l := range f.locations(h1, h2) {
+		if c := &f.counters[l]; *c < 255 {
+			*c++
+		}
+	}
+}
+
+// Remove deletes one occurrence of data, reporting false if it was definitely not present
+// Removing an item that was never added may remove other items as well
+func (f *CountingFilter) Remove(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	locs := f.locations(h1, h2)
+	for _, l := range locs {
+		if f.counters[l] == 0 {
+			return false
+		}
+	}
+	for _, l := range locs {
+		if c := &f.counters[l]; *c > 0 && *c < 255 {
+			*c--
+		}
+	}
+	return true
+}
+
+// locations returns the distinct counters probed for a key
+// Probes can land on the same counter when m is small, each key moves a counter at most once
+func (f *CountingFilter) locations(h1, h2 uint64) []uint64 {
+	locs := make([]uint64, 0, f.k)
+	for i := uint32(0); i < f.k; i++ {
+		if l := location(h1, h2, i, f.m); !slices.Contains(locs, l) {
+			locs = append(locs, l)
+		}
+	}
+	return locs
+}
+
+// Test reports whether data may be present
+func (f *CountingFilter) Test(data []byte) bool {
+	h1, h2 := baseHashes(data)
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	for i := uint32(0); i < f.k; i++ {
+		if f.counters[location(h1, h2, i, f.m)] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// AddString inserts s into the filter
+func (f *CountingFilter) AddString(s string) {
+	f.Add([]byte(s))
+}
+
+// RemoveString deletes one occurrence of s
+func (f *CountingFilter) RemoveString(s string) bool {
+	return f.Remove([]byte(s))
+}
+
+// TestString reports whether s may be present
+func (f *CountingFilter) TestString(s string) bool {
+	return f.Test([]byte(s))
+}
+
+// Reset clears the filter
+func (f *CountingFilter) Reset() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	clear(f.counters)
+}
+
+// MarshalBinary encodes the filter as m, k and the counters
+func (f *CountingFilter) MarshalBinary() ([]byte, error) {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	b := make([]byte, 12, 12+len(f.counters))
+	binary.BigEndian.PutUint64(b, f.m)
+	binary.BigEndian.PutUint32(b[8:], f.k)
+	return append(b, f.counters...), nil
+}
+
+// UnmarshalBinary decodes a filter produced by MarshalBinary, replacing the contents of f
+func (f *CountingFilter) UnmarshalBinary(data []byte) error {
+	if len(data) < 12 {
+		return ErrInvalidData
+	}
+	m := binary.BigEndian.Uint64(data)
+	k := binary.BigEndian.Uint32(data[8:])
+	if m == 0 || k == 0 || k > maxK || uint64(len(data)-12) != m {
+		return ErrInvalidData
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.m, f.k = m, k
+	f.counters = append([]uint8(nil), data[12:]...)
+	return nil
+}
@@ -0,0 +1,268 @@
+package cachex
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Option cache configuration structure
+type Option struct {
+	MaxEntries      int           // Maximum number of entries before LRU eviction, 0 means unlimited
+	DefaultTTL      time.Duration // TTL used by Set, 0 means entries never expire
+	CleanupInterval time.Duration // Interval of the background janitor, 0 disables it
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithMaxEntries bounds the cache size, least recently used entries are evicted first
+func WithMaxEntries(n int) OptionFunc {
+	return func(o *Option) { o.MaxEntries = n }
+}
+
+// WithDefaultTTL sets the TTL applied by Set
+func WithDefaultTTL(ttl time.Duration) OptionFunc {
+	return func(o *Option) { o.DefaultTTL = ttl }
+}
+
+// WithJanitor starts a background goroutine that purges expired entries every interval
+// Without it, expired entries are removed lazily on access or eviction; call Close to stop it
+func WithJanitor(interval time.Duration) OptionFunc {
+	return func(o *Option) { o.CleanupInterval = interval }
+}
+
+// Stats holds cache counters
+type Stats struct {
+	Hits      uint64 // Successful lookups
+	Misses    uint64 // Lookups of absent or expired keys
+	Evictions uint64 // Entries removed to respect MaxEntries
+	Entries   int    // Current number of entries, including expired ones not yet purged
+}
+
+type entry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time // Zero means no expiry
+}
+
+func (e *entry[K, V]) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && now.After(e.expiresAt)
+}
+
+// call is an in-flight GetOrLoad shared by concurrent callers of the same key
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// Cache is a concurrency-safe in-memory cache with per-entry TTL and LRU eviction
+type Cache[K comparable, V any] struct {
+	mu       sync.Mutex
+	opt      Option
+	items    map[K]*list.Element
+	lru      *list.List // Front is most recently used
+	inflight map[K]*call[V]
+
+	hits, misses, evictions atomic.Uint64
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a cache, see the With* options for size, TTL and janitor settings
+func New[K comparable, V any](opts ...OptionFunc) *Cache[K, V] {
+	c := &Cache[K, V]{
+		items:    make(map[K]*list.Element),
+		lru:      list.New(),
+		inflight: make(map[K]*call[V]),
+		stop:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&c.opt)
+	}
+	if c.opt.CleanupInterval > 0 {
+		go c.janitor(c.opt.CleanupInterval)
+	}
+	return c
+}
+
+// Get returns the value for key if present and not expired
+func (c *Cache[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if v, ok := c.peek(key); ok {
+		c.hits.Add(1)
+		return v, true
+	}
+	c.misses.Add(1)
+	var zero V
+	return zero, false
+}
+
+// peek returns the live value for key and marks it recently used, c.mu must be held
+func (c *Cache[K, V]) peek(key K) (V, bool) {
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		if !e.expired(time.Now()) {
+			c.lru.MoveToFront(el)
+			return e.value, true
+		}
+		c.removeElement(el)
+	}
+	var zero V
+	return zero, false
+}
+
+// Set stores value under key with the default TTL
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.SetWithTTL(key, value, c.opt.DefaultTTL)
+}
+
+// SetWithTTL stores value under key, expiring after ttl (0 means never)
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		e := el.Value.(*entry[K, V])
+		e.value, e.expiresAt = value, expiresAt
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.items[key] = c.lru.PushFront(&entry[K, V]{key: key, value: value, expiresAt: expiresAt})
+	for c.opt.MaxEntries > 0 && c.lru.Len() > c.opt.MaxEntries {
+		c.removeElement(c.lru.Back())
+		c.evictions.Add(1)
+	}
+}
+
+// Delete removes key from the cache
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[key]; ok {
+		c.removeElement(el)
+	}
+}
+
+// Len returns the number of entries, including expired ones not yet purged
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// Clear removes all entries
+func (c *Cache[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items = make(map[K]*list.Element)
+	c.lru.Init()
+}
+
+// GetOrLoad returns the cached value for key, or calls load to produce it and caches it with the
+// default TTL. Concurrent calls for the same missing key share a single load (singleflight),
+// preventing cache stampedes. Errors are returned to every waiter and are not cached
+// load runs without the cancellation of the caller that started it, every caller stops waiting
+// with ctx.Err() once its own ctx is done; a panic in load is returned as an error
+func (c *Cache[K, V]) GetOrLoad(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	c.mu.Lock()
+	// Another loader may have stored the value since Get
+	if v, ok := c.peek(key); ok {
+		c.mu.Unlock()
+		return v, nil
+	}
+	cl, ok := c.inflight[key]
+	if !ok {
+		cl = &call[V]{done: make(chan struct{})}
+		c.inflight[key] = cl
+		go c.load(context.WithoutCancel(ctx), key, cl, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-cl.done:
+		return cl.value, cl.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// load runs a GetOrLoad call, caching its value and waking the waiters
+func (c *Cache[K, V]) load(ctx context.Context, key K, cl *call[V], load func(ctx context.Context) (V, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			cl.err = fmt.Errorf("cachex: load panicked: %v", r)
+		}
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	cl.value, cl.err = load(ctx)
+	if cl.err == nil {
+		c.Set(key, cl.value)
+	}
+}
+
+// Stats returns a snapshot of the cache counters
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Entries:   c.Len(),
+	}
+}
+
+// DeleteExpired purges all expired entries
+func (c *Cache[K, V]) DeleteExpired() {
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for el := c.lru.Back(); el != nil; {
+		prev := el.Prev()
+		if el.Value.(*entry[K, V]).expired(now) {
+			c.removeElement(el)
+		}
+		el = prev
+	}
+}
+
+// Close stops the janitor goroutine, the cache remains usable
+func (c *Cache[K, V]) Close() {
+	c.closeOnce.Do(func() { close(c.stop) })
+}
+
+func (c *Cache[K, V]) janitor(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.DeleteExpired()
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// removeElement unlinks an entry, c.mu must be held
+func (c *Cache[K, V]) removeElement(el *list.Element) {
+	c.lru.Remove(el)
+	delete(c.items, el.Value.(*entry[K, V]).key)
+}
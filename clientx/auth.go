@@ -0,0 +1,11 @@
+package clientx
+
+// WithBasicAuth sets the Authorization header for HTTP Basic authentication
+func WithBasicAuth(username, password string) OptionFunc {
+	return WithHeaders(map[string]string{"Authorization": "Basic " + basicAuth(username, password)})
+}
+
+// WithBearerToken sets the Authorization header to a Bearer token
+func WithBearerToken(token string) OptionFunc {
+	return WithHeaders(map[string]string{"Authorization": "Bearer " + token})
+}
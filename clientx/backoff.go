@@ -0,0 +1,77 @@
+package clientx
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// ConstantBackoff waits d between every retry
+func ConstantBackoff(d time.Duration) BackoffFunc {
+	return func(int) time.Duration { return d }
+}
+
+// LinearBackoff waits base, 2*base, 3*base... capped at maxDelay
+func LinearBackoff(base, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return capDelay(base*time.Duration(attempt+1), base, maxDelay)
+	}
+}
+
+// ExponentialJitter doubles the delay each attempt up to maxDelay and randomizes its upper half
+// ("equal jitter"), so at least half of the exponential delay is always waited
+func ExponentialJitter(base, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := exponential(attempt, base, maxDelay)
+		return d/2 + randDuration(d-d/2)
+	}
+}
+
+// FullJitter picks a random delay between 0 and the exponential delay capped at maxDelay
+// It spreads retries the most and suits many clients failing at the same time
+func FullJitter(base, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		return randDuration(exponential(attempt, base, maxDelay))
+	}
+}
+
+// DecorrelatedJitter picks a random delay between base and three times the previous upper bound,
+// capped at maxDelay. BackoffFunc is shared by concurrent requests, so the previous delay is not
+// tracked and the upper bound grows as base*3^attempt
+func DecorrelatedJitter(base, maxDelay time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		upper := base
+		for i := 0; i < attempt && upper < maxDelay; i++ {
+			upper *= 3
+		}
+		upper = min(upper, maxDelay)
+		if upper <= base {
+			return upper
+		}
+		return base + randDuration(upper-base)
+	}
+}
+
+// exponential returns base*2^attempt capped at maxDelay without overflowing
+func exponential(attempt int, base, maxDelay time.Duration) time.Duration {
+	d := base
+	for i := 0; i < attempt && d < maxDelay; i++ {
+		d *= 2
+	}
+	return capDelay(d, base, maxDelay)
+}
+
+// capDelay limits d to maxDelay, treating overflowed values as maxDelay
+func capDelay(d, base, maxDelay time.Duration) time.Duration {
+	if d > maxDelay || d < base {
+		return maxDelay
+	}
+	return d
+}
+
+// randDuration returns a random duration in [0, d)
+func randDuration(d time.Duration) time.Duration {
+	if d <= 0 {
+		return 0
+	}
+	return rand.N(d)
+}
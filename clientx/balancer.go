@@ -0,0 +1,213 @@
+package clientx
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BalanceStrategy chooses the endpoint of each attempt
+type BalanceStrategy int
+
+const (
+	RoundRobin BalanceStrategy = iota // Endpoints take turns
+	Random                            // Endpoints are picked uniformly at random
+	Weighted                          // Endpoints take turns in proportion to their weights (smooth weighted round robin)
+)
+
+// BalancerOption load balancer configuration structure
+type BalancerOption struct {
+	Strategy     BalanceStrategy // Default RoundRobin
+	Weights      []int           // Weight of each endpoint for Weighted, default 1
+	MaxFailures  int             // Consecutive failures that eject an endpoint, default 3
+	EjectionTime time.Duration   // Time an ejected endpoint receives no requests, default 30s
+}
+
+// BalancerOptionFunc functional load balancer configuration type
+type BalancerOptionFunc func(*BalancerOption)
+
+// WithStrategy sets how endpoints are chosen
+func WithStrategy(s BalanceStrategy) BalancerOptionFunc {
+	return func(o *BalancerOption) { o.Strategy = s }
+}
+
+// WithWeights selects the Weighted strategy, weights are given in the order of the endpoints
+func WithWeights(weights ...int) BalancerOptionFunc {
+	return func(o *BalancerOption) {
+		o.Strategy = Weighted
+		o.Weights = weights
+	}
+}
+
+// WithEjection ejects an endpoint for d after maxFailures consecutive failed attempts
+func WithEjection(maxFailures int, d time.Duration) BalancerOptionFunc {
+	return func(o *BalancerOption) {
+		o.MaxFailures = maxFailures
+		o.EjectionTime = d
+	}
+}
+
+// endpoint is a replica and its health
+type endpoint struct {
+	url          *url.URL
+	weight       int
+	current      int       // Smooth weighted round robin state
+	failures     int       // Consecutive failed attempts
+	ejectedUntil time.Time // Zero while healthy
+}
+
+// Balancer routes requests across replicas of a service and ejects failing ones for a while
+// Attempts answered with a transport error or a 5xx status count as failures, so retries
+// move on to another replica
+type Balancer struct {
+	mu        sync.Mutex
+	opt       BalancerOption
+	endpoints []*endpoint
+	next      atomic.Uint64
+	err       error
+}
+
+// NewBalancer creates a balancer over the base URLs of the replicas
+func NewBalancer(urls []string, opts ...BalancerOptionFunc) *Balancer {
+	b := &Balancer{opt: BalancerOption{MaxFailures: 3, EjectionTime: 30 * time.Second}}
+	for _, opt := range opts {
+		opt(&b.opt)
+	}
+	if len(urls) == 0 {
+		b.err = errors.New("clientx: balancer has no endpoints")
+	}
+	for i, raw := range urls {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			b.err = errors.Join(b.err, fmt.Errorf("clientx: invalid endpoint %q", raw))
+			continue
+		}
+		weight := 1
+		if i < len(b.opt.Weights) && b.opt.Weights[i] > 0 {
+			weight = b.opt.Weights[i]
+		}
+		b.endpoints = append(b.endpoints, &endpoint{url: u, weight: weight})
+	}
+	return b
+}
+
+// WithEndpoints sends every request to one of the base URLs, round robin with ejection of
+// failing replicas; request URLs are given as paths ("/users") or as full URLs whose scheme
+// and host are replaced. Use WithBalancer for other strategies
+func WithEndpoints(urls ...string) OptionFunc {
+	return WithBalancer(NewBalancer(urls))
+}
+
+// WithBalancer routes every request through b
+func WithBalancer(b *Balancer) OptionFunc {
+	return func(o *Option) {
+		if b.err != nil {
+			o.err = errors.Join(o.err, b.err)
+			return
+		}
+		o.Balancer = b
+	}
+}
+
+// pick chooses a healthy endpoint other than those in skip; when all are ejected the
+// ejection is ignored rather than failing every request
+func (b *Balancer) pick(skip map[*endpoint]bool) *endpoint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	healthy := make([]*endpoint, 0, len(b.endpoints))
+	for _, e := range b.endpoints {
+		if !skip[e] && !now.Before(e.ejectedUntil) {
+			healthy = append(healthy, e)
+		}
+	}
+	if len(healthy) == 0 {
+		for _, e := range b.endpoints {
+			if !skip[e] {
+				healthy = append(healthy, e)
+			}
+		}
+	}
+	if len(healthy) == 0 {
+		return nil
+	}
+	switch b.opt.Strategy {
+	case Random:
+		return healthy[rand.IntN(len(healthy))]
+	case Weighted:
+		total := 0
+		var best *endpoint
+		for _, e := range healthy {
+			e.current += e.weight
+			total += e.weight
+			if best == nil || e.current > best.current {
+				best = e
+			}
+		}
+		best.current -= total
+		return best
+	default:
+		return healthy[int((b.next.Add(1)-1)%uint64(len(healthy)))]
+	}
+}
+
+// report records the outcome of an attempt sent to e
+func (b *Balancer) report(e *endpoint, success bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if success {
+		e.failures = 0
+		return
+	}
+	e.failures++
+	if b.opt.MaxFailures > 0 && e.failures >= b.opt.MaxFailures {
+		e.ejectedUntil = time.Now().Add(b.opt.EjectionTime)
+		e.failures = 0
+	}
+}
+
+// target rewrites u onto the base URL of e, keeping the base path as a prefix
+func (e *endpoint) target(u *url.URL) *url.URL {
+	t := *u
+	t.Scheme, t.Host, t.User = e.url.Scheme, e.url.Host, e.url.User
+	if base := strings.TrimSuffix(e.url.Path, "/"); base != "" {
+		t.Path = base + "/" + strings.TrimPrefix(u.Path, "/")
+		t.RawPath = ""
+	}
+	return &t
+}
+
+// balancerMiddleware sends each attempt to an endpoint of b
+// An endpoint whose circuit breaker is open is skipped for the next one
+func balancerMiddleware(b *Balancer) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			skip := make(map[*endpoint]bool)
+			for {
+				e := b.pick(skip)
+				if e == nil {
+					return nil, ErrCircuitOpen // Every endpoint was skipped
+				}
+				r := req.Clone(req.Context())
+				r.URL = e.target(req.URL)
+				r.Host = ""
+				resp, err := next(r)
+				if errors.Is(err, ErrCircuitOpen) {
+					skip[e] = true
+					continue
+				}
+				if req.Context().Err() == nil {
+					// Cancelled attempts, e.g. hedging losers, say nothing about the endpoint
+					b.report(e, err == nil && resp.StatusCode < 500)
+				}
+				return resp, err
+			}
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package clientx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// Req is one request of a Batch
+type Req struct {
+	Method  string       // HTTP method, default GET
+	URL     string       // Request URL
+	Body    []byte       // Request body
+	Options []OptionFunc // Options of this request, e.g. its retry policy
+}
+
+// Result is the outcome of one request of a Batch, exactly one field is set
+type Result struct {
+	Response *Response // Response with its body read
+	Err      error     // Error of the request after its retries
+}
+
+// Batch sends requests concurrently, at most concurrency at a time (0 means all at once), each
+// one with its own options and retries like Request. Results are in the order of requests and
+// bodies are read, so connections are freed as soon as a request completes. The returned error
+// joins the errors of the failed requests, each prefixed with its index; requests not started
+// when ctx ends fail with the error of ctx
+func Batch(ctx context.Context, requests []Req, concurrency int) ([]Result, error) {
+	return Default().Batch(ctx, requests, concurrency)
+}
+
+// Batch sends requests through c, see the package-level Batch
+func (c *Client) Batch(ctx context.Context, requests []Req, concurrency int) ([]Result, error) {
+	results := make([]Result, len(requests))
+	if concurrency <= 0 || concurrency > len(requests) {
+		concurrency = len(requests)
+	}
+	var (
+		wg   sync.WaitGroup
+		next atomic.Int64
+	)
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				i := int(next.Add(1) - 1)
+				if i >= len(requests) {
+					return
+				}
+				results[i] = c.batchOne(ctx, requests[i])
+			}
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, r := range results {
+		if r.Err != nil {
+			errs = append(errs, fmt.Errorf("request %d: %w", i, r.Err))
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// batchOne sends one request of a Batch and reads its body
+func (c *Client) batchOne(ctx context.Context, r Req) Result {
+	if err := ctx.Err(); err != nil {
+		return Result{Err: err}
+	}
+	method := r.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	resp, err := readResponse(c.Request(ctx, method, r.URL, r.Body, r.Options...))
+	if err != nil {
+		return Result{Err: err}
+	}
+	return Result{Response: resp}
+}
@@ -0,0 +1,87 @@
+package clientx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+)
+
+// ErrBodyNotReplayable is returned when a retry needs the body of ReaderBody a second time
+var ErrBodyNotReplayable = errors.New("clientx: request body cannot be replayed")
+
+// BodyFunc returns the request body of one attempt
+// It is called again for every retry, so it must return a fresh reader each time
+// Readers implementing io.Closer are closed once sent
+type BodyFunc func() (io.Reader, error)
+
+// BytesBody returns a BodyFunc replaying b, nil for an empty body
+func BytesBody(b []byte) BodyFunc {
+	if b == nil {
+		return nil
+	}
+	return func() (io.Reader, error) { return bytes.NewReader(b), nil }
+}
+
+// FileBody returns a BodyFunc opening the file at path for every attempt, sent with its size
+func FileBody(path string) BodyFunc {
+	return func() (io.Reader, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, err
+		}
+		return &sizedReader{ReadCloser: f, size: info.Size()}, nil
+	}
+}
+
+// sizedReader is a body whose length is known up front
+type sizedReader struct {
+	io.ReadCloser
+	size int64
+}
+
+// Size returns the body length
+func (r *sizedReader) Size() int64 {
+	return r.size
+}
+
+// ReaderBody returns a BodyFunc yielding r once, a retry then fails with ErrBodyNotReplayable
+// Use it for streams that cannot be re-read, e.g. a pipe or a request body being proxied
+func ReaderBody(r io.Reader) BodyFunc {
+	var once sync.Once
+	return func() (io.Reader, error) {
+		err := ErrBodyNotReplayable
+		once.Do(func() { err = nil })
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+}
+
+// WithContentLength sets the length of a streamed body, which is otherwise sent chunked
+// unless the reader reports its size (bytes.Reader, strings.Reader)
+func WithContentLength(n int64) OptionFunc {
+	return func(o *Option) { o.ContentLength = n }
+}
+
+// RequestReader sends a request whose body is obtained from body before every attempt
+func RequestReader(ctx context.Context, method, url string, body BodyFunc, opts ...OptionFunc) (*http.Response, error) {
+	return Default().RequestReader(ctx, method, url, body, opts...)
+}
+
+// readCloser adds a no-op Close to readers that lack one
+func readCloser(r io.Reader) io.ReadCloser {
+	if rc, ok := r.(io.ReadCloser); ok {
+		return rc
+	}
+	return io.NopCloser(r)
+}
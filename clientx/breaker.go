@@ -0,0 +1,248 @@
+package clientx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request while the breaker of its host is open
+var ErrCircuitOpen = errors.New("clientx: circuit breaker is open")
+
+// CircuitBreaker decides whether requests to a host may be sent
+// Allow returns a done callback that must be called with the outcome of an admitted request
+type CircuitBreaker interface {
+	Allow(host string) (done func(Outcome), err error)
+}
+
+// Outcome is the result of an admitted request reported to a CircuitBreaker
+type Outcome int
+
+const (
+	OutcomeSuccess Outcome = iota // The host answered with a status below 500
+	OutcomeFailure                // Transport error or 5xx response
+	OutcomeIgnored                // Cancelled by the caller or panicked, says nothing about the host
+)
+
+// BreakerState is the state of the breaker of one host
+type BreakerState int
+
+const (
+	StateClosed   BreakerState = iota // Requests flow, outcomes are counted
+	StateOpen                         // Requests fail fast until the cool-down has passed
+	StateHalfOpen                     // A limited number of probes decide between closed and open
+)
+
+// String returns the state name
+func (s BreakerState) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// BreakerOption circuit breaker configuration structure
+type BreakerOption struct {
+	FailureRatio  float64                                  // Failure rate that opens the breaker, default 0.5
+	MinRequests   int                                      // Requests needed in a window before the rate is evaluated, default 10
+	Window        time.Duration                            // Length of the counting window, default 10s
+	CoolDown      time.Duration                            // Time spent open before probing, default 30s
+	HalfOpenMax   int                                      // Concurrent probes allowed while half-open, default 1
+	OnStateChange func(host string, from, to BreakerState) // Called on every transition with the breaker locked
+}
+
+// BreakerOptionFunc functional breaker configuration type
+type BreakerOptionFunc func(*BreakerOption)
+
+// WithFailureRatio sets the failure rate and minimum volume that open the breaker
+func WithFailureRatio(ratio float64, minRequests int) BreakerOptionFunc {
+	return func(o *BreakerOption) {
+		o.FailureRatio = ratio
+		o.MinRequests = minRequests
+	}
+}
+
+// WithWindow sets the length of the counting window
+func WithWindow(d time.Duration) BreakerOptionFunc {
+	return func(o *BreakerOption) { o.Window = d }
+}
+
+// WithCoolDown sets how long the breaker stays open before probing the host again
+func WithCoolDown(d time.Duration) BreakerOptionFunc {
+	return func(o *BreakerOption) { o.CoolDown = d }
+}
+
+// WithHalfOpenMax sets the number of concurrent probes while half-open
+func WithHalfOpenMax(n int) BreakerOptionFunc {
+	return func(o *BreakerOption) { o.HalfOpenMax = n }
+}
+
+// WithStateChange registers a callback for breaker transitions, e.g. for logging or metrics
+func WithStateChange(fn func(host string, from, to BreakerState)) BreakerOptionFunc {
+	return func(o *BreakerOption) { o.OnStateChange = fn }
+}
+
+// Breaker is the default CircuitBreaker, keeping an independent state per host
+type Breaker struct {
+	opt   BreakerOption
+	mu    sync.Mutex
+	hosts map[string]*hostBreaker
+}
+
+// hostBreaker is the state of one host, guarded by Breaker.mu
+type hostBreaker struct {
+	state       BreakerState
+	windowStart time.Time
+	requests    int
+	failures    int
+	openedAt    time.Time
+	probes      int // Probes in flight while half-open
+}
+
+// NewBreaker creates a per-host circuit breaker
+func NewBreaker(opts ...BreakerOptionFunc) *Breaker {
+	o := BreakerOption{FailureRatio: 0.5, MinRequests: 10, Window: 10 * time.Second, CoolDown: 30 * time.Second, HalfOpenMax: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return &Breaker{opt: o, hosts: make(map[string]*hostBreaker)}
+}
+
+// Allow implements CircuitBreaker
+func (b *Breaker) Allow(host string) (func(Outcome), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	h := b.host(host)
+	now := time.Now()
+
+	switch h.state {
+	case StateOpen:
+		if now.Sub(h.openedAt) < b.opt.CoolDown {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+		}
+		b.transition(host, h, StateHalfOpen)
+		fallthrough
+	case StateHalfOpen:
+		if h.probes >= max(b.opt.HalfOpenMax, 1) {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+		}
+		h.probes++
+		return b.doneFunc(host, h, true), nil
+	default:
+		if now.Sub(h.windowStart) >= b.opt.Window {
+			h.windowStart, h.requests, h.failures = now, 0, 0
+		}
+		return b.doneFunc(host, h, false), nil
+	}
+}
+
+// State returns the current state of the breaker of host
+func (b *Breaker) State(host string) BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if h, ok := b.hosts[host]; ok {
+		return h.state
+	}
+	return StateClosed
+}
+
+// Reset closes the breaker of every host
+func (b *Breaker) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hosts = make(map[string]*hostBreaker)
+}
+
+func (b *Breaker) host(host string) *hostBreaker {
+	h, ok := b.hosts[host]
+	if !ok {
+		h = &hostBreaker{windowStart: time.Now()}
+		b.hosts[host] = h
+	}
+	return h
+}
+
+// doneFunc records the outcome of one admitted request, extra calls are ignored
+func (b *Breaker) doneFunc(host string, h *hostBreaker, probe bool) func(Outcome) {
+	var once sync.Once
+	return func(outcome Outcome) {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			if probe {
+				h.probes--
+				if h.state != StateHalfOpen || outcome == OutcomeIgnored {
+					return // An ignored probe only frees its slot
+				}
+				if outcome == OutcomeSuccess {
+					h.windowStart, h.requests, h.failures = time.Now(), 0, 0
+					b.transition(host, h, StateClosed)
+				} else {
+					h.openedAt = time.Now()
+					b.transition(host, h, StateOpen)
+				}
+				return
+			}
+			if h.state != StateClosed || outcome == OutcomeIgnored {
+				return
+			}
+			h.requests++
+			if outcome == OutcomeFailure {
+				h.failures++
+			}
+			if h.requests >= b.opt.MinRequests && float64(h.failures)/float64(h.requests) >= b.opt.FailureRatio {
+				h.openedAt = time.Now()
+				b.transition(host, h, StateOpen)
+			}
+		})
+	}
+}
+
+func (b *Breaker) transition(host string, h *hostBreaker, to BreakerState) {
+	from := h.state
+	h.state = to
+	if b.opt.OnStateChange != nil && from != to {
+		b.opt.OnStateChange(host, from, to)
+	}
+}
+
+// WithCircuitBreaker guards requests with cb, keyed by the request host
+// Transport errors and 5xx responses count as failures, attempts cancelled by the caller (timeouts
+// included) are not counted; while open, requests fail with ErrCircuitOpen
+func WithCircuitBreaker(cb CircuitBreaker) OptionFunc {
+	return func(o *Option) { o.CircuitBreaker = cb }
+}
+
+// breakerMiddleware reports the outcome of each attempt to cb
+func breakerMiddleware(cb CircuitBreaker) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			done, err := cb.Allow(req.URL.Host)
+			if err != nil {
+				return nil, err
+			}
+			// Reported even if next panics, a half-open breaker would otherwise keep the probe forever
+			outcome := OutcomeIgnored
+			defer func() { done(outcome) }()
+			resp, err := next(req)
+			switch {
+			case err != nil && (req.Context().Err() != nil || errors.Is(err, context.Canceled)):
+				// The caller gave up, this says nothing about the host
+			case err != nil || resp.StatusCode >= http.StatusInternalServerError:
+				outcome = OutcomeFailure
+			default:
+				outcome = OutcomeSuccess
+			}
+			return resp, err
+		}
+	}
+}
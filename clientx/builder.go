@@ -0,0 +1,138 @@
+package clientx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"slices"
+	"time"
+)
+
+// RequestBuilder builds a request step by step, the chained alternative to passing OptionFuncs:
+//
+//	resp, err := clientx.NewRequest(ctx).Method("POST").URL(u).Query("page", 1).
+//		Header("X-Request-Id", id).JSONBody(v).Retry(5).Do()
+//
+// Errors of the building steps are reported by Do and Send
+type RequestBuilder struct {
+	client  *Client
+	ctx     context.Context
+	method  string
+	url     string
+	body    BodyFunc
+	query   url.Values
+	headers map[string]string
+	opts    []OptionFunc
+	err     error
+}
+
+// NewRequest starts building a request sent by the default client
+func NewRequest(ctx context.Context) *RequestBuilder {
+	return Default().NewRequest(ctx)
+}
+
+// NewRequest starts building a request sent through c, with c's defaults
+func (c *Client) NewRequest(ctx context.Context) *RequestBuilder {
+	return &RequestBuilder{
+		client:  c,
+		ctx:     ctx,
+		method:  http.MethodGet,
+		query:   make(url.Values),
+		headers: make(map[string]string),
+	}
+}
+
+// Method sets the request method, GET by default
+func (b *RequestBuilder) Method(method string) *RequestBuilder {
+	b.method = method
+	return b
+}
+
+// URL sets the request URL, parameters added by Query are merged into its query
+func (b *RequestBuilder) URL(url string) *RequestBuilder {
+	b.url = url
+	return b
+}
+
+// Query adds a query parameter, slices add the parameter once per element
+func (b *RequestBuilder) Query(key string, value any) *RequestBuilder {
+	if err := encodeField(b.query, key, reflect.ValueOf(value), fieldOptions{}); err != nil {
+		b.err = errors.Join(b.err, err)
+	}
+	return b
+}
+
+// Header sets a request header
+func (b *RequestBuilder) Header(key, value string) *RequestBuilder {
+	b.headers[key] = value
+	return b
+}
+
+// Body sets the raw request body
+func (b *RequestBuilder) Body(body []byte) *RequestBuilder {
+	b.body = BytesBody(body)
+	return b
+}
+
+// BodyFunc sets a request body obtained before every attempt, see RequestReader
+func (b *RequestBuilder) BodyFunc(body BodyFunc) *RequestBuilder {
+	b.body = body
+	return b
+}
+
+// JSONBody serializes v as the request body and sets Content-Type to application/json
+func (b *RequestBuilder) JSONBody(v any) *RequestBuilder {
+	data, err := json.Marshal(v)
+	if err != nil {
+		b.err = errors.Join(b.err, fmt.Errorf("JSON serialization failed: %w", err))
+		return b
+	}
+	b.headers["Content-Type"] = "application/json"
+	return b.Body(data)
+}
+
+// FormBody sets an application/x-www-form-urlencoded request body
+func (b *RequestBuilder) FormBody(form url.Values) *RequestBuilder {
+	b.headers["Content-Type"] = "application/x-www-form-urlencoded"
+	return b.Body([]byte(form.Encode()))
+}
+
+// Retry sets the maximum number of retries
+func (b *RequestBuilder) Retry(n int) *RequestBuilder {
+	return b.Option(WithRetries(n))
+}
+
+// Timeout bounds the whole call, retries included, see WithRequestTimeout
+func (b *RequestBuilder) Timeout(d time.Duration) *RequestBuilder {
+	return b.Option(WithRequestTimeout(d))
+}
+
+// Option applies OptionFuncs for everything the builder has no method for
+func (b *RequestBuilder) Option(opts ...OptionFunc) *RequestBuilder {
+	b.opts = append(b.opts, opts...)
+	return b
+}
+
+// Send sends the request and returns the raw response, whose body the caller must close
+func (b *RequestBuilder) Send() (*http.Response, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.url == "" {
+		return nil, errors.New("clientx: request has no URL")
+	}
+	opts := append(slices.Clip(b.opts), WithHeaders(b.headers))
+	if len(b.query) > 0 {
+		opts = append(opts, WithQuery(b.query))
+	}
+	return b.client.RequestReader(b.ctx, b.method, b.url, b.body, opts...)
+}
+
+// Do sends the request and reads the whole response into a Response
+func (b *RequestBuilder) Do() (*Response, error) {
+	return readResponse(b.Send())
+}
@@ -0,0 +1,327 @@
+package clientx
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/chihqiang/gox/cachex"
+	"github.com/chihqiang/gox/filex"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CacheStore stores cached responses for WithCache
+type CacheStore interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, value []byte)
+	Delete(key string)
+}
+
+// cacheHeader is set on responses served from the cache
+const cacheHeader = "X-From-Cache"
+
+// WithCache caches GET responses in store following HTTP caching rules (RFC 9111) for a
+// private cache: fresh responses are served without a request, stale ones with an ETag or
+// Last-Modified are revalidated and served again on 304. Successful unsafe requests
+// invalidate the entry of their URL. Requests with Authorization or Cookie headers are cached
+// per credential, cookies added by a cookie jar are not seen, so sessions should not share a
+// store. Responses served from the cache carry X-From-Cache: 1
+func WithCache(store CacheStore) OptionFunc {
+	return func(o *Option) { o.Cache = store }
+}
+
+// memoryCache is a CacheStore backed by cachex.Cache
+type memoryCache struct {
+	c *cachex.Cache[string, []byte]
+}
+
+// NewMemoryCache returns an in-memory CacheStore holding up to maxEntries responses
+// (0 means unlimited), least recently used ones are evicted first
+func NewMemoryCache(maxEntries int) CacheStore {
+	return memoryCache{c: cachex.New[string, []byte](cachex.WithMaxEntries(maxEntries))}
+}
+
+func (m memoryCache) Get(key string) ([]byte, bool) { return m.c.Get(key) }
+func (m memoryCache) Set(key string, value []byte)  { m.c.Set(key, value) }
+func (m memoryCache) Delete(key string)             { m.c.Delete(key) }
+
+// diskCache is a CacheStore keeping one file per response
+type diskCache struct {
+	dir string
+}
+
+// NewDiskCache returns a CacheStore keeping responses as files in dir, created if missing
+func NewDiskCache(dir string) CacheStore {
+	return diskCache{dir: dir}
+}
+
+func (d diskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, hex.EncodeToString(sum[:]))
+}
+
+// Get implements CacheStore
+func (d diskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(d.path(key))
+	return data, err == nil
+}
+
+// Set implements CacheStore, write errors only cost a cache miss
+func (d diskCache) Set(key string, value []byte) {
+	if err := filex.EnsureDir(d.dir); err == nil {
+		_ = filex.AtomicWrite(d.path(key), value)
+	}
+}
+
+// Delete implements CacheStore
+func (d diskCache) Delete(key string) {
+	_ = os.Remove(d.path(key))
+}
+
+// cacheEntry is a stored response
+type cacheEntry struct {
+	Stored   time.Time         `json:"stored"`   // When the response was received or last revalidated
+	Vary     map[string]string `json:"vary"`     // Request headers named by Vary, with their values
+	Response []byte            `json:"response"` // The response in wire format
+}
+
+// cacheMiddleware serves GET requests from store and stores cacheable responses
+func cacheMiddleware(store CacheStore) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			key := cacheKey(req)
+			if req.Method != http.MethodGet {
+				resp, err := next(req)
+				if err == nil && req.Method != http.MethodHead && req.Method != http.MethodOptions &&
+					req.Method != http.MethodTrace && resp.StatusCode < 400 {
+					store.Delete(key)
+				}
+				return resp, err
+			}
+			reqCC := parseCacheControl(req.Header)
+			// Conditional requests of the caller expect the raw answer
+			if _, ok := reqCC["no-store"]; ok || req.Header.Get("If-None-Match") != "" ||
+				req.Header.Get("If-Modified-Since") != "" || req.Header.Get("Range") != "" {
+				return next(req)
+			}
+
+			entry, cached := loadCacheEntry(store, key, req)
+			if cached != nil {
+				_, noCache := reqCC["no-cache"]
+				if !noCache && isFresh(cached, entry.Stored, reqCC) {
+					cached.Header.Set(cacheHeader, "1")
+					return cached, nil
+				}
+				etag, lastModified := cached.Header.Get("ETag"), cached.Header.Get("Last-Modified")
+				if etag != "" || lastModified != "" {
+					cond := req.Clone(req.Context())
+					if etag != "" {
+						cond.Header.Set("If-None-Match", etag)
+					}
+					if lastModified != "" {
+						cond.Header.Set("If-Modified-Since", lastModified)
+					}
+					resp, err := next(cond)
+					if err != nil {
+						return nil, err
+					}
+					if resp.StatusCode == http.StatusNotModified {
+						_ = resp.Body.Close()
+						// Headers of the 304 replace the stored ones
+						for name, values := range resp.Header {
+							if name != "Content-Length" {
+								cached.Header[name] = values
+							}
+						}
+						entry.Stored = time.Now()
+						storeCacheEntry(store, key, entry, cached, nil)
+						cached.Header.Set(cacheHeader, "1")
+						return cached, nil
+					}
+					return cacheResponse(store, key, req, resp), nil
+				}
+			}
+
+			resp, err := next(req)
+			if err != nil {
+				return nil, err
+			}
+			return cacheResponse(store, key, req, resp), nil
+		}
+	}
+}
+
+// cacheKey is the URL of req, followed by a digest of its credentials when it has any so that
+// responses are never served to another user
+func cacheKey(req *http.Request) string {
+	auth, cookie := req.Header.Get("Authorization"), req.Header.Get("Cookie")
+	if auth == "" && cookie == "" {
+		return req.URL.String()
+	}
+	sum := sha256.Sum256([]byte(auth + "\n" + cookie))
+	return req.URL.String() + " " + hex.EncodeToString(sum[:])
+}
+
+// loadCacheEntry returns the stored response for req, nil if missing or varying
+func loadCacheEntry(store CacheStore, key string, req *http.Request) (*cacheEntry, *http.Response) {
+	data, ok := store.Get(key)
+	if !ok {
+		return nil, nil
+	}
+	entry := new(cacheEntry)
+	if err := json.Unmarshal(data, entry); err != nil {
+		return nil, nil
+	}
+	for name, value := range entry.Vary {
+		if req.Header.Get(name) != value {
+			return nil, nil
+		}
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(entry.Response)), req)
+	if err != nil {
+		return nil, nil
+	}
+	return entry, resp
+}
+
+// storeCacheEntry writes resp with the given body (read from resp when nil) to store
+func storeCacheEntry(store CacheStore, key string, entry *cacheEntry, resp *http.Response, body []byte) {
+	if body == nil {
+		var err error
+		if body, err = io.ReadAll(resp.Body); err != nil {
+			return
+		}
+		_ = resp.Body.Close()
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	stored := *resp
+	stored.Body = io.NopCloser(bytes.NewReader(body))
+	stored.ContentLength = int64(len(body))
+	stored.TransferEncoding = nil
+	stored.Header = resp.Header.Clone()
+	stored.Header.Del(cacheHeader)
+	data, err := httputil.DumpResponse(&stored, true)
+	if err != nil {
+		return
+	}
+	entry.Response = data
+	if data, err = json.Marshal(entry); err == nil {
+		store.Set(key, data)
+	}
+}
+
+// cacheableStatus lists the statuses stored by the cache
+var cacheableStatus = map[int]bool{
+	http.StatusOK: true, http.StatusNonAuthoritativeInfo: true, http.StatusNoContent: true,
+	http.StatusMultipleChoices: true, http.StatusMovedPermanently: true, http.StatusNotFound: true,
+	http.StatusMethodNotAllowed: true, http.StatusGone: true, http.StatusRequestURITooLong: true,
+	http.StatusNotImplemented: true, http.StatusPermanentRedirect: true,
+}
+
+// cacheResponse arranges for resp to be stored once its body has been read to the end
+func cacheResponse(store CacheStore, key string, req *http.Request, resp *http.Response) *http.Response {
+	cc := parseCacheControl(resp.Header)
+	_, noStore := cc["no-store"]
+	vary := resp.Header.Values("Vary")
+	if noStore || !cacheableStatus[resp.StatusCode] || strings.Contains(strings.Join(vary, ","), "*") {
+		store.Delete(key)
+		return resp
+	}
+	_, maxAge := cc["max-age"]
+	_, noCache := cc["no-cache"]
+	if !maxAge && !noCache && resp.Header.Get("Expires") == "" &&
+		resp.Header.Get("ETag") == "" && resp.Header.Get("Last-Modified") == "" {
+		return resp // Neither fresh nor revalidatable
+	}
+
+	entry := &cacheEntry{Stored: time.Now(), Vary: make(map[string]string)}
+	for _, v := range vary {
+		for _, name := range strings.Split(v, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				entry.Vary[http.CanonicalHeaderKey(name)] = req.Header.Get(name)
+			}
+		}
+	}
+	resp.Body = &cachingBody{ReadCloser: resp.Body, done: func(body []byte) {
+		storeCacheEntry(store, key, entry, resp, body)
+	}}
+	return resp
+}
+
+// cachingBody buffers a body and hands it to done once read to the end
+type cachingBody struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	done func(body []byte)
+}
+
+// Read implements io.Reader
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.buf.Write(p[:n])
+	if err == io.EOF && b.done != nil {
+		b.done(b.buf.Bytes())
+		b.done = nil
+	}
+	return n, err
+}
+
+// isFresh reports whether the response stored at stored may be served without revalidation
+func isFresh(resp *http.Response, stored time.Time, reqCC map[string]string) bool {
+	cc := parseCacheControl(resp.Header)
+	if _, ok := cc["no-cache"]; ok {
+		return false
+	}
+	date, err := http.ParseTime(resp.Header.Get("Date"))
+	if err != nil {
+		date = stored
+	}
+	age := time.Since(stored)
+	if v, err := strconv.Atoi(resp.Header.Get("Age")); err == nil && v > 0 {
+		age += time.Duration(v) * time.Second
+	}
+
+	var lifetime time.Duration
+	if v, ok := cc["max-age"]; ok {
+		seconds, _ := strconv.Atoi(v)
+		lifetime = time.Duration(seconds) * time.Second
+	} else if expires := resp.Header.Get("Expires"); expires != "" {
+		t, err := http.ParseTime(expires)
+		if err != nil {
+			return false // Invalid Expires means already expired
+		}
+		lifetime = t.Sub(date)
+	} else if lm, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil && date.After(lm) {
+		// Heuristic freshness, a tenth of the time since the last change
+		lifetime = date.Sub(lm) / 10
+	}
+
+	if v, ok := reqCC["max-age"]; ok {
+		if seconds, err := strconv.Atoi(v); err == nil {
+			lifetime = min(lifetime, time.Duration(seconds)*time.Second)
+		}
+	}
+	return age < lifetime
+}
+
+// parseCacheControl parses Cache-Control directives, names are lower case
+func parseCacheControl(h http.Header) map[string]string {
+	cc := make(map[string]string)
+	for _, v := range h.Values("Cache-Control") {
+		for _, directive := range strings.Split(v, ",") {
+			name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+			if name != "" {
+				cc[strings.ToLower(name)] = strings.Trim(value, `"`)
+			}
+		}
+	}
+	return cc
+}
@@ -1,13 +1,17 @@
 package clientx
 
 import (
-	"bytes"
 	"context"
 	"crypto/tls"
+	"errors"
 	"fmt"
+	"github.com/chihqiang/gox/cryptox"
+	"go.opentelemetry.io/otel/trace"
 	"io"
 	"net/http"
+	"net/url"
 	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -15,65 +19,184 @@ import (
 
 var (
 	mu sync.RWMutex
-	// Custom HTTP client
-	defaultClient = &http.Client{
-		Transport: &http.Transport{
-			// Automatically read proxy from environment variables, e.g., HTTP_PROXY / HTTPS_PROXY
-			Proxy: http.ProxyFromEnvironment,
+	// Client behind the package-level functions
+	std = New()
+)
+
+// newTransport creates the default transport of a Client
+func newTransport() *http.Transport {
+	return &http.Transport{
+		// Proxy set by WithProxy, otherwise read from environment variables, e.g., HTTP_PROXY / HTTPS_PROXY
+		Proxy: proxyFromContext,
+
+		// Answer the Digest challenges of proxies on CONNECT for WithProxyAuth
+		GetProxyConnectHeader:  proxyConnectHeader,
+		OnProxyConnectResponse: proxyConnectResponse,
+
+		// Maximum number of idle connections globally, suitable for high concurrency scenarios
+		MaxIdleConns: 100,
 
-			// Maximum number of idle connections globally, suitable for high concurrency scenarios
-			MaxIdleConns: 100,
+		// Idle connection timeout, close if not used for this duration to release resources
+		IdleConnTimeout: 90 * time.Second,
 
-			// Idle connection timeout, close if not used for this duration to release resources
-			IdleConnTimeout: 90 * time.Second,
+		// TLS handshake timeout, report error if handshake not completed within this time
+		TLSHandshakeTimeout: 10 * time.Second,
 
-			// TLS handshake timeout, report error if handshake not completed within this time
-			TLSHandshakeTimeout: 10 * time.Second,
+		// HTTP/1.1 Expect: 100-continue timeout
+		ExpectContinueTimeout: 1 * time.Second,
 
-			// HTTP/1.1 Expect: 100-continue timeout
-			ExpectContinueTimeout: 1 * time.Second,
+		// Maximum number of idle connections per host, dynamically set based on CPU cores
+		MaxIdleConnsPerHost: runtime.GOMAXPROCS(0) + 1,
 
-			// Maximum number of idle connections per host, dynamically set based on CPU cores
-			MaxIdleConnsPerHost: runtime.GOMAXPROCS(0) + 1,
+		// TLS configuration, certificates are verified against the system pool
+		// Use WithRootCAs, WithClientCert or WithTLSConfig to customize it
+		TLSClientConfig: &tls.Config{MinVersion: tls.VersionTLS12},
 
-			// TLS configuration
-			TLSClientConfig: &tls.Config{
-				// Skip certificate verification
-				InsecureSkipVerify: true,
-				// For security, you can use a custom CA:
-				// RootCAs: x509.NewCertPool()
-			},
+		// Whether to disable Keep-Alive, false means enable TCP connection reuse for better performance
+		DisableKeepAlives: false,
+	}
+}
 
-			// Whether to disable Keep-Alive, false means enable TCP connection reuse for better performance
-			DisableKeepAlives: false,
-		},
+// Client is an HTTP client with its own transport, timeout and default request options
+// Clients are safe for concurrent use; the package-level functions use a shared default Client
+type Client struct {
+	mu     sync.RWMutex
+	client *http.Client
+	opts   []OptionFunc // Defaults applied before the options of every request
+	base   int          // Number of opts passed to New, whose client and transport settings are built in
+}
 
+// New creates a client, opts set its transport and timeout (e.g. WithTimeout, WithMaxIdleConns)
+// as well as defaults for every request made through it (e.g. WithHeaders, WithRetries)
+func New(opts ...OptionFunc) *Client {
+	o := &Option{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	transport := newTransport()
+	applyDialer(transport, o.dialFuncs)
+	for _, fn := range o.transportFuncs {
+		fn(transport)
+	}
+	if o.http2 != nil {
+		configureHTTP2(transport, o.http2)
+	}
+	client := &http.Client{
+		Transport: transport,
 		// Request timeout (including connection, sending request, reading response)
 		Timeout: 10 * time.Second,
 	}
-	// bufferPool for reusing large request bodies
-	bufferPool = sync.Pool{
-		New: func() interface{} {
-			return &bytes.Buffer{}
-		},
+	for _, fn := range o.clientFuncs {
+		fn(client)
 	}
-)
+	return &Client{client: client, opts: opts, base: len(opts)}
+}
+
+// SetDefaultHeaders adds headers sent with every request of c, request headers take precedence
+func (c *Client) SetDefaultHeaders(h map[string]string) {
+	c.SetDefaultOptions(WithHeaders(h))
+}
+
+// SetDefaultOptions adds options applied to every request of c, before the options of the request
+// Transport settings (WithMaxIdleConns, TLS, dial and HTTP/2 options) are only honored by New,
+// requests fail when they are set here
+func (c *Client) SetDefaultOptions(opts ...OptionFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.opts = append(slices.Clip(c.opts), opts...)
+}
+
+// SetDefaultHeaders adds headers sent with every request of the default client
+func SetDefaultHeaders(h map[string]string) {
+	Default().SetDefaultHeaders(h)
+}
+
+// HTTPClient returns the underlying HTTP client
+func (c *Client) HTTPClient() *http.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.client
+}
+
+// SetHTTPClient replaces the underlying HTTP client
+func (c *Client) SetHTTPClient(client *http.Client) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.client = client
+}
+
+// Default returns the client used by the package-level functions
+func Default() *Client {
+	mu.RLock()
+	defer mu.RUnlock()
+	return std
+}
+
+// SetDefault replaces the client used by the package-level functions
+func SetDefault(c *Client) {
+	mu.Lock()
+	defer mu.Unlock()
+	std = c
+}
 
 // Middleware defines middleware type that can execute logic before/after requests
 type Middleware func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error)
 
-// SetClient replaces the global HTTP client
+// chain wraps do with the built-in stages and the middleware chain, the first middleware runs outermost
+func (o *Option) chain(do func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+	if o.Trace != nil {
+		do = traceMiddleware(o.Trace)(do)
+	}
+	if o.Debug != nil {
+		do = debugMiddleware(o.Debug)(do)
+	}
+	if o.HAR != nil {
+		do = harMiddleware(o.HAR)(do)
+	}
+	if o.proxyAuth != nil {
+		do = proxyAuthMiddleware(o.proxyAuth)(do)
+	}
+	if o.Signer != nil {
+		do = signerMiddleware(o.Signer)(do)
+	}
+	if o.Tracer != nil {
+		do = tracingMiddleware(o.Tracer)(do)
+	}
+	if o.MaxHedges > 0 {
+		do = hedgingMiddleware(o.HedgeDelay, o.MaxHedges, o.ForceRetry)(do)
+	}
+	if o.CircuitBreaker != nil {
+		do = breakerMiddleware(o.CircuitBreaker)(do)
+	}
+	if o.RateLimiter != nil {
+		do = rateLimitMiddleware(o.RateLimiter)(do)
+	}
+	if o.Balancer != nil {
+		do = balancerMiddleware(o.Balancer)(do)
+	}
+	if o.Compression {
+		do = compressionMiddleware(o.CompressionThreshold)(do)
+	}
+	if o.Cache != nil {
+		do = cacheMiddleware(o.Cache)(do)
+	}
+	if o.singleflight != nil {
+		do = singleflightMiddleware(o.singleflight, o.MaxResponseSize)(do)
+	}
+	for i := len(o.Middlewares) - 1; i >= 0; i-- {
+		do = o.Middlewares[i](do)
+	}
+	return do
+}
+
+// SetClient replaces the HTTP client of the default Client
 func SetClient(client *http.Client) {
-	mu.Lock()
-	defer mu.Unlock()
-	defaultClient = client
+	Default().SetHTTPClient(client)
 }
 
-// GetClient gets the global HTTP client
+// GetClient gets the HTTP client of the default Client
 func GetClient() *http.Client {
-	mu.RLock()
-	defer mu.RUnlock()
-	return defaultClient
+	return Default().HTTPClient()
 }
 
 // Option request configuration structure
@@ -81,8 +204,51 @@ type Option struct {
 	Retries     int               // Maximum number of retries
 	Backoff     BackoffFunc       // Retry backoff strategy
 	Headers     map[string]string // Custom request headers
+	Query       url.Values        // Parameters merged into the request URL
 	ForceRetry  bool              // Whether to force retry for all methods
 	Middlewares []Middleware      // Middleware chain
+
+	RequestTimeout time.Duration  // Deadline of the whole call, retries and backoff included
+	ContentLength  int64          // Length of a streamed body, sent chunked when unknown
+	CircuitBreaker CircuitBreaker // Fails fast while the upstream host is unhealthy
+	RateLimiter    RateLimiter    // Throttles every attempt before it is sent
+	Balancer       *Balancer      // Routes every attempt to one of several replicas
+	Signer         Signer         // Signs every attempt right before it is sent
+	Tracer         trace.Tracer   // Starts a client span for every attempt
+
+	Compression          bool  // Compress request bodies and decode compressed responses
+	CompressionThreshold int64 // Smallest request body that is gzipped
+
+	Cache CacheStore // Stores cacheable GET responses
+
+	HedgeDelay time.Duration // Wait before sending each duplicate of a slow attempt
+	MaxHedges  int           // Maximum number of duplicates per attempt, 0 disables hedging
+
+	Proxy func(*http.Request) (*url.URL, error) // Proxy of the request, defaults to the environment
+
+	RetryStatusCodes []int         // Statuses that are retried, defaults to 429 and 5xx
+	MaxRetryAfter    time.Duration // Longest Retry-After honored, longer waits end the retries, default 1m
+	RetryIf          RetryFunc     // Custom retry decision, replaces the status code and method rules
+
+	Debug io.Writer    // Receives a curl command and a dump of every attempt
+	HAR   *HARRecorder // Records every attempt into an HTTP Archive
+	Trace TimingsFunc  // Receives the DNS, connect, TLS and response timings of every attempt
+
+	UploadProgress   ProgressFunc // Reports request body progress
+	DownloadProgress ProgressFunc // Reports response body progress
+	MaxResponseSize  int64        // Largest response body read, 0 means unlimited
+
+	ChecksumAlgorithm cryptox.Algorithm // Hash of files written by Download
+	Checksum          []byte            // Expected digest of files written by Download, nil skips the check
+
+	err            error                   // Invalid option, returned by every request
+	clientFuncs    []func(*http.Client)    // HTTP client settings, applied to a copy of the client per request
+	transportFuncs []func(*http.Transport) // Transport settings, only honored by New
+	dialFuncs      []func(*dialer)         // Dialer settings, only honored by New
+	http2          *HTTP2Option            // HTTP/2 settings, only honored by New
+	proxyAuth      *proxyAuth              // Proxy credentials and challenges
+	singleflight   *singleflight           // Collapses concurrent identical requests
+	noRedirect     bool                    // Redirect responses are returned, set by WithNoRedirect
 }
 
 // BackoffFunc defines retry backoff function
@@ -110,18 +276,24 @@ func WithBackoff(f BackoffFunc) OptionFunc {
 	return func(o *Option) { o.Backoff = f }
 }
 
-// WithHeaders adds request headers
+// WithHeaders adds request headers, replacing earlier values of the same headers
 func WithHeaders(h map[string]string) OptionFunc {
 	return func(o *Option) {
 		if o.Headers == nil {
 			o.Headers = make(map[string]string)
 		}
 		for k, v := range h {
-			o.Headers[k] = v
+			// Canonical keys let request headers replace the defaults whatever their case
+			o.Headers[http.CanonicalHeaderKey(k)] = v
 		}
 	}
 }
 
+// WithUserAgent sets the User-Agent header
+func WithUserAgent(ua string) OptionFunc {
+	return WithHeaders(map[string]string{"User-Agent": ua})
+}
+
 // WithForceRetry forces retry for all methods
 func WithForceRetry() OptionFunc {
 	return func(o *Option) { o.ForceRetry = true }
@@ -132,41 +304,41 @@ func WithMiddleware(mw Middleware) OptionFunc {
 	return func(o *Option) { o.Middlewares = append(o.Middlewares, mw) }
 }
 
-// WithTimeout sets client timeout
+// WithTimeout sets the client timeout of every attempt
+// Passed to New it configures the client, passed to a request it only applies to that request
 func WithTimeout(timeout time.Duration) OptionFunc {
 	return func(o *Option) {
-		client := GetClient()
-		client.Timeout = timeout
+		o.clientFuncs = append(o.clientFuncs, func(c *http.Client) { c.Timeout = timeout })
+	}
+}
+
+// WithTransport sends requests through rt instead of the HTTP transport, e.g. a test double
+// Passed to New it configures the client, passed to a request it only applies to that request;
+// the transport options of New (WithMaxIdleConns, TLS...) have no effect on rt
+func WithTransport(rt http.RoundTripper) OptionFunc {
+	return func(o *Option) {
+		o.clientFuncs = append(o.clientFuncs, func(c *http.Client) { c.Transport = rt })
 	}
 }
 
-// WithMaxIdleConns sets global maximum idle connections
+// WithMaxIdleConns sets maximum idle connections, only honored by New
 func WithMaxIdleConns(n int) OptionFunc {
 	return func(o *Option) {
-		client := GetClient()
-		if t, ok := client.Transport.(*http.Transport); ok {
-			t.MaxIdleConns = n
-		}
+		o.transportFuncs = append(o.transportFuncs, func(t *http.Transport) { t.MaxIdleConns = n })
 	}
 }
 
-// WithMaxConnsPerHost sets maximum connections per host
+// WithMaxConnsPerHost sets maximum connections per host, only honored by New
 func WithMaxConnsPerHost(n int) OptionFunc {
 	return func(o *Option) {
-		client := GetClient()
-		if t, ok := client.Transport.(*http.Transport); ok {
-			t.MaxConnsPerHost = n
-		}
+		o.transportFuncs = append(o.transportFuncs, func(t *http.Transport) { t.MaxConnsPerHost = n })
 	}
 }
 
-// WithIdleConnTimeout sets idle connection timeout
+// WithIdleConnTimeout sets idle connection timeout, only honored by New
 func WithIdleConnTimeout(d time.Duration) OptionFunc {
 	return func(o *Option) {
-		client := GetClient()
-		if t, ok := client.Transport.(*http.Transport); ok {
-			t.IdleConnTimeout = d
-		}
+		o.transportFuncs = append(o.transportFuncs, func(t *http.Transport) { t.IdleConnTimeout = d })
 	}
 }
 
@@ -194,112 +366,227 @@ func (e *HTTPError) Error() string {
 // body: request body content, byte slice
 // opts: optional configuration, including retry count, backoff strategy, headers, middleware, etc.
 func Request(ctx context.Context, method, urlStr string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return Default().Request(ctx, method, urlStr, body, opts...)
+}
+
+// ErrTransportOption is returned by requests given an option that configures the transport,
+// such as WithMaxIdleConns, WithTLSConfig, WithDialTimeout or WithHTTP2, which are only valid for New
+var ErrTransportOption = errors.New("clientx: transport option only valid for New")
+
+// newOption merges the client defaults with the options of one request
+func (c *Client) newOption(opts []OptionFunc) *Option {
 	// Initialize default options: 3 retries, default backoff function
 	options := &Option{
-		Retries: 3,
-		Backoff: defaultBackoff,
+		Retries:       3,
+		Backoff:       defaultBackoff,
+		MaxRetryAfter: time.Minute,
+	}
+	c.mu.RLock()
+	defaults, base := c.opts, c.base
+	c.mu.RUnlock()
+	for _, opt := range defaults[:base] {
+		opt(options)
+	}
+	// Client and transport settings given to New are already part of c.client
+	options.clientFuncs, options.transportFuncs, options.dialFuncs, options.http2 = nil, nil, nil, nil
+	for _, opt := range defaults[base:] {
+		opt(options)
 	}
 	for _, opt := range opts {
 		opt(options) // Apply user-provided optional configuration
 	}
+	if len(options.transportFuncs) > 0 || len(options.dialFuncs) > 0 || options.http2 != nil {
+		// Silently dropping them would e.g. send a request meant for a client certificate without it
+		options.err = errors.Join(options.err, ErrTransportOption)
+	}
+	return options
+}
+
+// ResolveOptions returns the options a request of c made with opts would use, for packages
+// speaking other protocols over the transport of c such as clientx/ws
+func (c *Client) ResolveOptions(opts ...OptionFunc) (*Option, error) {
+	options := c.newOption(opts)
+	return options, options.err
+}
+
+// Context returns ctx carrying the per-request proxy settings of o, read by the transport of a
+// Client when it dials
+func (o *Option) Context(ctx context.Context) context.Context {
+	return withProxyAuth(withProxy(ctx, o.Proxy), o.proxyAuth)
+}
+
+// httpClient returns the HTTP client for a request, a copy when the request overrides client settings
+func (c *Client) httpClient(options *Option) *http.Client {
+	client := c.HTTPClient()
+	if len(options.clientFuncs) == 0 {
+		return client
+	}
+	copied := *client
+	for _, fn := range options.clientFuncs {
+		fn(&copied)
+	}
+	return &copied
+}
+
+// Request sends a request through c, see the package-level Request
+func (c *Client) Request(ctx context.Context, method, urlStr string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.RequestReader(ctx, method, urlStr, BytesBody(body), opts...)
+}
+
+// RequestReader is like Request but obtains the body from body before every attempt,
+// so large payloads are streamed instead of held in memory and retries can replay them
+func (c *Client) RequestReader(ctx context.Context, method, urlStr string, body BodyFunc, opts ...OptionFunc) (*http.Response, error) {
+	options := c.newOption(opts)
+	if options.err != nil {
+		return nil, options.err
+	}
+	if options.RequestTimeout <= 0 {
+		return c.do(ctx, method, urlStr, body, options)
+	}
+	ctx, cancel := context.WithTimeout(ctx, options.RequestTimeout)
+	resp, err := c.do(ctx, method, urlStr, body, options)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	// The deadline must outlive Request until the caller has read the body
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// do runs the retry loop of one request
+func (c *Client) do(ctx context.Context, method, urlStr string, body BodyFunc, options *Option) (*http.Response, error) {
+	if len(options.Query) > 0 {
+		var err error
+		if urlStr, err = mergeQuery(urlStr, options.Query); err != nil {
+			return nil, err
+		}
+	}
+	client := c.httpClient(options)
+	doFunc := options.chain(client.Do)
 
 	var lastErr error // Record the last error
 	for attempt := 0; attempt <= options.Retries; attempt++ {
+		// Obtain a fresh body for every attempt, the previous one has been consumed
 		var bodyReader io.Reader
-		var buf *bytes.Buffer
-
-		// Optimize request body:
-		// Less than 1KB, directly use bytes.NewReader
-		// Greater than 1KB, use buffer pool for reuse, reduce memory allocation
 		if body != nil {
-			if len(body) < 1024 {
-				bodyReader = bytes.NewReader(body)
-			} else {
-				buf = bufferPool.Get().(*bytes.Buffer)
-				buf.Reset()
-				buf.Write(body)
-				bodyReader = buf
+			r, err := body()
+			if err != nil {
+				if lastErr != nil {
+					return nil, errors.Join(lastErr, err)
+				}
+				return nil, err
 			}
+			bodyReader = r
 		}
 
 		// Create request object, bind context
-		req, err := http.NewRequestWithContext(ctx, method, urlStr, bodyReader)
+		reqCtx := options.Context(context.WithValue(ctx, attemptKey{}, attempt))
+		req, err := http.NewRequestWithContext(reqCtx, method, urlStr, bodyReader)
 		if err != nil {
-			if buf != nil {
-				bufferPool.Put(buf) // Return buffer to pool on error
+			if closer, ok := bodyReader.(io.Closer); ok {
+				_ = closer.Close()
 			}
 			return nil, err
 		}
+		if body != nil {
+			// Lets the transport replay the body on redirects and retried connections
+			req.GetBody = func() (io.ReadCloser, error) {
+				r, err := body()
+				if err != nil {
+					return nil, err
+				}
+				return readCloser(r), nil
+			}
+			if options.ContentLength > 0 {
+				req.ContentLength = options.ContentLength
+			} else if sr, ok := bodyReader.(*sizedReader); ok {
+				req.ContentLength = sr.Size()
+			}
+		}
+
+		if req.Body != nil && req.Body != http.NoBody {
+			req.Body = withProgress(req.Body, 0, req.ContentLength, options.UploadProgress)
+		}
 
 		// Set request headers
 		for k, v := range options.Headers {
 			req.Header.Set(k, v)
 		}
 
-		// Build middleware chain
-		doFunc := GetClient().Do // Default HTTP request function
-		for i := len(options.Middlewares) - 1; i >= 0; i-- {
-			mw := options.Middlewares[i] // Note the closure capture issue
-			next := doFunc
-			doFunc = func(req *http.Request) (*http.Response, error) {
-				return mw(next)(req) // Execute middleware
-			}
-		}
-
 		// Execute request
 		resp, err := doFunc(req)
+		if err != nil && req.Body != nil {
+			// The transport closes the body, but middlewares failing early may not
+			_ = req.Body.Close()
+		}
+		if errors.Is(err, ErrCircuitOpen) || errors.Is(err, ErrBlockedAddress) || errors.Is(err, ErrProxyAuthRequired) ||
+			errors.Is(err, ErrRedirect) || errors.Is(err, ErrResponseTooLarge) {
+			// Fail fast, retrying an open breaker, a rejected address, bad credentials, a refused redirect
+			// or an oversized shared response is pointless
+			return nil, err
+		}
 
-		// Return buffer to pool immediately after request completion
-		if buf != nil {
-			bufferPool.Put(buf)
+		// Decide whether the attempt is retried, RetryIf overrides the status and method rules
+		success := err == nil && (resp.StatusCode >= 200 && resp.StatusCode < 300 ||
+			options.noRedirect && isRedirect(resp.StatusCode))
+		var retry bool
+		if options.RetryIf != nil {
+			retry = options.retryIf(resp, err)
+		} else {
+			retry = !success && (resp == nil || options.RetryableStatus(resp.StatusCode))
+			retry = retry && (options.ForceRetry || strings.ToUpper(method) == http.MethodGet || strings.ToUpper(method) == http.MethodHead)
 		}
 
 		// If request is successful and status code is 2xx, return directly
-		if err == nil && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		if success && !retry {
+			if options.MaxResponseSize > 0 && resp.ContentLength > options.MaxResponseSize {
+				_ = resp.Body.Close()
+				return nil, fmt.Errorf("%w: %s %s has %d bytes", ErrResponseTooLarge, method, urlStr, resp.ContentLength)
+			}
+			resp.Body = withProgress(limitBody(resp.Body, options.MaxResponseSize), 0, resp.ContentLength, options.DownloadProgress)
 			return resp, nil
 		}
 
 		// Read response body for error information (max 512 bytes)
 		var bodyBytes []byte
+		var retryAfter time.Duration
+		statusCode := 0
 		if resp != nil {
+			statusCode = resp.StatusCode
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
 			bodyBytes, _ = io.ReadAll(io.LimitReader(resp.Body, 512))
 			_ = resp.Body.Close()
 		}
-
-		// 4xx errors return directly, no retry
-		if resp != nil && resp.StatusCode >= 400 && resp.StatusCode < 500 {
-			return nil, &HTTPError{
-				StatusCode: resp.StatusCode,
-				Method:     method,
-				URL:        urlStr,
-				Body:       bodyBytes,
-			}
-		}
-
-		// Record the last error
-		lastErr = &HTTPError{
-			StatusCode: 0,
+		httpErr := &HTTPError{
+			StatusCode: statusCode,
 			Method:     method,
 			URL:        urlStr,
 			Body:       bodyBytes,
 			Err:        err,
 		}
 
-		// Determine if retry is needed
-		if attempt < options.Retries {
-			// Enable retry for GET/HEAD methods or when ForceRetry is enabled
-			if options.ForceRetry || strings.ToUpper(method) == http.MethodGet || strings.ToUpper(method) == http.MethodHead {
-				backoff := options.Backoff(attempt) // Calculate backoff time
-				select {
-				case <-time.After(backoff):
-					// Wait for backoff time before retrying
-				case <-ctx.Done():
-					// Context cancelled or timed out, return
-					return nil, ctx.Err()
-				}
-			} else {
-				break // Non-retryable methods exit loop directly
+		// Record the last error
+		lastErr = httpErr
+
+		// Non-retryable results (by default 4xx and non-idempotent methods) return directly
+		if !retry || attempt == options.Retries {
+			break
+		}
+		backoff := options.Backoff(attempt) // Calculate backoff time
+		if retryAfter > 0 {
+			// The server said when to come back, typically on 429 and 503
+			if options.MaxRetryAfter > 0 && retryAfter > options.MaxRetryAfter {
+				break
 			}
+			backoff = retryAfter
+		}
+		select {
+		case <-time.After(backoff):
+			// Wait for backoff time before retrying
+		case <-ctx.Done():
+			// Context cancelled or timed out, return
+			return nil, ctx.Err()
 		}
 	}
 
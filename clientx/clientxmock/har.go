@@ -0,0 +1,38 @@
+package clientxmock
+
+import (
+	"github.com/chihqiang/gox/clientx"
+	"net/http"
+)
+
+// LoadHAR returns a Transport replaying the entries of the HAR archive at path, e.g. one
+// written by clientx.WithHAR or exported from a browser. Each entry answers one request with
+// the same method, URL and body, in archive order; entries without a response are skipped
+func LoadHAR(path string) (*Transport, error) {
+	h, err := clientx.ReadHAR(path)
+	if err != nil {
+		return nil, err
+	}
+	t := New()
+	for _, e := range h.Log.Entries {
+		if e.Response.Status == 0 {
+			continue // The request failed, there is nothing to replay
+		}
+		body, err := e.Response.Content.Body()
+		if err != nil {
+			return nil, err
+		}
+		s := t.On(e.Request.Method, e.Request.URL).Once().Reply(e.Response.Status, body)
+		if e.Request.PostData != nil && e.Request.PostData.Comment == "" {
+			// Bodies truncated by WithHAR carry a comment and cannot be compared
+			s.WithBody([]byte(e.Request.PostData.Text))
+		}
+		for _, h := range e.Response.Headers {
+			if name := http.CanonicalHeaderKey(h.Name); name == "Content-Encoding" || name == "Content-Length" {
+				continue // The content is recorded decoded
+			}
+			s.ReplyHeader(h.Name, h.Value)
+		}
+	}
+	return t, nil
+}
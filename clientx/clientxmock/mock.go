@@ -0,0 +1,296 @@
+// Package clientxmock provides test doubles for clientx: a Transport answering requests from
+// stubs, and a Recorder capturing real traffic to replay it later
+package clientxmock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/clientx"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// ErrNoStub is returned for requests no stub matches
+var ErrNoStub = errors.New("clientxmock: no stub matches the request")
+
+// Transport is an http.RoundTripper answering requests with the first matching stub
+type Transport struct {
+	mu    sync.Mutex
+	stubs []*Stub
+	calls []Call
+}
+
+// Call is a request received by a Transport
+type Call struct {
+	Method string
+	URL    string
+	Header http.Header
+	Body   []byte
+	Stub   *Stub // Stub that answered, nil when none matched
+}
+
+// New creates a Transport without stubs
+func New() *Transport {
+	return &Transport{}
+}
+
+// On adds a stub for method and rawURL, "*" matches any method
+// The scheme, host and path must be equal; query parameters of rawURL must be present in the
+// request, which may carry more
+func (t *Transport) On(method, rawURL string) *Stub {
+	s := &Stub{transport: t, method: strings.ToUpper(method), status: http.StatusOK, header: make(http.Header)}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		s.err = err
+		u = &url.URL{}
+	}
+	s.url = u
+	t.mu.Lock()
+	t.stubs = append(t.stubs, s)
+	t.mu.Unlock()
+	return s
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	call := Call{Method: req.Method, URL: req.URL.String(), Header: req.Header.Clone(), Body: body}
+
+	t.mu.Lock()
+	var stub *Stub
+	for _, s := range t.stubs {
+		if s.matches(req, body) {
+			stub = s
+			s.count++
+			break
+		}
+	}
+	call.Stub = stub
+	t.calls = append(t.calls, call)
+	t.mu.Unlock()
+
+	if stub == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoStub, req.Method, req.URL)
+	}
+	return stub.respond(req)
+}
+
+// Calls returns the requests received so far
+func (t *Transport) Calls() []Call {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]Call(nil), t.calls...)
+}
+
+// Reset drops the stubs and the recorded calls
+func (t *Transport) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.stubs, t.calls = nil, nil
+}
+
+// Client returns a clientx.Client sending its requests to t
+// Retries happen without backoff so tests of retry behavior stay fast; opts may override it
+func (t *Transport) Client(opts ...clientx.OptionFunc) *clientx.Client {
+	defaults := []clientx.OptionFunc{clientx.WithTransport(t), clientx.WithBackoff(clientx.ConstantBackoff(0))}
+	return clientx.New(append(defaults, opts...)...)
+}
+
+// Install makes the package-level functions of clientx (Get, Post...) use t until the test ends
+func (t *Transport) Install(tb testing.TB, opts ...clientx.OptionFunc) {
+	tb.Helper()
+	previous := clientx.Default()
+	clientx.SetDefault(t.Client(opts...))
+	tb.Cleanup(func() { clientx.SetDefault(previous) })
+}
+
+// Stub matches requests and describes the response returned for them
+type Stub struct {
+	transport *Transport
+	method    string
+	url       *url.URL
+	body      func([]byte) bool
+	match     http.Header // Request headers that must be present
+
+	status int
+	header http.Header
+	reply  []byte
+	err    error
+	delay  time.Duration
+	times  int // Remaining uses, 0 means unlimited
+	count  int
+}
+
+// WithBody only matches requests whose body equals body
+func (s *Stub) WithBody(body []byte) *Stub {
+	s.body = func(b []byte) bool { return bytes.Equal(b, body) }
+	return s
+}
+
+// WithJSONBody only matches requests whose body is JSON equal to v, whatever the formatting
+func (s *Stub) WithJSONBody(v any) *Stub {
+	want, err := normalizeJSON(v)
+	if err != nil {
+		s.err = err
+	}
+	s.body = func(b []byte) bool {
+		var got any
+		if json.Unmarshal(b, &got) != nil {
+			return false
+		}
+		data, _ := json.Marshal(got)
+		return bytes.Equal(data, want)
+	}
+	return s
+}
+
+// WithHeader only matches requests carrying the header with value
+func (s *Stub) WithHeader(key, value string) *Stub {
+	if s.match == nil {
+		s.match = make(http.Header)
+	}
+	s.match.Add(key, value)
+	return s
+}
+
+// Reply sets the status and body of the response, 200 with an empty body by default
+func (s *Stub) Reply(status int, body []byte) *Stub {
+	s.status, s.reply = status, body
+	return s
+}
+
+// ReplyString is Reply with a string body
+func (s *Stub) ReplyString(status int, body string) *Stub {
+	return s.Reply(status, []byte(body))
+}
+
+// ReplyJSON replies with v encoded as JSON and Content-Type application/json
+func (s *Stub) ReplyJSON(status int, v any) *Stub {
+	data, err := json.Marshal(v)
+	if err != nil {
+		s.err = err
+	}
+	s.header.Set("Content-Type", "application/json")
+	return s.Reply(status, data)
+}
+
+// ReplyHeader adds a response header
+func (s *Stub) ReplyHeader(key, value string) *Stub {
+	s.header.Add(key, value)
+	return s
+}
+
+// ReplyError fails the request with err, as a transport error
+func (s *Stub) ReplyError(err error) *Stub {
+	s.err = err
+	return s
+}
+
+// Delay waits d before responding, or until the request is cancelled
+func (s *Stub) Delay(d time.Duration) *Stub {
+	s.delay = d
+	return s
+}
+
+// Times limits the stub to n requests, later ones fall through to the next matching stub
+func (s *Stub) Times(n int) *Stub {
+	s.times = n
+	return s
+}
+
+// Once is Times(1)
+func (s *Stub) Once() *Stub {
+	return s.Times(1)
+}
+
+// Count returns the number of requests the stub answered
+func (s *Stub) Count() int {
+	s.transport.mu.Lock()
+	defer s.transport.mu.Unlock()
+	return s.count
+}
+
+// matches reports whether the stub answers req, called with the Transport locked
+func (s *Stub) matches(req *http.Request, body []byte) bool {
+	if s.times > 0 && s.count >= s.times {
+		return false
+	}
+	if s.method != "*" && s.method != req.Method {
+		return false
+	}
+	if s.url.Scheme != "" && s.url.Scheme != req.URL.Scheme ||
+		s.url.Host != "" && s.url.Host != req.URL.Host ||
+		s.url.Path != req.URL.Path && !(s.url.Path == "" && req.URL.Path == "/") {
+		return false
+	}
+	query := req.URL.Query()
+	for k, values := range s.url.Query() {
+		for _, v := range values {
+			if !slices.Contains(query[k], v) {
+				return false
+			}
+		}
+	}
+	for k, values := range s.match {
+		for _, v := range values {
+			if !slices.Contains(req.Header.Values(k), v) {
+				return false
+			}
+		}
+	}
+	return s.body == nil || s.body(body)
+}
+
+// respond builds the response of the stub
+func (s *Stub) respond(req *http.Request) (*http.Response, error) {
+	if s.delay > 0 {
+		select {
+		case <-time.After(s.delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", s.status, http.StatusText(s.status)),
+		StatusCode:    s.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        s.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(s.reply)),
+		ContentLength: int64(len(s.reply)),
+		Request:       req,
+	}, nil
+}
+
+// normalizeJSON encodes v in the canonical form used to compare JSON bodies
+func normalizeJSON(v any) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return nil, err
+	}
+	return json.Marshal(generic)
+}
@@ -0,0 +1,191 @@
+package clientxmock
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/chihqiang/gox/clientx"
+	"github.com/chihqiang/gox/filex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"unicode/utf8"
+)
+
+// Interaction is a recorded request and its response
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the request of an Interaction, credentials are not recorded
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// RecordedResponse is the response of an Interaction
+type RecordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   Body        `json:"body,omitempty"`
+}
+
+// Body is recorded as a string when it is UTF-8 text and as base64 otherwise
+type Body []byte
+
+// MarshalJSON implements json.Marshaler
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var encoded struct {
+		Base64 string `json:"base64"`
+	}
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(encoded.Base64)
+	*b = decoded
+	return err
+}
+
+// redactedHeaders are left out of recorded requests
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie"}
+
+// Recorder is an http.RoundTripper sending requests through another transport and recording
+// every interaction, Save writes them to a file that Load replays
+type Recorder struct {
+	mu           sync.Mutex
+	next         http.RoundTripper
+	path         string
+	interactions []Interaction
+}
+
+// NewRecorder records the traffic sent through next (http.DefaultTransport when nil) to path
+func NewRecorder(path string, next http.RoundTripper) *Recorder {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &Recorder{next: next, path: path}
+}
+
+// RoundTrip implements http.RoundTripper
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil && req.Body != http.NoBody {
+		var err error
+		if reqBody, err = io.ReadAll(req.Body); err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+	resp, err := r.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := req.Header.Clone()
+	for _, h := range redactedHeaders {
+		header.Del(h)
+	}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{
+		Request:  RecordedRequest{Method: req.Method, URL: req.URL.String(), Header: header, Body: reqBody},
+		Response: RecordedResponse{Status: resp.StatusCode, Header: resp.Header.Clone(), Body: respBody},
+	})
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// Interactions returns the interactions recorded so far
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the file of the recorder
+func (r *Recorder) Save() error {
+	data, err := json.MarshalIndent(r.Interactions(), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := filex.EnsureDir(filepath.Dir(r.path)); err != nil {
+		return err
+	}
+	return filex.AtomicWrite(r.path, data)
+}
+
+// Load returns a Transport replaying the interactions saved at path
+// Each interaction answers one request with the same method, URL and body, in recording order
+func Load(path string) (*Transport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var interactions []Interaction
+	if err := json.Unmarshal(data, &interactions); err != nil {
+		return nil, err
+	}
+	t := New()
+	for _, in := range interactions {
+		s := t.On(in.Request.Method, in.Request.URL).WithBody(in.Request.Body).Once().
+			Reply(in.Response.Status, in.Response.Body)
+		for k, values := range in.Response.Header {
+			for _, v := range values {
+				s.ReplyHeader(k, v)
+			}
+		}
+	}
+	return t, nil
+}
+
+// Record makes the package-level functions of clientx replay the interactions saved at path
+// until the test ends. When the file does not exist yet, real requests are sent and recorded
+// to it instead; delete the file to record again
+func Record(tb testing.TB, path string, opts ...clientx.OptionFunc) {
+	tb.Helper()
+	t, err := Load(path)
+	if err == nil {
+		t.Install(tb, opts...)
+		return
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		tb.Fatalf("clientxmock: load %s: %v", path, err)
+	}
+
+	previous := clientx.Default()
+	rec := NewRecorder(path, previous.HTTPClient().Transport)
+	clientx.SetDefault(clientx.New(append([]clientx.OptionFunc{clientx.WithTransport(rec)}, opts...)...))
+	tb.Cleanup(func() {
+		clientx.SetDefault(previous)
+		if err := rec.Save(); err != nil {
+			tb.Errorf("clientxmock: save %s: %v", path, err)
+		}
+	})
+}
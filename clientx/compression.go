@@ -0,0 +1,179 @@
+package clientx
+
+import (
+	"bufio"
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"github.com/andybalholm/brotli"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// defaultCompressionThreshold is the smallest request body gzipped by WithCompression
+const defaultCompressionThreshold = 1024
+
+// WithCompression gzips request bodies of at least 1 KiB and decodes gzip, deflate and br
+// response bodies, also when Accept-Encoding was set by the caller, which turns off the
+// decompression of the standard transport
+func WithCompression() OptionFunc {
+	return WithCompressionThreshold(defaultCompressionThreshold)
+}
+
+// WithCompressionThreshold is WithCompression gzipping request bodies of at least n bytes
+func WithCompressionThreshold(n int64) OptionFunc {
+	return func(o *Option) {
+		o.Compression = true
+		o.CompressionThreshold = n
+	}
+}
+
+// compressionMiddleware compresses the request body and decodes the response body
+func compressionMiddleware(threshold int64) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if req.Header.Get("Accept-Encoding") == "" {
+				req.Header.Set("Accept-Encoding", "gzip, deflate, br")
+			}
+			compressRequest(req, threshold)
+			resp, err := next(req)
+			if err != nil {
+				return resp, err
+			}
+			decodeResponse(resp)
+			return resp, nil
+		}
+	}
+}
+
+// compressRequest gzips the body of req when it reaches threshold
+// Bodies of unknown length are peeked to find out
+func compressRequest(req *http.Request, threshold int64) {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return
+	}
+	if req.ContentLength > 0 && req.ContentLength < threshold {
+		return
+	}
+	body := req.Body
+	if req.ContentLength <= 0 {
+		// For a client request 0 with a body means unknown
+		prefix, err := io.ReadAll(io.LimitReader(body, threshold))
+		replay := struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(prefix), body), body}
+		if err != nil || int64(len(prefix)) < threshold {
+			req.Body = replay
+			if err == nil {
+				req.ContentLength = int64(len(prefix))
+			}
+			return
+		}
+		body = replay
+	}
+	req.Body = gzipReader(body)
+	req.ContentLength = -1
+	req.Header.Set("Content-Encoding", "gzip")
+	req.Header.Del("Content-Length")
+	if getBody := req.GetBody; getBody != nil {
+		req.GetBody = func() (io.ReadCloser, error) {
+			b, err := getBody()
+			if err != nil {
+				return nil, err
+			}
+			return gzipReader(b), nil
+		}
+	}
+}
+
+// gzipReader returns the gzip stream of r, compressed as it is read
+func gzipReader(r io.ReadCloser) io.ReadCloser {
+	pr, pw := io.Pipe()
+	go func() {
+		defer r.Close()
+		zw := gzip.NewWriter(pw)
+		_, err := io.Copy(zw, r)
+		if err == nil {
+			err = zw.Close()
+		}
+		_ = pw.CloseWithError(err)
+	}()
+	return pr
+}
+
+// decodeResponse replaces a compressed body the transport left alone by its decoded content
+func decodeResponse(resp *http.Response) {
+	encodings := strings.Split(resp.Header.Get("Content-Encoding"), ",")
+	if resp.Uncompressed || resp.Body == nil || len(encodings) == 0 {
+		return
+	}
+	// Encodings are listed in the order they were applied
+	for _, enc := range encodings {
+		switch strings.ToLower(strings.TrimSpace(enc)) {
+		case "gzip", "x-gzip", "deflate", "br":
+		default:
+			return
+		}
+	}
+	body := resp.Body
+	var r io.Reader = body
+	for i := len(encodings) - 1; i >= 0; i-- {
+		r = &decodeReader{src: r, encoding: strings.ToLower(strings.TrimSpace(encodings[i]))}
+	}
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{r, body}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// decodeReader decodes src, creating the decoder on the first Read so empty bodies
+// (HEAD, 204, 304) do not fail
+type decodeReader struct {
+	src      io.Reader
+	encoding string
+	r        io.Reader
+	err      error
+}
+
+// Read implements io.Reader
+func (d *decodeReader) Read(p []byte) (int, error) {
+	if d.r == nil && d.err == nil {
+		d.r, d.err = newDecoder(d.src, d.encoding)
+	}
+	if d.err != nil {
+		return 0, d.err
+	}
+	return d.r.Read(p)
+}
+
+// newDecoder returns a reader decoding src
+func newDecoder(src io.Reader, encoding string) (io.Reader, error) {
+	br := bufio.NewReader(src)
+	header, err := br.Peek(2)
+	if len(header) == 0 {
+		if err == io.EOF {
+			err = nil // Empty body
+		}
+		return br, err
+	}
+	switch encoding {
+	case "br":
+		return brotli.NewReader(br), nil
+	case "deflate":
+		// Meant to be zlib wrapped, but some servers send raw deflate
+		if len(header) == 2 && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+			return zlib.NewReader(br)
+		}
+		return flate.NewReader(br), nil
+	default:
+		return gzip.NewReader(br)
+	}
+}
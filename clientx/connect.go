@@ -7,5 +7,10 @@ import (
 
 // Connect request
 func Connect(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodConnect, url, body, opts...)
+	return Default().Connect(ctx, url, body, opts...)
+}
+
+// Connect request
+func (c *Client) Connect(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodConnect, url, body, opts...)
 }
@@ -0,0 +1,160 @@
+package clientx
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// debugBodyLimit is the number of body bytes written by WithDebugCurl
+const debugBodyLimit = 64 << 10
+
+// WithDebugCurl writes every attempt to w as an equivalent curl command, followed by the raw
+// request and response; bodies are cut at 64 KiB. Credentials are written as is, keep the
+// output out of shared logs. Event streams are dumped without their body
+func WithDebugCurl(w io.Writer) OptionFunc {
+	return func(o *Option) { o.Debug = w }
+}
+
+// debugWriters serializes the output of concurrent requests per writer
+var debugWriters sync.Map // io.Writer -> *sync.Mutex
+
+// debugMiddleware dumps the request handed to the transport and its response
+func debugMiddleware(w io.Writer) Middleware {
+	mu, _ := debugWriters.LoadOrStore(w, new(sync.Mutex))
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			var body []byte
+			if req.GetBody != nil && req.Body != nil && req.Body != http.NoBody {
+				if r, err := req.GetBody(); err == nil {
+					body, _ = io.ReadAll(io.LimitReader(r, debugBodyLimit+1))
+					_ = r.Close()
+				}
+			}
+			var b strings.Builder
+			fmt.Fprintf(&b, "# attempt %d\n", AttemptFromContext(req.Context())+1)
+			b.WriteString(curlCommand(req, body))
+			b.WriteString("\n")
+			dumpRequest(&b, req, body)
+
+			resp, err := next(req)
+			if err != nil {
+				fmt.Fprintf(&b, "# error: %v\n", err)
+			} else {
+				var prefix []byte
+				// Streams are left alone, reading ahead would hold back their events
+				if resp.Body != nil && !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+					prefix, _ = io.ReadAll(io.LimitReader(resp.Body, debugBodyLimit+1))
+					resp.Body = struct {
+						io.Reader
+						io.Closer
+					}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+				}
+				dumpResponse(&b, resp, prefix)
+			}
+			b.WriteString("\n")
+
+			mu.(*sync.Mutex).Lock()
+			_, _ = io.WriteString(w, b.String())
+			mu.(*sync.Mutex).Unlock()
+			return resp, err
+		}
+	}
+}
+
+// curlCommand renders req as a curl command line
+func curlCommand(req *http.Request, body []byte) string {
+	parts := []string{"curl"}
+	if req.Method != http.MethodGet {
+		if req.Method == http.MethodHead {
+			parts = append(parts, "--head")
+		} else {
+			parts = append(parts, "-X", shellQuote(req.Method))
+		}
+	}
+	parts = append(parts, shellQuote(req.URL.String()))
+	if req.Host != "" && req.Host != req.URL.Host {
+		parts = append(parts, "-H", shellQuote("Host: "+req.Host))
+	}
+	for _, name := range sortedKeys(req.Header) {
+		for _, v := range req.Header[name] {
+			parts = append(parts, "-H", shellQuote(name+": "+v))
+		}
+	}
+	switch {
+	case len(body) > debugBodyLimit:
+		parts = append(parts, "--data-binary", "@body # body longer than 64 KiB, not included")
+	case len(body) > 0 && utf8.Valid(body):
+		parts = append(parts, "--data-binary", shellQuote(string(body)))
+	case len(body) > 0:
+		parts = append(parts, "--data-binary", "@body # binary body, not included")
+	}
+	return strings.Join(parts, " ")
+}
+
+// dumpRequest writes the request line, headers and body prefixed with "> "
+func dumpRequest(b *strings.Builder, req *http.Request, body []byte) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	fmt.Fprintf(b, "> %s %s %s\n> Host: %s\n", req.Method, req.URL.RequestURI(), req.Proto, host)
+	for _, name := range sortedKeys(req.Header) {
+		for _, v := range req.Header[name] {
+			fmt.Fprintf(b, "> %s: %s\n", name, v)
+		}
+	}
+	writeDebugBody(b, "> ", body)
+}
+
+// dumpResponse writes the status line, headers and body prefixed with "< "
+func dumpResponse(b *strings.Builder, resp *http.Response, body []byte) {
+	fmt.Fprintf(b, "< %s %s\n", resp.Proto, resp.Status)
+	for _, name := range sortedKeys(resp.Header) {
+		for _, v := range resp.Header[name] {
+			fmt.Fprintf(b, "< %s: %s\n", name, v)
+		}
+	}
+	writeDebugBody(b, "< ", body)
+}
+
+// writeDebugBody writes body after an empty line, summarized when binary or too long
+func writeDebugBody(b *strings.Builder, prefix string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	b.WriteString(prefix + "\n")
+	truncated := len(body) > debugBodyLimit
+	if truncated {
+		body = body[:debugBodyLimit]
+	}
+	if !utf8.Valid(body) {
+		fmt.Fprintf(b, "%s[%d bytes of binary data]\n", prefix, len(body))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(string(body), "\n"), "\n") {
+		b.WriteString(prefix + line + "\n")
+	}
+	if truncated {
+		b.WriteString(prefix + "[truncated]\n")
+	}
+}
+
+// shellQuote quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+func sortedKeys(h http.Header) []string {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
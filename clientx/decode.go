@@ -0,0 +1,48 @@
+package clientx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// DecodeJSON decodes the body of a response returned by Request (or any helper) into T
+// and always closes the body; an empty body is an error unless the status is 204 No Content.
+// A non-nil err is returned unchanged, so calls can be chained:
+//
+//	user, err := clientx.DecodeJSON[User](clientx.Get(ctx, url))
+func DecodeJSON[T any](resp *http.Response, err error) (T, error) {
+	var v T
+	if err != nil {
+		return v, err
+	}
+	defer func() {
+		// Drain what the decoder left so the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil && (err != io.EOF || resp.StatusCode != http.StatusNoContent) {
+		return v, fmt.Errorf("JSON deserialization failed: %w", err)
+	}
+	return v, nil
+}
+
+// GetJSON sends a GET request and decodes the JSON response into T
+// Non-2xx responses are returned as *HTTPError, the body is always closed
+func GetJSON[T any](ctx context.Context, url string, opts ...OptionFunc) (T, error) {
+	headerOpt := WithHeaders(map[string]string{
+		"Accept": "application/json",
+	})
+	// The default goes first so that an Accept header of the caller wins
+	return DecodeJSON[T](Get(ctx, url, append([]OptionFunc{headerOpt}, opts...)...))
+}
+
+// PostJSONAs sends payload as JSON and decodes the JSON response into T
+func PostJSONAs[T any](ctx context.Context, url string, payload any, opts ...OptionFunc) (T, error) {
+	headerOpt := WithHeaders(map[string]string{
+		"Accept": "application/json",
+	})
+	return DecodeJSON[T](PostJSON(ctx, url, payload, append([]OptionFunc{headerOpt}, opts...)...))
+}
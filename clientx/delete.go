@@ -7,5 +7,20 @@ import (
 
 // Delete request
 func Delete(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodDelete, url, body, opts...)
+	return Default().Delete(ctx, url, body, opts...)
+}
+
+// Delete request
+func (c *Client) Delete(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodDelete, url, body, opts...)
+}
+
+// DeleteJSON sends a DELETE request with payload serialized as JSON
+func DeleteJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return Default().DeleteJSON(ctx, url, payload, opts...)
+}
+
+// DeleteJSON sends a DELETE request with payload serialized as JSON through c
+func (c *Client) DeleteJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return c.requestJSON(ctx, http.MethodDelete, url, payload, opts...)
 }
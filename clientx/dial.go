@@ -0,0 +1,141 @@
+package clientx
+
+import (
+	"context"
+	"errors"
+	"github.com/chihqiang/gox/cachex"
+	"net"
+	"net/http"
+	"time"
+)
+
+// Resolver looks up the addresses of a host, *net.Resolver implements it
+type Resolver interface {
+	LookupIPAddr(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+// dialer is the DialContext of the transport once a dial option is set
+type dialer struct {
+	net.Dialer
+	resolver Resolver                            // Custom resolver, nil uses the dialer's
+	cache    *cachex.Cache[string, []net.IPAddr] // Resolved addresses, nil disables caching
+	socket   string                              // Unix socket every connection is made to
+	guard    *guard                              // Validates dialed addresses, nil allows all
+}
+
+// withDialer configures the dialer of the transport, only honored by New
+func withDialer(fn func(*dialer)) OptionFunc {
+	return func(o *Option) { o.dialFuncs = append(o.dialFuncs, fn) }
+}
+
+// newDialer builds the dialer configured by fns
+func newDialer(fns []func(*dialer)) *dialer {
+	d := &dialer{Dialer: net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}}
+	for _, fn := range fns {
+		fn(d)
+	}
+	return d
+}
+
+// WithDialTimeout bounds the time to establish a connection, only honored by New
+// The default transport has no limit of its own beyond the client timeout
+func WithDialTimeout(d time.Duration) OptionFunc {
+	return withDialer(func(dl *dialer) { dl.Timeout = d })
+}
+
+// WithResolver resolves host names with r instead of the system resolver, only honored by New
+func WithResolver(r Resolver) OptionFunc {
+	return withDialer(func(d *dialer) {
+		if nr, ok := r.(*net.Resolver); ok {
+			d.Dialer.Resolver = nr
+			d.resolver = nil
+			return
+		}
+		d.resolver = r
+	})
+}
+
+// WithDNSCache caches resolved addresses for ttl, only honored by New
+// Concurrent lookups of the same host share one query; failures are not cached
+func WithDNSCache(ttl time.Duration) OptionFunc {
+	return withDialer(func(d *dialer) {
+		d.cache = cachex.New[string, []net.IPAddr](cachex.WithDefaultTTL(ttl), cachex.WithMaxEntries(4096))
+	})
+}
+
+// WithUnixSocket connects to the Unix domain socket at path whatever the host of the URL,
+// e.g. http://unix/v1.45/containers/json with /var/run/docker.sock, only honored by New
+func WithUnixSocket(path string) OptionFunc {
+	return withDialer(func(d *dialer) { d.socket = path })
+}
+
+// DialContext connects to addr, resolving the host through the resolver and cache
+func (d *dialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d.socket != "" {
+		return d.Dialer.DialContext(ctx, "unix", d.socket)
+	}
+	if d.guard != nil {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			return nil, err
+		}
+		ctx = context.WithValue(ctx, nameAllowedKey{}, d.guard.allowName(host))
+	}
+	if d.resolver == nil && d.cache == nil {
+		return d.Dialer.DialContext(ctx, network, addr)
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return d.Dialer.DialContext(ctx, network, addr)
+	}
+	ips, err := d.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, ip := range ips {
+		if network == "tcp4" && ip.IP.To4() == nil || network == "tcp6" && ip.IP.To4() != nil {
+			continue
+		}
+		conn, err := d.Dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		errs = append(errs, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if len(errs) == 0 {
+		return nil, &net.DNSError{Err: "no suitable address found", Name: host, IsNotFound: true}
+	}
+	return nil, errors.Join(errs...)
+}
+
+// lookup resolves host, from the cache when enabled
+func (d *dialer) lookup(ctx context.Context, host string) ([]net.IPAddr, error) {
+	resolve := func(ctx context.Context) ([]net.IPAddr, error) {
+		if d.resolver != nil {
+			return d.resolver.LookupIPAddr(ctx, host)
+		}
+		r := d.Dialer.Resolver
+		if r == nil {
+			r = net.DefaultResolver
+		}
+		return r.LookupIPAddr(ctx, host)
+	}
+	if d.cache == nil {
+		return resolve(ctx)
+	}
+	return d.cache.GetOrLoad(ctx, host, resolve)
+}
+
+// applyDialer installs the dialer configured by fns on t
+func applyDialer(t *http.Transport, fns []func(*dialer)) {
+	if len(fns) > 0 {
+		t.DialContext = newDialer(fns).DialContext
+	}
+}
@@ -0,0 +1,289 @@
+package clientx
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/cryptox"
+	"github.com/chihqiang/gox/filex"
+	"hash"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrIncompleteDownload is returned when the body ends before the announced length
+var ErrIncompleteDownload = errors.New("clientx: incomplete download")
+
+// ErrChecksumMismatch is returned when a downloaded file does not match WithChecksum
+var ErrChecksumMismatch = errors.New("clientx: checksum mismatch")
+
+// WithChecksum verifies files written by Download against the hex digest expected, algo is one
+// of "md5", "sha1", "sha256" or "sha512". The file is hashed while it is written and deleted
+// when it does not match
+func WithChecksum(algo, expected string) OptionFunc {
+	alg, err := cryptox.ParseAlgorithm(algo)
+	if err != nil {
+		err = fmt.Errorf("clientx: %w", err)
+	}
+	sum, hexErr := hex.DecodeString(strings.TrimSpace(expected))
+	if err == nil && (hexErr != nil || len(sum) != alg.New().Size()) {
+		err = fmt.Errorf("clientx: invalid %s checksum %q", alg, expected)
+	}
+	return func(o *Option) {
+		if err != nil {
+			o.err = errors.Join(o.err, err)
+			return
+		}
+		o.ChecksumAlgorithm, o.Checksum = alg, sum
+	}
+}
+
+// Download streams url to destPath
+func Download(ctx context.Context, url, destPath string, opts ...OptionFunc) error {
+	return Default().Download(ctx, url, destPath, opts...)
+}
+
+// Download streams url to destPath. Data is written to destPath+".part" and renamed once complete
+// Failed and interrupted transfers are retried, resumed with Range requests (or restarted when
+// the server does not accept ranges), up to the retry count of the options
+// On failure the .part file is kept if the server accepts ranges, so a later call resumes it,
+// and removed otherwise
+func (c *Client) Download(ctx context.Context, url, destPath string, opts ...OptionFunc) (err error) {
+	options := c.newOption(opts)
+	if err := filex.EnsureDir(filepath.Dir(destPath)); err != nil {
+		return err
+	}
+	part := destPath + ".part"
+	f, err := os.OpenFile(part, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	d := &download{
+		client: c,
+		url:    url,
+		// Progress covers the whole file, not the range fetched by each attempt, and every
+		// attempt is sent once: retrying is done here, resuming where the last one stopped
+		opts:     append(slices.Clip(opts), WithDownloadProgress(nil), WithRetries(0)),
+		options:  options,
+		file:     f,
+		progress: options.DownloadProgress,
+	}
+	if options.Checksum != nil {
+		d.hash = &countingHash{Hash: options.ChecksumAlgorithm.New()}
+	}
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		d.ranges = true // Only kept by an earlier call when the server accepts ranges
+	}
+	defer func() {
+		_ = f.Close()
+		if err == nil {
+			return
+		}
+		if info, statErr := os.Stat(part); statErr == nil && (!d.ranges || info.Size() == 0) {
+			_ = os.Remove(part)
+		}
+	}()
+
+	for attempt := 0; ; attempt++ {
+		interrupted, err := d.fetch(ctx)
+		if err == nil {
+			break
+		}
+		// Without range support the next attempt starts over
+		if !interrupted || attempt >= options.Retries {
+			return err
+		}
+		select {
+		case <-time.After(options.Backoff(attempt)):
+			// Wait before resuming
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if d.hash != nil {
+		if err := d.syncHash(); err != nil {
+			return err
+		}
+		if sum := d.hash.Sum(nil); !bytes.Equal(sum, options.Checksum) {
+			d.ranges = false // Resuming the same bytes cannot help, drop the file
+			return fmt.Errorf("%w: %s is %x, want %x", ErrChecksumMismatch, options.ChecksumAlgorithm, sum, options.Checksum)
+		}
+	}
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(part, destPath)
+}
+
+// download is the state of one Download call
+type download struct {
+	client    *Client
+	url       string
+	opts      []OptionFunc
+	options   *Option
+	file      *os.File
+	validator string // ETag or Last-Modified of the first response, sent as If-Range when resuming
+	ranges    bool   // The server accepts byte ranges
+	progress  ProgressFunc
+	hash      *countingHash // Digest of the file for WithChecksum, nil without it
+}
+
+// countingHash is a hash that knows how many bytes it has consumed
+type countingHash struct {
+	hash.Hash
+	n int64
+}
+
+// Write implements io.Writer
+func (h *countingHash) Write(p []byte) (int, error) {
+	n, err := h.Hash.Write(p)
+	h.n += int64(n)
+	return n, err
+}
+
+// syncHash brings the digest in line with the file, rehashing it from the start when bytes were
+// written by an earlier call, truncated or lost to a failed write
+func (d *download) syncHash() error {
+	size, err := d.file.Seek(0, io.SeekEnd)
+	if err != nil || d.hash.n == size {
+		return err
+	}
+	d.hash.Reset()
+	d.hash.n = 0
+	_, err = io.Copy(d.hash, io.NewSectionReader(d.file, 0, size))
+	return err
+}
+
+// fetch requests the bytes missing from the file and appends them
+// interrupted reports that the transfer broke off and may be resumed
+func (d *download) fetch(ctx context.Context) (interrupted bool, err error) {
+	offset, err := d.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+	headers := make(map[string]string)
+	if offset > 0 {
+		headers["Range"] = fmt.Sprintf("bytes=%d-", offset)
+		if d.validator != "" {
+			headers["If-Range"] = d.validator
+		}
+	}
+	resp, err := d.client.Get(ctx, d.url, append(slices.Clip(d.opts), WithHeaders(headers))...)
+	if err != nil {
+		var httpErr *HTTPError
+		if !errors.As(err, &httpErr) {
+			return false, err // Invalid options or failing fast
+		}
+		switch code := httpErr.StatusCode; {
+		case code == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+			// The partial file does not match the resource any more, start over
+			return true, errors.Join(err, d.truncate())
+		case code == 0:
+			return ctx.Err() == nil, err // Connection failures are retried like by Request
+		case d.options.RetryableStatus(code):
+			return true, err
+		case code >= 400 && code < 500:
+			d.ranges = false // The resource is gone or forbidden, resuming cannot help
+		}
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if v := resp.Header.Get("ETag"); v != "" && !strings.HasPrefix(v, "W/") {
+		d.validator = v
+	} else if v := resp.Header.Get("Last-Modified"); v != "" {
+		d.validator = v
+	}
+
+	total := resp.ContentLength
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		d.ranges = true
+		start, size, ok := parseContentRange(resp.Header.Get("Content-Range"))
+		if !ok || start != offset {
+			return true, errors.Join(fmt.Errorf("clientx: unexpected Content-Range %q", resp.Header.Get("Content-Range")), d.truncate())
+		}
+		total = size
+	default:
+		// Full body, the server ignored the range or the resource changed
+		d.ranges = resp.Header.Get("Accept-Ranges") == "bytes"
+		if offset > 0 {
+			if err := d.truncate(); err != nil {
+				return false, err
+			}
+		}
+	}
+
+	offset, err = d.file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return false, err
+	}
+	var w io.Writer = d.file
+	if d.hash != nil {
+		if err := d.syncHash(); err != nil {
+			return false, err
+		}
+		w = io.MultiWriter(d.file, d.hash) // Hash the bytes as they are written
+	}
+	if _, err := io.Copy(w, withProgress(resp.Body, offset, total, d.progress)); err != nil {
+		return true, err
+	}
+	if total >= 0 {
+		size, err := d.file.Seek(0, io.SeekEnd)
+		if err != nil {
+			return false, err
+		}
+		if size != total {
+			return true, fmt.Errorf("%w: got %d of %d bytes", ErrIncompleteDownload, size, total)
+		}
+	}
+	return false, nil
+}
+
+// truncate empties the partial file
+func (d *download) truncate() error {
+	if err := d.file.Truncate(0); err != nil {
+		return err
+	}
+	_, err := d.file.Seek(0, io.SeekStart)
+	return err
+}
+
+// parseContentRange parses "bytes start-end/size", size is -1 when unknown
+func parseContentRange(v string) (start, size int64, ok bool) {
+	v, found := strings.CutPrefix(v, "bytes ")
+	if !found {
+		return 0, 0, false
+	}
+	rng, total, found := strings.Cut(v, "/")
+	if !found {
+		return 0, 0, false
+	}
+	first, _, found := strings.Cut(rng, "-")
+	if !found {
+		return 0, 0, false
+	}
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	size = -1
+	if total != "*" {
+		if size, err = strconv.ParseInt(total, 10, 64); err != nil {
+			return 0, 0, false
+		}
+	}
+	return start, size, true
+}
@@ -0,0 +1,65 @@
+package clientx
+
+import (
+	"context"
+	"fmt"
+	"github.com/chihqiang/gox/encodingx"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// RequestEncoded encodes payload in format (a name, extension or MIME type registered in
+// encodingx, e.g. "yaml" or "application/msgpack") and sends it with the Content-Type of the
+// codec; the Accept header asks for the same format
+func RequestEncoded(ctx context.Context, method, url, format string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return Default().RequestEncoded(ctx, method, url, format, payload, opts...)
+}
+
+// RequestEncoded sends an encoded payload through c, see the package-level RequestEncoded
+func (c *Client) RequestEncoded(ctx context.Context, method, url, format string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	codec, ok := encodingx.Lookup(format)
+	if !ok {
+		return nil, &encodingx.UnknownFormatError{Format: format}
+	}
+	data, err := codec.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("%s serialization failed: %w", strings.ToUpper(format), err)
+	}
+	headerOpt := WithHeaders(map[string]string{
+		"Content-Type": codec.ContentType(),
+		"Accept":       codec.ContentType(),
+	})
+	// Headers of the caller take precedence
+	return c.Request(ctx, method, url, data, append([]OptionFunc{headerOpt}, opts...)...)
+}
+
+// Decode decodes the body of a response into T with the encodingx codec matching its
+// Content-Type, JSON when the header is absent, and always closes the body; a non-nil err is
+// returned unchanged, so calls can be chained like DecodeJSON
+func Decode[T any](resp *http.Response, err error) (T, error) {
+	var v T
+	if err != nil {
+		return v, err
+	}
+	defer resp.Body.Close()
+	ct := resp.Header.Get("Content-Type")
+	if ct == "" {
+		ct = encodingx.JSON
+	}
+	codec, ok := encodingx.Lookup(ct)
+	if !ok {
+		return v, &encodingx.UnknownFormatError{Format: ct}
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return v, err
+	}
+	if len(data) == 0 {
+		return v, nil
+	}
+	if err := codec.Unmarshal(data, &v); err != nil {
+		return v, fmt.Errorf("%s deserialization failed: %w", codec.ContentType(), err)
+	}
+	return v, nil
+}
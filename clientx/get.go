@@ -7,5 +7,10 @@ import (
 
 // Get request
 func Get(ctx context.Context, url string, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodGet, url, nil, opts...)
+	return Default().Get(ctx, url, opts...)
+}
+
+// Get request
+func (c *Client) Get(ctx context.Context, url string, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodGet, url, nil, opts...)
 }
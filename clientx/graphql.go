@@ -0,0 +1,79 @@
+package clientx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// GraphQLError is returned when a GraphQL response carries errors; data, if any, has still been
+// decoded into out, so partial results remain usable
+type GraphQLError struct {
+	Errors []GraphQLErrorDetail
+}
+
+// GraphQLErrorDetail is an entry of the errors array of a GraphQL response
+type GraphQLErrorDetail struct {
+	Message    string            `json:"message"`
+	Locations  []GraphQLLocation `json:"locations,omitempty"`
+	Path       []any             `json:"path,omitempty"` // Field names and list indices
+	Extensions map[string]any    `json:"extensions,omitempty"`
+}
+
+// GraphQLLocation points at the query text an error is about
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error implements error interface
+func (e *GraphQLError) Error() string {
+	messages := make([]string, len(e.Errors))
+	for i, d := range e.Errors {
+		messages[i] = d.Message
+		if len(d.Path) > 0 {
+			path := make([]string, len(d.Path))
+			for j, p := range d.Path {
+				path[j] = fmt.Sprint(p)
+			}
+			messages[i] += " (at " + strings.Join(path, ".") + ")"
+		}
+	}
+	return "clientx: graphql: " + strings.Join(messages, "; ")
+}
+
+// GraphQL sends query with variables to endpoint, see Client.GraphQL
+func GraphQL(ctx context.Context, endpoint, query string, variables map[string]any, out any, opts ...OptionFunc) error {
+	return Default().GraphQL(ctx, endpoint, query, variables, out, opts...)
+}
+
+// GraphQL posts query and variables (nil for none) to endpoint as a GraphQL over HTTP request and
+// decodes the data of the response into out (nil discards it). Errors reported in the response
+// are returned as *GraphQLError, HTTP errors as *HTTPError. Like every POST, the request is only
+// retried with WithForceRetry
+func (c *Client) GraphQL(ctx context.Context, endpoint, query string, variables map[string]any, out any, opts ...OptionFunc) error {
+	payload := struct {
+		Query     string         `json:"query"`
+		Variables map[string]any `json:"variables,omitempty"`
+	}{query, variables}
+	headerOpt := WithHeaders(map[string]string{
+		"Accept": "application/graphql-response+json, application/json",
+	})
+	resp, err := DecodeJSON[struct {
+		Data   json.RawMessage      `json:"data"`
+		Errors []GraphQLErrorDetail `json:"errors"`
+	}](c.PostJSON(ctx, endpoint, payload, append([]OptionFunc{headerOpt}, opts...)...))
+	if err != nil {
+		return err
+	}
+	if out != nil && len(resp.Data) > 0 && string(resp.Data) != "null" {
+		if err := json.Unmarshal(resp.Data, out); err != nil {
+			return fmt.Errorf("JSON deserialization failed: %w", err)
+		}
+	}
+	if len(resp.Errors) > 0 {
+		return &GraphQLError{Errors: resp.Errors}
+	}
+	return nil
+}
@@ -0,0 +1,350 @@
+package clientx
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"github.com/chihqiang/gox/filex"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// HAR is an HTTP Archive 1.2 document, as written by HARRecorder and read by browsers,
+// proxies and API tools
+type HAR struct {
+	Log HARLog `json:"log"`
+}
+
+// HARLog is the root object of a HAR document
+type HARLog struct {
+	Version string     `json:"version"`
+	Creator HARCreator `json:"creator"`
+	Entries []HAREntry `json:"entries"`
+}
+
+// HARCreator names the application that wrote the archive
+type HARCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// HAREntry is one request sent and its response
+type HAREntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"` // Total milliseconds
+	Request         HARRequest  `json:"request"`
+	Response        HARResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         HARTimings  `json:"timings"`
+	Comment         string      `json:"comment,omitempty"`
+}
+
+// HARRequest is the request of an entry, credentials are left out
+type HARRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	QueryString []HARNameValue `json:"queryString"`
+	PostData    *HARPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARResponse is the response of an entry
+type HARResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []HARCookie    `json:"cookies"`
+	Headers     []HARNameValue `json:"headers"`
+	Content     HARContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+// HARNameValue is a header or query parameter
+type HARNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HARCookie is a cookie sent or set
+type HARCookie struct {
+	Name     string `json:"name"`
+	Value    string `json:"value"`
+	Path     string `json:"path,omitempty"`
+	Domain   string `json:"domain,omitempty"`
+	Expires  string `json:"expires,omitempty"`
+	HTTPOnly bool   `json:"httpOnly,omitempty"`
+	Secure   bool   `json:"secure,omitempty"`
+}
+
+// HARPostData is the body of a request
+type HARPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Comment  string `json:"comment,omitempty"` // Set when Text is truncated
+}
+
+// HARContent is the decoded body of a response, Encoding is "base64" for binary bodies
+type HARContent struct {
+	Size        int    `json:"size"`
+	Compression int    `json:"compression,omitempty"` // Bytes saved by Content-Encoding
+	MimeType    string `json:"mimeType"`
+	Text        string `json:"text,omitempty"`
+	Encoding    string `json:"encoding,omitempty"`
+}
+
+// HARTimings splits the time of an entry in milliseconds, -1 when not measured
+type HARTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// Body decodes the text of the content
+func (c HARContent) Body() ([]byte, error) {
+	if c.Encoding == "base64" {
+		return base64.StdEncoding.DecodeString(c.Text)
+	}
+	return []byte(c.Text), nil
+}
+
+// ReadHAR reads the HAR document at path
+func ReadHAR(path string) (*HAR, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var h HAR
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// harRedactedHeaders are left out of recorded requests
+var harRedactedHeaders = map[string]bool{"Authorization": true, "Proxy-Authorization": true, "Cookie": true}
+
+// HARRecorder collects the traffic of the clients it is passed to with WithHAR
+type HARRecorder struct {
+	mu      sync.Mutex
+	entries []HAREntry
+}
+
+// NewHARRecorder creates an empty recorder
+func NewHARRecorder() *HARRecorder {
+	return &HARRecorder{}
+}
+
+// WithHAR records every attempt into r, including retries, with the Authorization,
+// Proxy-Authorization and Cookie request headers left out. Response bodies are read into
+// memory, except event streams which are recorded without their body; request bodies are
+// recorded up to 64 KiB
+func WithHAR(r *HARRecorder) OptionFunc {
+	return func(o *Option) { o.HAR = r }
+}
+
+// HAR returns the entries recorded so far as a HAR document
+func (r *HARRecorder) HAR() *HAR {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &HAR{Log: HARLog{
+		Version: "1.2",
+		Creator: HARCreator{Name: "clientx", Version: "1.0"},
+		Entries: append([]HAREntry{}, r.entries...),
+	}}
+}
+
+// Save writes the entries recorded so far to path
+func (r *HARRecorder) Save(path string) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // Keep URLs readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r.HAR()); err != nil {
+		return err
+	}
+	if err := filex.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return filex.AtomicWrite(path, buf.Bytes())
+}
+
+// Reset drops the recorded entries
+func (r *HARRecorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+}
+
+// harMiddleware records the request handed to the transport and its response
+func harMiddleware(r *HARRecorder) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			entry := HAREntry{Request: harRequest(req)}
+			begin := time.Now()
+			entry.StartedDateTime = begin.Format("2006-01-02T15:04:05.000Z07:00")
+			resp, err := next(req)
+			wait := time.Since(begin)
+			if err != nil {
+				entry.Response = HARResponse{Cookies: []HARCookie{}, Headers: []HARNameValue{}, HeadersSize: -1, BodySize: -1}
+				entry.Comment = err.Error()
+				entry.Timings = HARTimings{Send: -1, Wait: millis(wait), Receive: -1}
+				entry.Time = millis(wait)
+				r.add(entry)
+				return resp, err
+			}
+
+			var body []byte
+			streamed := strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+			if resp.Body != nil && !streamed {
+				var readErr error
+				body, readErr = io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				if readErr != nil {
+					return nil, readErr
+				}
+				resp.Body = io.NopCloser(bytes.NewReader(body))
+			}
+			total := time.Since(begin)
+			entry.Response = harResponse(resp, body)
+			if streamed {
+				entry.Response.BodySize = -1
+				entry.Comment = "event stream, body not recorded"
+			}
+			entry.Timings = HARTimings{Send: 0, Wait: millis(wait), Receive: millis(total - wait)}
+			entry.Time = millis(total)
+			r.add(entry)
+			return resp, nil
+		}
+	}
+}
+
+// add appends an entry
+func (r *HARRecorder) add(e HAREntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = append(r.entries, e)
+}
+
+// harBodyLimit is the number of request body bytes recorded
+const harBodyLimit = 64 << 10
+
+// harRequest records req, its body is read through GetBody and cut at harBodyLimit
+func harRequest(req *http.Request) HARRequest {
+	h := HARRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []HARCookie{},
+		Headers:     harHeaders(req.Header, harRedactedHeaders),
+		QueryString: []HARNameValue{},
+		HeadersSize: -1,
+		BodySize:    0,
+	}
+	if h.HTTPVersion == "" {
+		h.HTTPVersion = "HTTP/1.1"
+	}
+	query := req.URL.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, v := range query[name] {
+			h.QueryString = append(h.QueryString, HARNameValue{Name: name, Value: v})
+		}
+	}
+	if req.Body != nil && req.Body != http.NoBody {
+		h.BodySize = -1
+		if req.GetBody != nil {
+			if body, err := req.GetBody(); err == nil {
+				data, _ := io.ReadAll(io.LimitReader(body, harBodyLimit+1))
+				_ = body.Close()
+				h.BodySize = len(data)
+				h.PostData = &HARPostData{MimeType: req.Header.Get("Content-Type")}
+				if len(data) > harBodyLimit {
+					// Uploads are not buffered whole just to be recorded
+					data = data[:harBodyLimit]
+					h.BodySize = max(int(req.ContentLength), -1)
+					h.PostData.Comment = "body truncated at 64 KiB"
+				}
+				h.PostData.Text = string(data)
+			}
+		}
+	}
+	return h
+}
+
+// harResponse records resp with its body
+func harResponse(resp *http.Response, body []byte) HARResponse {
+	h := HARResponse{
+		Status:      resp.StatusCode,
+		StatusText:  http.StatusText(resp.StatusCode),
+		HTTPVersion: resp.Proto,
+		Cookies:     []HARCookie{},
+		Headers:     harHeaders(resp.Header, nil),
+		Content:     HARContent{Size: len(body), MimeType: resp.Header.Get("Content-Type")},
+		RedirectURL: resp.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	for _, c := range resp.Cookies() {
+		cookie := HARCookie{Name: c.Name, Value: c.Value, Path: c.Path, Domain: c.Domain, HTTPOnly: c.HttpOnly, Secure: c.Secure}
+		if !c.Expires.IsZero() {
+			cookie.Expires = c.Expires.UTC().Format(time.RFC3339)
+		}
+		h.Cookies = append(h.Cookies, cookie)
+	}
+	// The content is recorded decoded, as browsers do
+	decoded := &http.Response{Header: resp.Header.Clone(), Body: io.NopCloser(bytes.NewReader(body))}
+	decodeResponse(decoded)
+	if decoded.Uncompressed {
+		if data, err := io.ReadAll(decoded.Body); err == nil {
+			h.Content.Size, h.Content.Compression = len(data), max(len(data)-len(body), 0)
+			body = data
+		}
+	}
+	if utf8.Valid(body) {
+		h.Content.Text = string(body)
+	} else {
+		h.Content.Text = base64.StdEncoding.EncodeToString(body)
+		h.Content.Encoding = "base64"
+	}
+	return h
+}
+
+// harHeaders lists headers sorted by name, leaving out those in skip
+func harHeaders(header http.Header, skip map[string]bool) []HARNameValue {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		if !skip[http.CanonicalHeaderKey(name)] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	pairs := make([]HARNameValue, 0, len(names))
+	for _, name := range names {
+		for _, v := range header[name] {
+			pairs = append(pairs, HARNameValue{Name: name, Value: v})
+		}
+	}
+	return pairs
+}
+
+// millis converts d to fractional milliseconds
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
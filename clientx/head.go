@@ -7,5 +7,10 @@ import (
 
 // Head request
 func Head(ctx context.Context, url string, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodHead, url, nil, opts...)
+	return Default().Head(ctx, url, opts...)
+}
+
+// Head request
+func (c *Client) Head(ctx context.Context, url string, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodHead, url, nil, opts...)
 }
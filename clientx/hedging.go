@@ -0,0 +1,106 @@
+package clientx
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// WithHedging sends up to maxHedges duplicates of an attempt, one more each time delay passes
+// without a response, and keeps the first response; the other requests are cancelled
+// Like retries, hedging only applies to GET and HEAD unless WithForceRetry is set
+func WithHedging(delay time.Duration, maxHedges int) OptionFunc {
+	return func(o *Option) {
+		o.HedgeDelay = delay
+		o.MaxHedges = maxHedges
+	}
+}
+
+// hedgeResult is the outcome of one hedged request
+type hedgeResult struct {
+	resp   *http.Response
+	err    error
+	index  int // Position in the launch order
+	cancel context.CancelFunc
+}
+
+// hedgingMiddleware races duplicates of slow requests
+func hedgingMiddleware(delay time.Duration, maxHedges int, force bool) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			method := strings.ToUpper(req.Method)
+			if !force && method != http.MethodGet && method != http.MethodHead {
+				return next(req)
+			}
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				return next(req) // The body cannot be sent twice
+			}
+
+			results := make(chan hedgeResult, maxHedges+1)
+			var cancels []context.CancelFunc
+			launch := func(r *http.Request) {
+				ctx, cancel := context.WithCancel(req.Context())
+				index := len(cancels)
+				cancels = append(cancels, cancel)
+				go func() {
+					resp, err := next(r.WithContext(ctx))
+					results <- hedgeResult{resp: resp, err: err, index: index, cancel: cancel}
+				}()
+			}
+			launch(req)
+			pending, sent := 1, 0
+			timer := time.NewTimer(delay)
+			defer timer.Stop()
+
+			var firstErr error
+			for {
+				select {
+				case <-timer.C:
+					if sent >= maxHedges {
+						continue
+					}
+					hedge := req.Clone(req.Context())
+					if req.GetBody != nil {
+						body, err := req.GetBody()
+						if err != nil {
+							continue
+						}
+						hedge.Body = body
+					}
+					launch(hedge)
+					pending++
+					sent++
+					timer.Reset(delay)
+				case r := <-results:
+					pending--
+					if r.err != nil {
+						r.cancel()
+						if firstErr == nil {
+							firstErr = r.err
+						}
+						if pending > 0 {
+							continue // Another request may still answer
+						}
+						return nil, firstErr
+					}
+					// Cancel the losers and release them in the background
+					for i, cancel := range cancels {
+						if i != r.index {
+							cancel()
+						}
+					}
+					go func(pending int) {
+						for ; pending > 0; pending-- {
+							if loser := <-results; loser.resp != nil {
+								_ = loser.resp.Body.Close()
+							}
+						}
+					}(pending)
+					r.resp.Body = &cancelBody{ReadCloser: r.resp.Body, cancel: r.cancel}
+					return r.resp, nil
+				}
+			}
+		}
+	}
+}
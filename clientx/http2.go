@@ -0,0 +1,96 @@
+package clientx
+
+import (
+	"context"
+	"crypto/tls"
+	"golang.org/x/net/http2"
+	"net"
+	"net/http"
+	"time"
+)
+
+// HTTP2Option HTTP/2 configuration structure
+type HTTP2Option struct {
+	ReadIdleTimeout            time.Duration // Ping a connection idle for this long to check its health, 0 disables
+	PingTimeout                time.Duration // Close the connection when a ping is not answered in time, default 15s
+	WriteByteTimeout           time.Duration // Close the connection when a write makes no progress for this long
+	StrictMaxConcurrentStreams bool          // Queue requests beyond the server stream limit instead of opening connections
+	h2c                        bool          // Prior-knowledge HTTP/2 for http:// URLs
+}
+
+// HTTP2OptionFunc functional configuration type
+type HTTP2OptionFunc func(*HTTP2Option)
+
+// WithHealthCheck pings connections idle for interval and closes those not answering within timeout
+func WithHealthCheck(interval, timeout time.Duration) HTTP2OptionFunc {
+	return func(o *HTTP2Option) {
+		o.ReadIdleTimeout = interval
+		o.PingTimeout = timeout
+	}
+}
+
+// WithWriteByteTimeout closes connections whose writes make no progress for d
+func WithWriteByteTimeout(d time.Duration) HTTP2OptionFunc {
+	return func(o *HTTP2Option) { o.WriteByteTimeout = d }
+}
+
+// WithStrictMaxConcurrentStreams keeps one connection per host and queues requests beyond the
+// MAX_CONCURRENT_STREAMS announced by the server, instead of dialing more connections
+func WithStrictMaxConcurrentStreams() HTTP2OptionFunc {
+	return func(o *HTTP2Option) { o.StrictMaxConcurrentStreams = true }
+}
+
+// WithHTTP2 negotiates HTTP/2 over TLS, only honored by New
+// The default transport speaks HTTP/1.1 only, since it carries its own TLS configuration
+func WithHTTP2(opts ...HTTP2OptionFunc) OptionFunc {
+	return func(o *Option) {
+		if o.http2 == nil {
+			o.http2 = &HTTP2Option{}
+		}
+		for _, opt := range opts {
+			opt(o.http2)
+		}
+	}
+}
+
+// WithH2C is WithHTTP2 also speaking HTTP/2 without TLS to http:// URLs (prior knowledge),
+// as expected by gRPC gateways and internal h2c services, only honored by New
+// Servers that only speak HTTP/1.1 cannot be reached over http:// any more
+func WithH2C(opts ...HTTP2OptionFunc) OptionFunc {
+	return func(o *Option) {
+		WithHTTP2(opts...)(o)
+		o.http2.h2c = true
+	}
+}
+
+// configureHTTP2 enables HTTP/2 on t, after every other transport setting has been applied
+func configureHTTP2(t *http.Transport, o *HTTP2Option) {
+	t2, err := http2.ConfigureTransports(t)
+	if err != nil {
+		return // Already configured
+	}
+	o.apply(t2)
+	if !o.h2c {
+		return
+	}
+	dial := t.DialContext
+	if dial == nil {
+		dial = (&net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}).DialContext
+	}
+	h2c := &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return dial(ctx, network, addr)
+		},
+	}
+	o.apply(h2c)
+	t.RegisterProtocol("http", h2c)
+}
+
+// apply copies the settings to an HTTP/2 transport
+func (o *HTTP2Option) apply(t *http2.Transport) {
+	t.ReadIdleTimeout = o.ReadIdleTimeout
+	t.PingTimeout = o.PingTimeout
+	t.WriteByteTimeout = o.WriteByteTimeout
+	t.StrictMaxConcurrentStreams = o.StrictMaxConcurrentStreams
+}
@@ -0,0 +1,171 @@
+package clientx
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/chihqiang/gox/logx"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// attemptKey carries the attempt number in the request context
+type attemptKey struct{}
+
+// AttemptFromContext returns the attempt number of a request sent by clientx, 0 for the first try
+// and n for the n-th retry; middlewares read it from req.Context()
+func AttemptFromContext(ctx context.Context) int {
+	n, _ := ctx.Value(attemptKey{}).(int)
+	return n
+}
+
+// LogOption logging middleware configuration structure
+type LogOption struct {
+	Level         logx.Level // Level of successful requests, non-2xx use Warn and errors use Error
+	Headers       bool       // Log request and response headers
+	Bodies        bool       // Log request and response bodies, truncated to MaxBodySize
+	MaxBodySize   int        // Bytes of each body logged, default 1024
+	RedactHeaders []string   // Headers logged as "[REDACTED]", defaults to credentials and cookies
+}
+
+// LogOptionFunc functional configuration type
+type LogOptionFunc func(*LogOption)
+
+// WithLogLevel sets the level of successful requests
+func WithLogLevel(level logx.Level) LogOptionFunc {
+	return func(o *LogOption) { o.Level = level }
+}
+
+// WithLogHeaders logs request and response headers, sensitive ones redacted
+func WithLogHeaders() LogOptionFunc {
+	return func(o *LogOption) { o.Headers = true }
+}
+
+// WithLogBodies logs request and response bodies truncated to maxSize bytes (0 keeps the default)
+func WithLogBodies(maxSize int) LogOptionFunc {
+	return func(o *LogOption) {
+		o.Bodies = true
+		if maxSize > 0 {
+			o.MaxBodySize = maxSize
+		}
+	}
+}
+
+// WithRedactHeaders adds headers whose values are never logged
+func WithRedactHeaders(headers ...string) LogOptionFunc {
+	return func(o *LogOption) { o.RedactHeaders = append(o.RedactHeaders, headers...) }
+}
+
+// LoggingMiddleware logs every attempt with method, URL, status, duration and attempt number
+// A nil logger logs through the logx package functions. Bodies are read through GetBody on the
+// request side and buffered on the response side, so the caller still receives them whole
+func LoggingMiddleware(logger *logx.Logger, opts ...LogOptionFunc) Middleware {
+	o := &LogOption{
+		Level:       logx.LevelInfo,
+		MaxBodySize: 1024,
+		RedactHeaders: []string{
+			"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie", "X-Api-Key", "X-Amz-Security-Token",
+		},
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	redact := make(map[string]bool, len(o.RedactHeaders))
+	for _, h := range o.RedactHeaders {
+		redact[http.CanonicalHeaderKey(h)] = true
+	}
+	logf := func(level logx.Level, format string, v ...any) {
+		if logger == nil {
+			_ = logx.Log(level, format, v...)
+			return
+		}
+		_ = logger.Log(level, format, v...)
+	}
+
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			var details []string
+			if o.Headers {
+				details = append(details, "request headers: "+formatHeaders(req.Header, redact))
+			}
+			if o.Bodies && req.GetBody != nil {
+				if body, err := req.GetBody(); err == nil {
+					details = append(details, "request body: "+readLimited(body, o.MaxBodySize))
+					_ = body.Close()
+				}
+			}
+
+			begin := time.Now()
+			resp, err := next(req)
+			elapsed := time.Since(begin)
+			line := fmt.Sprintf("clientx: %s %s attempt=%d duration=%s",
+				req.Method, req.URL.Redacted(), AttemptFromContext(req.Context())+1, elapsed.Round(time.Microsecond))
+			if err != nil {
+				logf(logx.LevelError, "%s error: %v%s", line, err, joinDetails(details))
+				return resp, err
+			}
+
+			if o.Headers {
+				details = append(details, "response headers: "+formatHeaders(resp.Header, redact))
+			}
+			if o.Bodies && resp.Body != nil {
+				// Log a prefix of the body and hand the caller a reader replaying it
+				prefix, _ := io.ReadAll(io.LimitReader(resp.Body, int64(o.MaxBodySize)+1))
+				details = append(details, "response body: "+truncated(prefix, o.MaxBodySize))
+				resp.Body = struct {
+					io.Reader
+					io.Closer
+				}{io.MultiReader(bytes.NewReader(prefix), resp.Body), resp.Body}
+			}
+			level := o.Level
+			if resp.StatusCode >= 400 {
+				level = logx.LevelWarn
+			}
+			logf(level, "%s status=%d%s", line, resp.StatusCode, joinDetails(details))
+			return resp, nil
+		}
+	}
+}
+
+// formatHeaders renders headers sorted by name, redacting sensitive values
+func formatHeaders(h http.Header, redact map[string]bool) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, name := range names {
+		value := strings.Join(h[name], ", ")
+		if redact[http.CanonicalHeaderKey(name)] {
+			value = "[REDACTED]"
+		}
+		parts[i] = name + ": " + value
+	}
+	return "{" + strings.Join(parts, "; ") + "}"
+}
+
+// readLimited reads up to limit bytes of r for logging
+func readLimited(r io.Reader, limit int) string {
+	b, _ := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+	return truncated(b, limit)
+}
+
+// truncated quotes b, cut to limit bytes
+func truncated(b []byte, limit int) string {
+	if len(b) > limit {
+		return fmt.Sprintf("%q...", b[:limit])
+	}
+	return fmt.Sprintf("%q", b)
+}
+
+// joinDetails appends optional log details on separate lines
+func joinDetails(details []string) string {
+	if len(details) == 0 {
+		return ""
+	}
+	return "\n  " + strings.Join(details, "\n  ")
+}
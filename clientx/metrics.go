@@ -0,0 +1,42 @@
+package clientx
+
+import (
+	"github.com/chihqiang/gox/metricsx"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// MetricsMiddleware records client metrics for every attempt, labeled by method and host:
+// http_client_requests_total{method,host,class} (class is 2xx..5xx or error),
+// http_client_request_duration_seconds{method,host}, http_client_retries_total{method,host}
+// and http_client_requests_in_flight{method,host}. A nil provider uses metricsx.Default()
+func MetricsMiddleware(p metricsx.Provider) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			provider := p
+			if provider == nil {
+				provider = metricsx.Default()
+			}
+			labels := metricsx.Labels{"method": req.Method, "host": req.URL.Host}
+			if AttemptFromContext(req.Context()) > 0 {
+				provider.Counter("http_client_retries_total", "HTTP client retry attempts", labels).Inc()
+			}
+			inFlight := provider.Gauge("http_client_requests_in_flight", "HTTP client requests awaiting a response", labels)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			begin := time.Now()
+			resp, err := next(req)
+			class := "error"
+			if err == nil {
+				class = strconv.Itoa(resp.StatusCode/100) + "xx"
+			}
+			provider.Counter("http_client_requests_total", "HTTP client requests sent",
+				metricsx.Labels{"method": req.Method, "host": req.URL.Host, "class": class}).Inc()
+			provider.Histogram("http_client_request_duration_seconds", "HTTP client request latency", nil,
+				labels).Observe(time.Since(begin).Seconds())
+			return resp, err
+		}
+	}
+}
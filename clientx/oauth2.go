@@ -0,0 +1,176 @@
+package clientx
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Token is an OAuth2 access token
+type Token struct {
+	AccessToken string    `json:"access_token"`
+	TokenType   string    `json:"token_type"` // Defaults to Bearer
+	Expiry      time.Time `json:"-"`          // Zero means the token does not expire
+}
+
+// Valid reports whether the token is set and not expired
+func (t *Token) Valid() bool {
+	return t != nil && t.AccessToken != "" && (t.Expiry.IsZero() || time.Now().Before(t.Expiry))
+}
+
+// authorization returns the Authorization header value
+func (t *Token) authorization() string {
+	typ := t.TokenType
+	if typ == "" || strings.EqualFold(typ, "bearer") {
+		typ = "Bearer"
+	}
+	return typ + " " + t.AccessToken
+}
+
+// TokenSource supplies tokens to OAuth2Middleware
+// Sources that cache tokens should also implement Invalidate(*Token), which is called when the
+// server rejects a token with 401 so the next call to Token fetches a new one
+type TokenSource interface {
+	Token(ctx context.Context) (*Token, error)
+}
+
+// TokenFunc fetches a new token
+type TokenFunc func(ctx context.Context) (*Token, error)
+
+// Token implements TokenSource, fetching a token on every call
+func (f TokenFunc) Token(ctx context.Context) (*Token, error) {
+	return f(ctx)
+}
+
+// StaticToken returns a TokenSource always returning token
+func StaticToken(token string) TokenSource {
+	t := &Token{AccessToken: token}
+	return TokenFunc(func(context.Context) (*Token, error) { return t, nil })
+}
+
+// CachedTokenSource caches the token of fetch until it expires or is invalidated
+// Concurrent callers share a single refresh
+type CachedTokenSource struct {
+	mu    sync.Mutex
+	fetch TokenFunc
+	early time.Duration
+	token *Token
+}
+
+// NewCachedTokenSource caches the tokens of fetch, refreshing them early before they expire
+func NewCachedTokenSource(fetch TokenFunc, early time.Duration) *CachedTokenSource {
+	return &CachedTokenSource{fetch: fetch, early: early}
+}
+
+// Token returns the cached token, fetching a new one when it is missing or about to expire
+func (s *CachedTokenSource) Token(ctx context.Context) (*Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token.Valid() && (s.token.Expiry.IsZero() || time.Now().Add(s.early).Before(s.token.Expiry)) {
+		return s.token, nil
+	}
+	t, err := s.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.token = t
+	return t, nil
+}
+
+// Invalidate drops the cached token if it is still t, so callers rejected with the same token
+// trigger only one refresh
+func (s *CachedTokenSource) Invalidate(t *Token) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token == t {
+		s.token = nil
+	}
+}
+
+// ClientCredentials returns a TokenFunc requesting tokens with the OAuth2 client credentials grant
+// Wrap it in NewCachedTokenSource to reuse tokens until they expire
+func ClientCredentials(tokenURL, clientID, clientSecret string, scopes ...string) TokenFunc {
+	return func(ctx context.Context) (*Token, error) {
+		form := url.Values{"grant_type": {"client_credentials"}}
+		if len(scopes) > 0 {
+			form.Set("scope", strings.Join(scopes, " "))
+		}
+		// Credentials are form-encoded before being used for basic auth (RFC 6749 2.3.1)
+		headerOpt := WithHeaders(map[string]string{
+			"Accept":        "application/json",
+			"Authorization": "Basic " + basicAuth(url.QueryEscape(clientID), url.QueryEscape(clientSecret)),
+		})
+		resp, err := DecodeJSON[struct {
+			Token
+			ExpiresIn int64 `json:"expires_in"`
+		}](PostForm(ctx, tokenURL, form, headerOpt))
+		if err != nil {
+			return nil, fmt.Errorf("clientx: fetch token failed: %w", err)
+		}
+		if resp.AccessToken == "" {
+			return nil, errors.New("clientx: token response has no access_token")
+		}
+		t := resp.Token
+		if resp.ExpiresIn > 0 {
+			t.Expiry = time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+		}
+		return &t, nil
+	}
+}
+
+// OAuth2Middleware sets the Authorization header from src on every request
+// A 401 response invalidates the token and the request is replayed once with a fresh one,
+// provided its body can be obtained again (always the case for requests sent by clientx)
+func OAuth2Middleware(src TokenSource) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			token, err := src.Token(req.Context())
+			if err != nil {
+				return nil, err
+			}
+			resp, err := next(authorize(req, token))
+			if err != nil || resp.StatusCode != http.StatusUnauthorized {
+				return resp, err
+			}
+			if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+				return resp, nil // The body is gone, leave the 401 to the caller
+			}
+
+			if inv, ok := src.(interface{ Invalidate(*Token) }); ok {
+				inv.Invalidate(token)
+			}
+			fresh, err := src.Token(req.Context())
+			if err != nil || fresh.AccessToken == token.AccessToken {
+				return resp, nil // Nothing new to try
+			}
+			retry := authorize(req, fresh)
+			if req.GetBody != nil {
+				if retry.Body, err = req.GetBody(); err != nil {
+					return resp, nil
+				}
+			}
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			_ = resp.Body.Close()
+			return next(retry)
+		}
+	}
+}
+
+// authorize returns a copy of req carrying token
+func authorize(req *http.Request, token *Token) *http.Request {
+	r := req.Clone(req.Context())
+	r.Header.Set("Authorization", token.authorization())
+	return r
+}
+
+// basicAuth encodes credentials for the Basic scheme
+func basicAuth(username, password string) string {
+	return base64.StdEncoding.EncodeToString([]byte(username + ":" + password))
+}
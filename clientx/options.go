@@ -7,5 +7,10 @@ import (
 
 // Options request
 func Options(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodOptions, url, body, opts...)
+	return Default().Options(ctx, url, body, opts...)
+}
+
+// Options request
+func (c *Client) Options(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodOptions, url, body, opts...)
 }
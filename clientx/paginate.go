@@ -0,0 +1,187 @@
+package clientx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/convx"
+	"github.com/chihqiang/gox/jsonx"
+	"io"
+	"iter"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Page is a fetched page of a list API, handed to a NextPageFunc
+type Page struct {
+	URL    *url.URL    // URL the page was fetched from
+	Header http.Header // Response headers
+	Body   []byte      // Response body
+	Items  int         // Number of items on the page
+}
+
+// NextPageFunc returns the URL of the page after p, "" on the last page
+type NextPageFunc func(p *Page) (string, error)
+
+// Pages describes how a list API is paged
+type Pages struct {
+	Items    string       // JSON path of the item array, e.g. "data" or "result.items"; "" when the body is the array
+	Next     NextPageFunc // Finds the next page, default LinkNext()
+	MaxPages int          // Stops after this many pages, 0 means no limit
+	Client   *Client      // Sends the requests, default Default()
+}
+
+// LinkNext follows the rel="next" link of the Link header (RFC 8288), as GitHub and GitLab send
+func LinkNext() NextPageFunc {
+	return func(p *Page) (string, error) {
+		for _, header := range p.Header.Values("Link") {
+			for _, link := range strings.Split(header, ",") {
+				target, params, ok := strings.Cut(link, ";")
+				target = strings.TrimSpace(target)
+				if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+					continue
+				}
+				for _, param := range strings.Split(params, ";") {
+					name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+					if !strings.EqualFold(name, "rel") || !hasToken(strings.Trim(value, `"`), "next") {
+						continue
+					}
+					next, err := p.URL.Parse(target[1 : len(target)-1])
+					if err != nil {
+						return "", fmt.Errorf("clientx: invalid Link header: %w", err)
+					}
+					return next.String(), nil
+				}
+			}
+		}
+		return "", nil
+	}
+}
+
+// hasToken reports whether the space separated list s contains token
+func hasToken(s, token string) bool {
+	for _, t := range strings.Fields(s) {
+		if strings.EqualFold(t, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// CursorNext reads the cursor of the next page from the JSON path cursorPath of the body, e.g.
+// "meta.next_cursor", and sends it as the query parameter param; a missing, null or empty
+// cursor ends the list
+func CursorNext(cursorPath, param string) NextPageFunc {
+	return func(p *Page) (string, error) {
+		v, err := jsonx.Get(p.Body, cursorPath)
+		if errors.Is(err, jsonx.ErrNotFound) || v == nil {
+			return "", nil
+		}
+		if err != nil {
+			return "", err
+		}
+		cursor, err := convx.ToStringE(v)
+		if err != nil || cursor == "" {
+			return "", err
+		}
+		return withQueryParam(p.URL, param, cursor), nil
+	}
+}
+
+// PageNext increments the query parameter pageParam, starting from 1 when the first URL has
+// none, and sends limit items per page in limitParam ("" leaves the page size to the server).
+// A page with fewer than limit items, or none, is the last one
+func PageNext(pageParam, limitParam string, limit int) NextPageFunc {
+	return func(p *Page) (string, error) {
+		if p.Items == 0 || limit > 0 && p.Items < limit {
+			return "", nil
+		}
+		page, err := strconv.Atoi(p.URL.Query().Get(pageParam))
+		if err != nil {
+			page = 1
+		}
+		next := withQueryParam(p.URL, pageParam, strconv.Itoa(page+1))
+		if limitParam != "" && limit > 0 {
+			u, _ := url.Parse(next)
+			next = withQueryParam(u, limitParam, strconv.Itoa(limit))
+		}
+		return next, nil
+	}
+}
+
+// withQueryParam returns u with the query parameter name set to value
+func withQueryParam(u *url.URL, name, value string) string {
+	next := *u
+	query := next.Query()
+	query.Set(name, value)
+	next.RawQuery = query.Encode()
+	return next.String()
+}
+
+// Paginate fetches the pages of a list API starting at url and yields their items one by one,
+// fetching the next page only when the items of the current one are consumed. Iteration stops
+// on the last page, at MaxPages, when the loop breaks, or after yielding an error
+func Paginate[T any](ctx context.Context, url string, pages Pages, opts ...OptionFunc) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		client := pages.Client
+		if client == nil {
+			client = Default()
+		}
+		next := pages.Next
+		if next == nil {
+			next = LinkNext()
+		}
+		headerOpt := WithHeaders(map[string]string{"Accept": "application/json"})
+		for n := 1; url != ""; n++ {
+			page, items, err := fetchPage[T](ctx, client, url, pages.Items, append(opts[:len(opts):len(opts)], headerOpt))
+			if err != nil {
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if pages.MaxPages > 0 && n >= pages.MaxPages {
+				return
+			}
+			if url, err = next(page); err != nil {
+				yield(zero, err)
+				return
+			}
+		}
+	}
+}
+
+// fetchPage gets one page and decodes the items at itemsPath
+func fetchPage[T any](ctx context.Context, c *Client, rawURL, itemsPath string, opts []OptionFunc) (*Page, []T, error) {
+	resp, err := c.Get(ctx, rawURL, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	raw := body
+	if itemsPath != "" {
+		v, err := jsonx.Get(body, itemsPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if raw, err = json.Marshal(v); err != nil {
+			return nil, nil, err
+		}
+	}
+	var items []T
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil, nil, fmt.Errorf("JSON deserialization failed: %w", err)
+	}
+	return &Page{URL: resp.Request.URL, Header: resp.Header, Body: body, Items: len(items)}, items, nil
+}
@@ -0,0 +1,26 @@
+package clientx
+
+import (
+	"context"
+	"net/http"
+)
+
+// Patch request
+func Patch(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return Default().Patch(ctx, url, body, opts...)
+}
+
+// Patch request
+func (c *Client) Patch(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodPatch, url, body, opts...)
+}
+
+// PatchJSON sends a PATCH request with payload serialized as JSON
+func PatchJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return Default().PatchJSON(ctx, url, payload, opts...)
+}
+
+// PatchJSON sends a PATCH request with payload serialized as JSON through c
+func (c *Client) PatchJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return c.requestJSON(ctx, http.MethodPatch, url, payload, opts...)
+}
@@ -1,15 +1,16 @@
 package clientx
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
+	"github.com/chihqiang/gox/encodingx"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"sync"
 )
 
 // Post wraps a standard POST request
@@ -18,33 +19,69 @@ import (
 // body: Request body byte data
 // opts: Optional configurations (retry, Headers, Middleware, etc.)
 func Post(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodPost, url, body, opts...)
+	return Default().Post(ctx, url, body, opts...)
+}
+
+// Post wraps a standard POST request sent through c
+func (c *Client) Post(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodPost, url, body, opts...)
 }
 
 // PostJSON sends a JSON request
 // Automatically serializes payload and sets Content-Type to application/json
 func PostJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return Default().PostJSON(ctx, url, payload, opts...)
+}
+
+// PostJSON sends a JSON request through c
+func (c *Client) PostJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return c.requestJSON(ctx, http.MethodPost, url, payload, opts...)
+}
+
+// requestJSON serializes payload and sends it with Content-Type application/json
+func (c *Client) requestJSON(ctx context.Context, method, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
 	// Serialize JSON
-	data, err := json.Marshal(payload)
+	codec, _ := encodingx.Lookup(encodingx.JSON)
+	data, err := codec.Marshal(payload)
 	if err != nil {
 		return nil, fmt.Errorf("JSON serialization failed: %w", err)
 	}
 	// Set Content-Type: application/json
 	headerOpt := WithHeaders(map[string]string{
-		"Content-Type": "application/json",
+		"Content-Type": codec.ContentType(),
 	})
-	// Call base Post method to send request
-	return Post(ctx, url, data, append(opts, headerOpt)...)
+	return c.Request(ctx, method, url, data, append(slices.Clip(opts), headerOpt)...)
 }
 
 // PostForm sends a form request with application/x-www-form-urlencoded content type
 func PostForm(ctx context.Context, url string, form url.Values, opts ...OptionFunc) (*http.Response, error) {
+	return Default().PostForm(ctx, url, form, opts...)
+}
+
+// PostForm sends a form request through c
+func (c *Client) PostForm(ctx context.Context, url string, form url.Values, opts ...OptionFunc) (*http.Response, error) {
 	// Set Content-Type
 	headerOpt := WithHeaders(map[string]string{
 		"Content-Type": "application/x-www-form-urlencoded",
 	})
 	// Encode form into URL query string format
-	return Post(ctx, url, []byte(form.Encode()), append(opts, headerOpt)...)
+	return c.Post(ctx, url, []byte(form.Encode()), append(slices.Clip(opts), headerOpt)...)
+}
+
+// PostFormStruct sends the fields of v as a form, named by their `form:"name,omitempty"` tags
+// Encoding follows WithQueryStruct: slices repeat the field, times honor `layout:"..."` tags
+// and the unix / unixmilli options
+func PostFormStruct(ctx context.Context, url string, v any, opts ...OptionFunc) (*http.Response, error) {
+	return Default().PostFormStruct(ctx, url, v, opts...)
+}
+
+// PostFormStruct sends the fields of v as a form through c
+func (c *Client) PostFormStruct(ctx context.Context, url string, v any, opts ...OptionFunc) (*http.Response, error) {
+	form, err := encodeValues(v, "form")
+	if err != nil {
+		return nil, err
+	}
+	return c.PostForm(ctx, url, form, opts...)
 }
 
 // File defines a single uploaded file structure
@@ -78,51 +115,234 @@ func OpenFile(fieldName, filename string) (File, error) {
 
 // PostMForm supports multipart/form-data uploads, including files and form fields
 func PostMForm(ctx context.Context, url string, data FormData, opts ...OptionFunc) (*http.Response, error) {
+	return Default().PostMForm(ctx, url, data, opts...)
+}
+
+// PostMForm sends a multipart/form-data upload through c
+// Parts are streamed into the request body through a pipe, so files are never held in memory
+// Files are rewound for retries when they implement io.Seeker and closed once the call returns;
+// the body is sent with a Content-Length when the size of every file is known
+func (c *Client) PostMForm(ctx context.Context, url string, data FormData, opts ...OptionFunc) (*http.Response, error) {
 	// Validation: must have at least files or form fields
-	if (data.Files == nil || len(data.Files) == 0) && (data.Fields == nil || len(data.Fields) == 0) {
+	if len(data.Files) == 0 && len(data.Fields) == 0 {
 		return nil, fmt.Errorf("upload failed: Files and Fields cannot be empty at the same time")
 	}
+	// Close files after upload (if they implement io.Closer)
+	defer func() {
+		for _, f := range data.Files {
+			if closer, ok := f.File.(io.Closer); ok {
+				_ = closer.Close()
+			}
+		}
+	}()
+
+	body, err := newMultipartBody(data)
+	if err != nil {
+		return nil, err
+	}
+	defer body.stop()
+	// Set Content-Type to multipart/form-data
+	headerOpt := WithHeaders(map[string]string{
+		"Content-Type": body.contentType,
+	})
+	return c.RequestReader(ctx, http.MethodPost, url, body.next, append(slices.Clip(opts), headerOpt)...)
+}
+
+// multipartBody streams FormData into a pipe, once per call of next
+type multipartBody struct {
+	data        FormData
+	boundary    string
+	contentType string
+	size        int64   // Encoded length, -1 when a file size is unknown
+	offsets     []int64 // Start offsets of the files, -1 when a file cannot be rewound
+	sizes       []int64 // Bytes to send of every file, -1 when unknown
 
-	// Buffer to store multipart data
-	var buf bytes.Buffer
-	writer := multipart.NewWriter(&buf)
+	mu      sync.Mutex
+	writers []*pipeWriter // Writers started by next, stopped by stop
+}
+
+// pipeWriter is a goroutine encoding the form into a pipe
+type pipeWriter struct {
+	reader *io.PipeReader
+	done   chan struct{} // Closed when the writer returns
+}
+
+func newMultipartBody(data FormData) (*multipartBody, error) {
+	writer := multipart.NewWriter(io.Discard)
+	b := &multipartBody{
+		data:        data,
+		boundary:    writer.Boundary(),
+		contentType: writer.FormDataContentType(),
+		offsets:     make([]int64, len(data.Files)),
+		sizes:       make([]int64, len(data.Files)),
+	}
+	for i, f := range data.Files {
+		b.offsets[i] = -1
+		if s, ok := f.File.(io.Seeker); ok {
+			offset, err := s.Seek(0, io.SeekCurrent)
+			if err == nil {
+				b.offsets[i] = offset
+			}
+		}
+		b.sizes[i] = readerSize(f.File)
+	}
+
+	// The encoded length is the multipart framing plus the size of every file
+	counter := &countWriter{}
+	if err := b.write(counter, nil); err != nil {
+		return nil, err
+	}
+	b.size = counter.n
+	for _, n := range b.sizes {
+		if n < 0 {
+			b.size = -1
+			break
+		}
+		b.size += n
+	}
+	return b, nil
+}
+
+// next is the BodyFunc of the upload, installed as GetBody too
+// Files implementing io.ReaderAt are read through their own io.SectionReader on every call, so
+// the bodies returned are independent: middlewares may read one while another is sent. Other
+// files are shared, a new call stops the previous writers and rewinds them
+func (b *multipartBody) next() (io.Reader, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	files, independent := b.readers()
+	if !independent && len(b.writers) > 0 {
+		// Stop the previous writers before touching the files they read
+		b.stopLocked()
+		for i, f := range b.data.Files {
+			if f.File == nil || b.sectioned(i) {
+				continue
+			}
+			if b.offsets[i] < 0 {
+				return nil, ErrBodyNotReplayable
+			}
+			if _, err := f.File.(io.Seeker).Seek(b.offsets[i], io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	pr, pw := io.Pipe()
+	w := &pipeWriter{reader: pr, done: make(chan struct{})}
+	b.writers = append(b.writers, w)
+	go func() {
+		defer close(w.done)
+		_ = pw.CloseWithError(b.write(pw, files))
+	}()
+	if b.size >= 0 {
+		return &sizedReader{ReadCloser: pr, size: b.size}, nil
+	}
+	return pr, nil
+}
+
+// readers returns the readers of the files for one body, and whether none is shared with
+// another body
+func (b *multipartBody) readers() ([]io.Reader, bool) {
+	files := make([]io.Reader, len(b.data.Files))
+	independent := true
+	for i, f := range b.data.Files {
+		if b.sectioned(i) {
+			files[i] = io.NewSectionReader(f.File.(io.ReaderAt), b.offsets[i], b.sizes[i])
+			continue
+		}
+		files[i] = f.File
+		independent = independent && f.File == nil
+	}
+	return files, independent
+}
+
+// sectioned reports whether file i is read through an io.SectionReader
+func (b *multipartBody) sectioned(i int) bool {
+	_, ok := b.data.Files[i].File.(io.ReaderAt)
+	return ok && b.offsets[i] >= 0 && b.sizes[i] >= 0
+}
+
+// stop ends every writer started by next
+func (b *multipartBody) stop() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.stopLocked()
+}
+
+func (b *multipartBody) stopLocked() {
+	for _, w := range b.writers {
+		_ = w.reader.Close()
+		<-w.done
+	}
+	b.writers = nil
+}
+
+// write encodes the form to w reading the files from files, leaving out the file contents when
+// files is nil
+func (b *multipartBody) write(w io.Writer, files []io.Reader) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(b.boundary); err != nil {
+		return err
+	}
 
 	// Write file part
-	for _, f := range data.Files {
+	for i, f := range b.data.Files {
 		// Create form file
 		part, err := writer.CreateFormFile(f.FieldName, f.FileName)
 		if err != nil {
-			return nil, fmt.Errorf("create form file %s failed: %w", f.FileName, err)
+			return fmt.Errorf("create form file %s failed: %w", f.FileName, err)
 		}
-		// Copy file content to multipart
-		if _, err := io.Copy(part, f.File); err != nil {
-			return nil, fmt.Errorf("copy file %s failed: %w", f.FileName, err)
+		if files == nil || files[i] == nil {
+			continue
 		}
-		// Close file after upload (if it implements io.Closer)
-		if closer, ok := f.File.(io.Closer); ok {
-			_ = closer.Close()
+		// Copy file content to multipart
+		if _, err := io.Copy(part, files[i]); err != nil {
+			return fmt.Errorf("copy file %s failed: %w", f.FileName, err)
 		}
 	}
 
 	// Write regular form fields
-	if data.Fields != nil {
-		for k, v := range data.Fields {
-			if err := writer.WriteField(k, v); err != nil {
-				return nil, fmt.Errorf("write form field %s failed: %w", k, err)
-			}
+	for k, v := range b.data.Fields {
+		if err := writer.WriteField(k, v); err != nil {
+			return fmt.Errorf("write form field %s failed: %w", k, err)
 		}
 	}
 
 	// Close writer to generate multipart boundary
 	if err := writer.Close(); err != nil {
-		return nil, fmt.Errorf("close multipart writer failed: %w", err)
+		return fmt.Errorf("close multipart writer failed: %w", err)
 	}
+	return nil
+}
 
-	// Set Content-Type to multipart/form-data
-	headerOpt := WithHeaders(map[string]string{
-		"Content-Type": writer.FormDataContentType(),
-	})
+// readerSize returns the bytes left in r, -1 when unknown
+func readerSize(r io.Reader) int64 {
+	switch v := r.(type) {
+	case nil:
+		return 0
+	case interface{ Len() int }:
+		return int64(v.Len())
+	case *os.File:
+		info, err := v.Stat()
+		if err != nil || !info.Mode().IsRegular() {
+			return -1
+		}
+		offset, err := v.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return -1
+		}
+		return info.Size() - offset
+	}
+	return -1
+}
+
+// countWriter counts the bytes written to it
+type countWriter struct {
+	n int64
+}
 
-	// Call Post to send request
-	return Post(ctx, url, buf.Bytes(), append(opts, headerOpt)...)
+// Write counts p
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
 }
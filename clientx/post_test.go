@@ -0,0 +1,77 @@
+package clientx
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPostMFormGetBodyIndependent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseMultipartForm(1 << 20); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var parts []string
+		for _, field := range []string{"doc", "note"} {
+			f, _, err := r.FormFile(field)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			data, _ := io.ReadAll(f)
+			parts = append(parts, string(data))
+		}
+		_, _ = io.WriteString(w, strings.Join(append(parts, r.FormValue("name")), ","))
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(path, []byte("file content"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Reads the body through GetBody before sending, like signing and dumping middlewares do
+	readGetBody := WithMiddleware(func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			_, _ = io.Copy(io.Discard, body)
+			_ = body.Close()
+			return next(req)
+		}
+	})
+	tests := map[string][]OptionFunc{
+		"getbody": {readGetBody},
+		"signer":  {WithSigner(NewHMACSigner("key", []byte("secret")))},
+		"debug":   {WithDebugCurl(io.Discard)},
+		"both":    {WithSigner(NewHMACSigner("key", []byte("secret"))), WithDebugCurl(io.Discard)},
+	}
+	for name, opts := range tests {
+		t.Run(name, func(t *testing.T) {
+			doc, err := OpenFile("doc", path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data := FormData{
+				Fields: map[string]string{"name": "gox"},
+				Files:  []File{doc, {FieldName: "note", FileName: "note.txt", File: strings.NewReader("note content")}},
+			}
+			resp, err := New().PostMForm(context.Background(), srv.URL, data, opts...)
+			if err != nil {
+				t.Fatalf("PostMForm: %v", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if got, want := string(body), "file content,note content,gox"; got != want {
+				t.Errorf("body = %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package clientx
+
+import "io"
+
+// ProgressFunc receives the bytes transferred so far and the total, total is -1 when unknown
+// It is called from the goroutine reading the body
+type ProgressFunc func(transferred, total int64)
+
+// WithProgress reports the progress of both request and response bodies
+func WithProgress(fn ProgressFunc) OptionFunc {
+	return func(o *Option) { o.UploadProgress, o.DownloadProgress = fn, fn }
+}
+
+// WithUploadProgress reports the progress of request bodies, restarting from 0 on every retry
+func WithUploadProgress(fn ProgressFunc) OptionFunc {
+	return func(o *Option) { o.UploadProgress = fn }
+}
+
+// WithDownloadProgress reports the progress of response bodies as the caller reads them
+func WithDownloadProgress(fn ProgressFunc) OptionFunc {
+	return func(o *Option) { o.DownloadProgress = fn }
+}
+
+// progressReader reports the bytes read through it
+type progressReader struct {
+	io.ReadCloser
+	transferred int64
+	total       int64
+	fn          ProgressFunc
+}
+
+// Read reads from the body and reports progress
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	if n > 0 {
+		r.transferred += int64(n)
+		r.fn(r.transferred, r.total)
+	}
+	return n, err
+}
+
+// withProgress wraps body to report to fn, starting at offset of total bytes
+func withProgress(body io.ReadCloser, offset, total int64, fn ProgressFunc) io.ReadCloser {
+	if fn == nil {
+		return body
+	}
+	if total <= 0 {
+		total = -1
+	}
+	return &progressReader{ReadCloser: body, transferred: offset, total: total, fn: fn}
+}
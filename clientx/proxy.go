@@ -0,0 +1,297 @@
+package clientx
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/cryptox"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// ErrProxyAuthRequired is returned when the proxy rejects the request with 407
+var ErrProxyAuthRequired = errors.New("clientx: proxy authentication required")
+
+// proxyKey carries the proxy function of a request in its context
+type proxyKey struct{}
+
+// proxyAuthKey carries the proxy credentials of a request in its context
+type proxyAuthKey struct{}
+
+// WithProxy routes requests through proxyURL (http, https or socks5), "" connects directly
+// Passed to New it applies to every request, passed to a request it only applies to that request
+// Requests without it use the HTTP_PROXY / HTTPS_PROXY / NO_PROXY environment variables
+func WithProxy(proxyURL string) OptionFunc {
+	var (
+		u   *url.URL
+		err error
+	)
+	if proxyURL != "" {
+		u, err = url.Parse(proxyURL)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			err = fmt.Errorf("clientx: invalid proxy URL %q", proxyURL)
+		}
+	}
+	return func(o *Option) {
+		if err != nil {
+			o.err = errors.Join(o.err, err)
+			return
+		}
+		o.Proxy = func(*http.Request) (*url.URL, error) { return u, nil }
+	}
+}
+
+// WithProxyFunc chooses the proxy of every request with fn, a nil URL connects directly
+func WithProxyFunc(fn func(*http.Request) (*url.URL, error)) OptionFunc {
+	return func(o *Option) { o.Proxy = fn }
+}
+
+// WithProxyAuth authenticates to the proxy, whether set by WithProxy or the environment
+// Credentials are sent upfront with the Basic scheme (username and password for socks5); a proxy
+// answering 407 with a Digest challenge gets the request again with a Digest response, for plain
+// HTTP requests as well as CONNECT tunnels. A proxy still refusing returns ErrProxyAuthRequired
+func WithProxyAuth(username, password string) OptionFunc {
+	a := &proxyAuth{username: username, password: password, challenges: make(map[string]*digestChallenge)}
+	return func(o *Option) { o.proxyAuth = a }
+}
+
+// proxyFromContext is the Proxy of the default transport, honoring WithProxy before the environment
+func proxyFromContext(req *http.Request) (*url.URL, error) {
+	var (
+		u   *url.URL
+		err error
+	)
+	if fn, ok := req.Context().Value(proxyKey{}).(func(*http.Request) (*url.URL, error)); ok {
+		u, err = fn(req)
+	} else {
+		u, err = http.ProxyFromEnvironment(req)
+	}
+	if a, ok := req.Context().Value(proxyAuthKey{}).(*proxyAuth); ok && u != nil && u.User == nil && a.challenge(u.Host) == nil {
+		// The transport turns the user info into Basic credentials, or socks5 username and password
+		withUser := *u
+		withUser.User = url.UserPassword(a.username, a.password)
+		u = &withUser
+	}
+	return u, err
+}
+
+// withProxy stores the proxy function in ctx for proxyFromContext
+func withProxy(ctx context.Context, fn func(*http.Request) (*url.URL, error)) context.Context {
+	if fn == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, proxyKey{}, fn)
+}
+
+// withProxyAuth stores the proxy credentials in ctx for the proxy hooks of the transport
+func withProxyAuth(ctx context.Context, a *proxyAuth) context.Context {
+	if a == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, proxyAuthKey{}, a)
+}
+
+// proxyConnectHeader is the GetProxyConnectHeader of the default transport, answering the
+// Digest challenge of the proxy on CONNECT
+func proxyConnectHeader(ctx context.Context, proxyURL *url.URL, target string) (http.Header, error) {
+	a, ok := ctx.Value(proxyAuthKey{}).(*proxyAuth)
+	if !ok {
+		return nil, nil
+	}
+	h := make(http.Header)
+	if auth := a.digest(proxyURL.Host, http.MethodConnect, target); auth != "" {
+		h.Set("Proxy-Authorization", auth)
+	}
+	return h, nil
+}
+
+// proxyConnectResponse is the OnProxyConnectResponse of the default transport, recording the
+// challenge of a 407 so the request can be replayed
+func proxyConnectResponse(ctx context.Context, proxyURL *url.URL, _ *http.Request, resp *http.Response) error {
+	if resp.StatusCode != http.StatusProxyAuthRequired {
+		return nil
+	}
+	if a, ok := ctx.Value(proxyAuthKey{}).(*proxyAuth); ok {
+		a.setChallenge(proxyURL.Host, resp.Header.Values("Proxy-Authenticate"))
+	}
+	return fmt.Errorf("%w by %s", ErrProxyAuthRequired, proxyURL.Redacted())
+}
+
+// proxyAuth holds proxy credentials and the Digest challenges received per proxy
+type proxyAuth struct {
+	username, password string
+
+	mu         sync.Mutex
+	challenges map[string]*digestChallenge // By proxy host
+}
+
+// digestChallenge is a parsed Proxy-Authenticate: Digest challenge
+type digestChallenge struct {
+	realm, nonce, opaque, algorithm string
+	qop                             bool   // The proxy offered qop=auth
+	nc                              uint32 // Requests sent with the nonce
+}
+
+// challenge returns the Digest challenge of a proxy, nil until it sent one
+func (a *proxyAuth) challenge(host string) *digestChallenge {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.challenges[host]
+}
+
+// setChallenge records the Digest challenge among values, reporting whether there was one
+func (a *proxyAuth) setChallenge(host string, values []string) bool {
+	for _, v := range values {
+		scheme, params, _ := strings.Cut(strings.TrimSpace(v), " ")
+		if !strings.EqualFold(scheme, "Digest") {
+			continue
+		}
+		p := parseAuthParams(params)
+		algorithm := strings.ToUpper(p["algorithm"])
+		if algorithm != "" && algorithm != "MD5" && algorithm != "SHA-256" {
+			continue
+		}
+		c := &digestChallenge{realm: p["realm"], nonce: p["nonce"], opaque: p["opaque"], algorithm: algorithm}
+		for _, q := range strings.Split(p["qop"], ",") {
+			c.qop = c.qop || strings.TrimSpace(q) == "auth"
+		}
+		a.mu.Lock()
+		a.challenges[host] = c
+		a.mu.Unlock()
+		return true
+	}
+	return false
+}
+
+// digest answers the challenge of a proxy for method and uri, "" without a challenge (RFC 7616)
+func (a *proxyAuth) digest(host, method, uri string) string {
+	a.mu.Lock()
+	c := a.challenges[host]
+	if c == nil {
+		a.mu.Unlock()
+		return ""
+	}
+	c.nc++
+	nc := fmt.Sprintf("%08x", c.nc)
+	a.mu.Unlock()
+
+	h := cryptox.MD5
+	if c.algorithm == "SHA-256" {
+		h = cryptox.SHA256
+	}
+	ha1 := h(a.username + ":" + c.realm + ":" + a.password)
+	ha2 := h(method + ":" + uri)
+	fields := []string{
+		fmt.Sprintf("username=%q", a.username),
+		fmt.Sprintf("realm=%q", c.realm),
+		fmt.Sprintf("nonce=%q", c.nonce),
+		fmt.Sprintf("uri=%q", uri),
+	}
+	if c.qop {
+		b := make([]byte, 8)
+		_, _ = rand.Read(b)
+		cnonce := hex.EncodeToString(b)
+		fields = append(fields, "qop=auth", "nc="+nc, fmt.Sprintf("cnonce=%q", cnonce),
+			fmt.Sprintf("response=%q", h(ha1+":"+c.nonce+":"+nc+":"+cnonce+":auth:"+ha2)))
+	} else {
+		fields = append(fields, fmt.Sprintf("response=%q", h(ha1+":"+c.nonce+":"+ha2)))
+	}
+	if c.algorithm != "" {
+		fields = append(fields, "algorithm="+c.algorithm)
+	}
+	if c.opaque != "" {
+		fields = append(fields, fmt.Sprintf("opaque=%q", c.opaque))
+	}
+	return "Digest " + strings.Join(fields, ", ")
+}
+
+// parseAuthParams parses the comma separated name=value pairs of a challenge, values may be quoted
+func parseAuthParams(s string) map[string]string {
+	params := make(map[string]string)
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimLeft(s, ", ") {
+		name, rest, ok := strings.Cut(s, "=")
+		if !ok {
+			break
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		rest = strings.TrimLeft(rest, " ")
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			var b strings.Builder
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				b.WriteByte(rest[i])
+			}
+			value, s = b.String(), rest[min(i+1, len(rest)):]
+		} else {
+			value, s, _ = strings.Cut(rest, ",")
+			value = strings.TrimSpace(value)
+		}
+		params[name] = value
+	}
+	return params
+}
+
+// proxyAuthMiddleware answers Digest challenges of the proxy, replaying the request once
+// Tunnelled requests are answered by the transport hooks, plain HTTP requests carry the
+// Proxy-Authorization header themselves
+func proxyAuthMiddleware(a *proxyAuth) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			proxyURL, _ := proxyFromContext(req)
+			if proxyURL == nil || proxyURL.Scheme == "socks5" || proxyURL.Scheme == "socks5h" {
+				return next(req)
+			}
+			tunnel := req.URL.Scheme == "https"
+			send := func() (*http.Response, error) {
+				r := req
+				if !tunnel {
+					if auth := a.digest(proxyURL.Host, req.Method, proxyRequestURI(req.URL)); auth != "" {
+						r = req.Clone(req.Context())
+						r.Header.Set("Proxy-Authorization", auth)
+					}
+				}
+				return next(r)
+			}
+
+			resp, err := send()
+			challenged := errors.Is(err, ErrProxyAuthRequired) && a.challenge(proxyURL.Host) != nil
+			if err == nil && resp.StatusCode == http.StatusProxyAuthRequired {
+				challenged = a.setChallenge(proxyURL.Host, resp.Header.Values("Proxy-Authenticate"))
+			}
+			if challenged && (req.Body == nil || req.Body == http.NoBody || req.GetBody != nil) {
+				if resp != nil {
+					_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+					_ = resp.Body.Close()
+				}
+				if req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return nil, err
+					}
+					req = req.Clone(req.Context())
+					req.Body = body
+				}
+				resp, err = send()
+			}
+			if err == nil && resp.StatusCode == http.StatusProxyAuthRequired {
+				_ = resp.Body.Close()
+				return nil, fmt.Errorf("%w by %s", ErrProxyAuthRequired, proxyURL.Redacted())
+			}
+			return resp, err
+		}
+	}
+}
+
+// proxyRequestURI is the absolute request target sent to an HTTP proxy
+func proxyRequestURI(u *url.URL) string {
+	return u.Scheme + "://" + u.Host + u.RequestURI()
+}
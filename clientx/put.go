@@ -7,5 +7,20 @@ import (
 
 // Put request
 func Put(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodPut, url, body, opts...)
+	return Default().Put(ctx, url, body, opts...)
+}
+
+// Put request
+func (c *Client) Put(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodPut, url, body, opts...)
+}
+
+// PutJSON sends a PUT request with payload serialized as JSON
+func PutJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return Default().PutJSON(ctx, url, payload, opts...)
+}
+
+// PutJSON sends a PUT request with payload serialized as JSON through c
+func (c *Client) PutJSON(ctx context.Context, url string, payload any, opts ...OptionFunc) (*http.Response, error) {
+	return c.requestJSON(ctx, http.MethodPut, url, payload, opts...)
 }
@@ -0,0 +1,232 @@
+package clientx
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithQuery merges values into the request URL, replacing parameters of the same name
+func WithQuery(values url.Values) OptionFunc {
+	return func(o *Option) {
+		if o.Query == nil {
+			o.Query = make(url.Values)
+		}
+		for k, v := range values {
+			o.Query[k] = append([]string(nil), v...)
+		}
+	}
+}
+
+// WithQueryStruct merges the fields of a struct (or a map with string keys) into the request URL
+// Fields are named by their `query:"name,omitempty"` tag, or the field name without one, and
+// "-" skips a field. Slices repeat the parameter, time.Time is formatted as RFC 3339 unless the
+// field has a `layout:"2006-01-02"` tag or the unix / unixmilli option, and
+// encoding.TextMarshaler is honored; an unsupported field makes the request fail
+func WithQueryStruct(v any) OptionFunc {
+	values, err := encodeValues(v, "query")
+	return func(o *Option) {
+		if err != nil {
+			o.err = errors.Join(o.err, err)
+			return
+		}
+		WithQuery(values)(o)
+	}
+}
+
+// mergeQuery sets values on the query of rawURL
+// Other parameters are kept byte for byte and in order, so signatures over the raw query still hold
+func mergeQuery(rawURL string, values url.Values) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	var kept []string
+	for _, pair := range strings.Split(u.RawQuery, "&") {
+		if pair == "" {
+			continue
+		}
+		key, _, _ := strings.Cut(pair, "=")
+		if name, err := url.QueryUnescape(key); err == nil {
+			if _, replaced := values[name]; replaced {
+				continue
+			}
+		}
+		kept = append(kept, pair)
+	}
+	if encoded := values.Encode(); encoded != "" {
+		kept = append(kept, encoded)
+	}
+	u.RawQuery = strings.Join(kept, "&")
+	return u.String(), nil
+}
+
+// encodeValues encodes a struct or a map with string keys into url.Values using tag for names
+func encodeValues(v any, tag string) (url.Values, error) {
+	values := make(url.Values)
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return values, nil
+		}
+		rv = rv.Elem()
+	}
+	switch rv.Kind() {
+	case reflect.Struct:
+		return values, encodeStruct(values, rv, tag)
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("clientx: cannot encode %s as values, keys must be strings", rv.Type())
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			if err := encodeField(values, iter.Key().String(), iter.Value(), fieldOptions{}); err != nil {
+				return nil, err
+			}
+		}
+		return values, nil
+	case reflect.Invalid:
+		return values, nil
+	}
+	return nil, fmt.Errorf("clientx: cannot encode %s as values", rv.Type())
+}
+
+func encodeStruct(values url.Values, rv reflect.Value, tag string) error {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		name, optList, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		fv := rv.Field(i)
+		// Embedded structs without a name are flattened
+		if field.Anonymous && name == "" {
+			for fv.Kind() == reflect.Pointer {
+				if fv.IsNil() {
+					break
+				}
+				fv = fv.Elem()
+			}
+			if fv.Kind() == reflect.Struct && !isTextType(fv.Type()) {
+				if err := encodeStruct(values, fv, tag); err != nil {
+					return err
+				}
+				continue
+			}
+			if !field.IsExported() {
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+		opts := fieldOptions{layout: field.Tag.Get("layout")}
+		for _, opt := range strings.Split(optList, ",") {
+			switch opt {
+			case "omitempty":
+				opts.omitEmpty = true
+			case "unix", "unixmilli":
+				opts.layout = opt
+			}
+		}
+		if err := encodeField(values, name, fv, opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fieldOptions are the encoding options of a struct field
+type fieldOptions struct {
+	omitEmpty bool   // Skip zero values
+	layout    string // Time layout, or "unix" / "unixmilli" for timestamps
+}
+
+// encodeField adds the text of v under name, once per element for slices and arrays
+func encodeField(values url.Values, name string, v reflect.Value, opts fieldOptions) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if opts.omitEmpty && v.IsZero() {
+		return nil
+	}
+	if (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && v.Type().Elem().Kind() != reflect.Uint8 {
+		for i := 0; i < v.Len(); i++ {
+			if err := encodeField(values, name, v.Index(i), fieldOptions{layout: opts.layout}); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	s, err := textValue(v, opts.layout)
+	if err != nil {
+		return fmt.Errorf("clientx: field %s: %w", name, err)
+	}
+	values.Add(name, s)
+	return nil
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	textMarshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+// isTextType reports whether values of t encode themselves as text
+// It looks at the type only, values of unexported embedded structs cannot be turned into interfaces
+func isTextType(t reflect.Type) bool {
+	return t == timeType || t.Implements(textMarshalerType) || reflect.PointerTo(t).Implements(textMarshalerType)
+}
+
+// textValue formats a scalar value, times with layout
+func textValue(v reflect.Value, layout string) (string, error) {
+	if v.CanInterface() {
+		if !v.Type().Implements(textMarshalerType) && reflect.PointerTo(v.Type()).Implements(textMarshalerType) {
+			// MarshalText has a pointer receiver, call it on a copy
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			v = p
+		}
+		switch x := v.Interface().(type) {
+		case time.Time:
+			switch layout {
+			case "":
+				return x.Format(time.RFC3339), nil
+			case "unix":
+				return strconv.FormatInt(x.Unix(), 10), nil
+			case "unixmilli":
+				return strconv.FormatInt(x.UnixMilli(), 10), nil
+			}
+			return x.Format(layout), nil
+		case time.Duration:
+			return x.String(), nil
+		case encoding.TextMarshaler:
+			b, err := x.MarshalText()
+			return string(b), err
+		}
+	}
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() == reflect.Uint8 {
+		return string(v.Bytes()), nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, v.Type().Bits()), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", v.Type())
+}
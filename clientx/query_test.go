@@ -0,0 +1,59 @@
+package clientx
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type paging struct {
+	Page int `query:"page"`
+}
+
+type listQuery struct {
+	paging
+	Name string `query:"name"`
+}
+
+type cursor struct {
+	ID int
+}
+
+func (c *cursor) MarshalText() ([]byte, error) {
+	return []byte("c" + strconv.Itoa(c.ID)), nil
+}
+
+func TestEncodeValues(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want string
+	}{
+		{"unexported embedded struct", listQuery{paging{2}, "x"}, "name=x&page=2"},
+		{"unexported embedded pointer", struct {
+			*paging
+			Name string `query:"name"`
+		}{&paging{3}, "y"}, "name=y&page=3"},
+		{"time layout", struct {
+			Day time.Time `query:"day" layout:"2006-01-02"`
+		}{time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)}, "day=2024-05-06"},
+		{"text marshaler", struct {
+			IP net.IP `query:"ip"`
+		}{net.IPv4(10, 0, 0, 1)}, "ip=10.0.0.1"},
+		{"pointer receiver text marshaler", struct {
+			After cursor `query:"after"`
+		}{cursor{5}}, "after=c5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := encodeValues(tt.in, "query")
+			if err != nil {
+				t.Fatalf("encodeValues: %v", err)
+			}
+			if got := values.Encode(); got != tt.want {
+				t.Errorf("encodeValues = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
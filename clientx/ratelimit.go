@@ -0,0 +1,117 @@
+package clientx
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter throttles outgoing requests, Wait blocks until req may be sent or ctx is done
+type RateLimiter interface {
+	Wait(ctx context.Context, req *http.Request) error
+}
+
+// WithRateLimit throttles all requests sharing this option to rps per second with bursts of burst
+// Create the option once and pass it to New (or reuse it across calls): every call of WithRateLimit
+// creates a new token bucket
+func WithRateLimit(rps float64, burst int) OptionFunc {
+	return WithRateLimiter(NewRateLimiter(rps, burst))
+}
+
+// WithHostRateLimit is like WithRateLimit with an independent bucket per request host
+func WithHostRateLimit(rps float64, burst int) OptionFunc {
+	return WithRateLimiter(NewHostRateLimiter(rps, burst))
+}
+
+// WithRateLimiter throttles every attempt, retries included, with l
+func WithRateLimiter(l RateLimiter) OptionFunc {
+	return func(o *Option) { o.RateLimiter = l }
+}
+
+// TokenBucket is a RateLimiter refilling rps tokens per second up to burst
+type TokenBucket struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  float64
+	tokens float64 // Negative while callers are waiting for reserved tokens
+	last   time.Time
+}
+
+// NewRateLimiter creates a token bucket limiter, starting full
+func NewRateLimiter(rps float64, burst int) *TokenBucket {
+	burst = max(burst, 1)
+	return &TokenBucket{rps: rps, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// Wait implements RateLimiter
+func (b *TokenBucket) Wait(ctx context.Context, _ *http.Request) error {
+	return b.wait(ctx)
+}
+
+func (b *TokenBucket) wait(ctx context.Context) error {
+	if b.rps <= 0 {
+		return nil
+	}
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rps)
+	b.last = now
+	// Reserve a token, waiting for the refill when the bucket is empty
+	b.tokens--
+	delay := time.Duration(-b.tokens / b.rps * float64(time.Second))
+	b.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Give the reservation back so later callers are not delayed by it
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// HostRateLimiter keeps a token bucket per request host
+type HostRateLimiter struct {
+	mu      sync.Mutex
+	rps     float64
+	burst   int
+	buckets map[string]*TokenBucket
+}
+
+// NewHostRateLimiter creates a limiter allowing rps per second with bursts of burst to every host
+func NewHostRateLimiter(rps float64, burst int) *HostRateLimiter {
+	return &HostRateLimiter{rps: rps, burst: burst, buckets: make(map[string]*TokenBucket)}
+}
+
+// Wait implements RateLimiter
+func (l *HostRateLimiter) Wait(ctx context.Context, req *http.Request) error {
+	l.mu.Lock()
+	b, ok := l.buckets[req.URL.Host]
+	if !ok {
+		b = NewRateLimiter(l.rps, l.burst)
+		l.buckets[req.URL.Host] = b
+	}
+	l.mu.Unlock()
+	return b.wait(ctx)
+}
+
+// rateLimitMiddleware waits for l before each attempt
+func rateLimitMiddleware(l RateLimiter) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			if err := l.Wait(req.Context(), req); err != nil {
+				return nil, err
+			}
+			return next(req)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package clientx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrRedirect is returned when a redirect is refused by WithRedirectPolicy, it is not retried
+var ErrRedirect = errors.New("clientx: redirect not allowed")
+
+// WithRedirectPolicy follows at most maxRedirects redirects, only to the host of the original
+// request unless allowCrossHost; a refused redirect fails with ErrRedirect
+// Passed to New it applies to every request, passed to a request it only applies to that request
+func WithRedirectPolicy(maxRedirects int, allowCrossHost bool) OptionFunc {
+	return func(o *Option) {
+		o.noRedirect = false
+		o.clientFuncs = append(o.clientFuncs, func(c *http.Client) {
+			c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+				if len(via) > maxRedirects {
+					return fmt.Errorf("%w: stopped after %d redirects", ErrRedirect, maxRedirects)
+				}
+				if !allowCrossHost && !strings.EqualFold(req.URL.Host, via[0].URL.Host) {
+					return fmt.Errorf("%w: %s redirects to another host", ErrRedirect, via[0].URL.Host)
+				}
+				return nil
+			}
+		})
+	}
+}
+
+// WithNoRedirect returns redirect responses instead of following them, as successful responses
+// whose Location header the caller can read
+// Passed to New it applies to every request, passed to a request it only applies to that request
+func WithNoRedirect() OptionFunc {
+	return func(o *Option) {
+		o.noRedirect = true
+		o.clientFuncs = append(o.clientFuncs, func(c *http.Client) {
+			c.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+		})
+	}
+}
+
+// isRedirect reports whether code is a status the HTTP client follows
+func isRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
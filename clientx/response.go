@@ -0,0 +1,115 @@
+package clientx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/filex"
+	"io"
+	"net/http"
+	"path/filepath"
+)
+
+// ErrResponseTooLarge is returned when a response body exceeds WithMaxResponseSize
+var ErrResponseTooLarge = errors.New("clientx: response body too large")
+
+// WithMaxResponseSize fails requests whose response body is larger than n bytes with
+// ErrResponseTooLarge, up front when Content-Length announces it and otherwise while reading
+func WithMaxResponseSize(n int64) OptionFunc {
+	return func(o *Option) { o.MaxResponseSize = n }
+}
+
+// limitedBody fails reads beyond max bytes
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+	max       int64
+}
+
+// limitBody limits body to max bytes, 0 means unlimited
+func limitBody(body io.ReadCloser, max int64) io.ReadCloser {
+	if max <= 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, remaining: max, max: max}
+}
+
+// Read implements io.Reader
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, b.max)
+	}
+	// Reading one byte past the limit tells a body of exactly max bytes from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n + int(b.remaining), fmt.Errorf("%w: more than %d bytes", ErrResponseTooLarge, b.max)
+	}
+	return n, err
+}
+
+// Response is a response whose body has been read and closed
+// The embedded http.Response keeps status and headers, its Body must not be used
+type Response struct {
+	*http.Response
+	body []byte
+}
+
+// Fetch sends a request like Request and reads the whole response into a Response, closing the
+// body whatever happens; combine it with WithMaxResponseSize to bound the memory used
+func Fetch(ctx context.Context, method, url string, body []byte, opts ...OptionFunc) (*Response, error) {
+	return Default().Fetch(ctx, method, url, body, opts...)
+}
+
+// Fetch sends a request through c, see the package-level Fetch
+func (c *Client) Fetch(ctx context.Context, method, url string, body []byte, opts ...OptionFunc) (*Response, error) {
+	return readResponse(c.Request(ctx, method, url, body, opts...))
+}
+
+// readResponse reads and closes the body of resp; a non-nil err is returned unchanged
+func readResponse(resp *http.Response, err error) (*Response, error) {
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Response: resp, body: body}, nil
+}
+
+// Bytes returns the response body
+func (r *Response) Bytes() []byte {
+	return r.body
+}
+
+// String returns the response body as a string
+func (r *Response) String() string {
+	return string(r.body)
+}
+
+// JSON decodes the response body into v
+func (r *Response) JSON(v any) error {
+	if err := json.Unmarshal(r.body, v); err != nil {
+		return fmt.Errorf("JSON deserialization failed: %w", err)
+	}
+	return nil
+}
+
+// IsSuccess reports whether the status is 2xx
+func (r *Response) IsSuccess() bool {
+	return r.StatusCode >= 200 && r.StatusCode < 300
+}
+
+// SaveTo writes the response body to path atomically, creating missing directories
+func (r *Response) SaveTo(path string) error {
+	if err := filex.EnsureDir(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return filex.AtomicWrite(path, r.body)
+}
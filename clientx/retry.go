@@ -0,0 +1,75 @@
+package clientx
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithRetryStatusCodes sets the response statuses that are retried, replacing the default of 429 and 5xx
+// Any other non-2xx status is returned immediately as *HTTPError
+func WithRetryStatusCodes(codes ...int) OptionFunc {
+	return func(o *Option) { o.RetryStatusCodes = codes }
+}
+
+// WithMaxRetryAfter bounds the Retry-After delay honored between retries, 0 means unbounded
+// A server asking to wait longer ends the retries with the last response as error
+func WithMaxRetryAfter(d time.Duration) OptionFunc {
+	return func(o *Option) { o.MaxRetryAfter = d }
+}
+
+// RetryFunc reports whether an attempt should be retried, resp is nil when err is a transport error
+type RetryFunc func(resp *http.Response, err error) bool
+
+// WithRetryIf lets fn decide which attempts are retried, for any method and status code
+// fn may read resp.Body, what it reads is still returned to the caller when the attempt is not retried
+// A 2xx response fn asks to retry is returned as *HTTPError once the retries are exhausted
+func WithRetryIf(fn RetryFunc) OptionFunc {
+	return func(o *Option) { o.RetryIf = fn }
+}
+
+// retryIf calls RetryIf, recording the body it reads so the response stays readable afterwards
+func (o *Option) retryIf(resp *http.Response, err error) bool {
+	if resp == nil || resp.Body == nil {
+		return o.RetryIf(resp, err)
+	}
+	var buf bytes.Buffer
+	body := resp.Body
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(body, &buf), body}
+	retry := o.RetryIf(resp, err)
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(&buf, body), body}
+	return retry
+}
+
+// RetryableStatus reports whether a response with status code may be retried
+func (o *Option) RetryableStatus(code int) bool {
+	if o.RetryStatusCodes != nil {
+		return slices.Contains(o.RetryStatusCodes, code)
+	}
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(v string) time.Duration {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0)
+	}
+	return 0
+}
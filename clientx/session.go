@@ -0,0 +1,171 @@
+package clientx
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/chihqiang/gox/filex"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"os"
+	"slices"
+	"sync"
+	"time"
+)
+
+// WithCookieJar sets the cookie jar used to store and send cookies
+// Passed to New it applies to every request, passed to a request it only applies to that request
+func WithCookieJar(jar http.CookieJar) OptionFunc {
+	return func(o *Option) {
+		o.clientFuncs = append(o.clientFuncs, func(c *http.Client) { c.Jar = jar })
+	}
+}
+
+// Session is a Client with its own cookie jar, so cookies set by a login are sent afterwards
+// Sessions created by NewFileSession persist the jar with Save
+type Session struct {
+	*Client
+	jar  *sessionJar
+	path string
+}
+
+// NewSession creates a session with an in-memory cookie jar, opts are the client defaults
+func NewSession(opts ...OptionFunc) *Session {
+	jar := newSessionJar()
+	return &Session{
+		Client: New(append(slices.Clip(opts), WithCookieJar(jar))...),
+		jar:    jar,
+	}
+}
+
+// NewFileSession creates a session whose cookies are loaded from path if it exists and written
+// back by Save. The file holds credentials and is created readable by the owner only
+func NewFileSession(path string, opts ...OptionFunc) (*Session, error) {
+	s := NewSession(opts...)
+	s.path = path
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := s.jar.load(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Jar returns the cookie jar of the session
+func (s *Session) Jar() http.CookieJar {
+	return s.jar
+}
+
+// Cookies returns the cookies the session sends to u
+func (s *Session) Cookies(u *url.URL) []*http.Cookie {
+	return s.jar.Cookies(u)
+}
+
+// Clear drops every cookie of the session
+func (s *Session) Clear() {
+	s.jar.clear()
+}
+
+// Save writes the persistent cookies to the session file, session cookies are not saved
+func (s *Session) Save() error {
+	if s.path == "" {
+		return errors.New("clientx: session has no file, use NewFileSession")
+	}
+	data, err := s.jar.marshal()
+	if err != nil {
+		return err
+	}
+	// AtomicWrite keeps the mode of an existing file
+	if f, err := os.OpenFile(s.path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600); err == nil {
+		_ = f.Close()
+	}
+	return filex.AtomicWrite(s.path, data)
+}
+
+// savedCookie is a cookie as received from URL, the form it is persisted in
+type savedCookie struct {
+	URL    string       `json:"url"`
+	Cookie *http.Cookie `json:"cookie"`
+}
+
+// sessionJar is a cookiejar.Jar that also records what it is given, since the standard jar
+// cannot list its cookies for persistence
+type sessionJar struct {
+	mu      sync.Mutex
+	jar     *cookiejar.Jar
+	entries map[string]savedCookie // Keyed by host, path and name, later cookies replace earlier ones
+}
+
+func newSessionJar() *sessionJar {
+	jar, _ := cookiejar.New(nil) // Never fails without options
+	return &sessionJar{jar: jar, entries: make(map[string]savedCookie)}
+}
+
+// SetCookies implements http.CookieJar
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar.SetCookies(u, cookies)
+	now := time.Now()
+	for _, c := range cookies {
+		cp := *c
+		if cp.MaxAge > 0 {
+			// MaxAge is relative to now, persist it as an absolute expiry
+			cp.Expires, cp.MaxAge = now.Add(time.Duration(cp.MaxAge)*time.Second), 0
+		}
+		key := u.Host + ";" + c.Domain + ";" + c.Path + ";" + c.Name
+		j.entries[key] = savedCookie{URL: u.Scheme + "://" + u.Host + u.Path, Cookie: &cp}
+	}
+}
+
+// Cookies implements http.CookieJar
+func (j *sessionJar) Cookies(u *url.URL) []*http.Cookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.jar.Cookies(u)
+}
+
+func (j *sessionJar) clear() {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.jar, _ = cookiejar.New(nil)
+	j.entries = make(map[string]savedCookie)
+}
+
+// marshal encodes the recorded cookies that are persistent and not yet expired
+func (j *sessionJar) marshal() ([]byte, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	saved := make([]savedCookie, 0, len(j.entries))
+	for _, e := range j.entries {
+		// Skip deletions, session cookies and expired cookies
+		if e.Cookie.MaxAge < 0 || !e.Cookie.Expires.After(now) {
+			continue
+		}
+		saved = append(saved, e)
+	}
+	return json.MarshalIndent(saved, "", "  ")
+}
+
+// load replays saved cookies into the jar
+func (j *sessionJar) load(data []byte) error {
+	var saved []savedCookie
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, e := range saved {
+		u, err := url.Parse(e.URL)
+		if err != nil || e.Cookie == nil || !e.Cookie.Expires.After(now) {
+			continue
+		}
+		j.SetCookies(u, []*http.Cookie{e.Cookie})
+	}
+	return nil
+}
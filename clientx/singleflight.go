@@ -0,0 +1,99 @@
+package clientx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/chihqiang/gox/syncx"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// singleflight collapses concurrent identical requests
+type singleflight struct {
+	key   func(*http.Request) string
+	group syncx.Group[string, *sharedResponse]
+}
+
+// sharedResponse is a response handed to every caller of a collapsed request
+type sharedResponse struct {
+	resp *http.Response
+	body []byte
+}
+
+// WithSingleflight collapses concurrent GET and HEAD requests with the same key into one upstream
+// request; every caller receives its own copy of the response, whose body is read into memory
+// up to WithMaxResponseSize.
+// keyFunc returning "" sends the request on its own, nil keys requests by method, URL and headers.
+// Passed to New it applies to every request of the client
+func WithSingleflight(keyFunc func(*http.Request) string) OptionFunc {
+	if keyFunc == nil {
+		keyFunc = requestKey
+	}
+	sf := &singleflight{key: keyFunc}
+	return func(o *Option) { o.singleflight = sf }
+}
+
+// requestKey identifies a request by method, URL and headers
+func requestKey(req *http.Request) string {
+	names := make([]string, 0, len(req.Header))
+	for name := range req.Header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var b strings.Builder
+	b.WriteString(req.Method + " " + req.URL.String())
+	for _, name := range names {
+		b.WriteString("\n" + name + ": " + strings.Join(req.Header[name], ", "))
+	}
+	return b.String()
+}
+
+// singleflightMiddleware shares the response of one request among concurrent identical ones,
+// buffering at most maxSize bytes of it (0 means unlimited)
+func singleflightMiddleware(sf *singleflight, maxSize int64) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet && req.Method != http.MethodHead ||
+				req.Body != nil && req.Body != http.NoBody ||
+				strings.Contains(req.Header.Get("Accept"), "text/event-stream") {
+				return next(req) // Event streams never end, their body cannot be shared
+			}
+			key := sf.key(req)
+			if key == "" {
+				return next(req)
+			}
+			for {
+				led := false
+				shared, err, _ := sf.group.Do(key, func() (*sharedResponse, error) {
+					led = true
+					resp, err := next(req)
+					if err != nil {
+						return nil, err
+					}
+					body := limitBody(resp.Body, maxSize)
+					defer body.Close()
+					data, err := io.ReadAll(body)
+					if err != nil {
+						return nil, err
+					}
+					return &sharedResponse{resp: resp, body: data}, nil
+				})
+				if err != nil {
+					if !led && req.Context().Err() == nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+						continue // The request we waited for was cancelled by its caller, not by ours
+					}
+					return nil, err
+				}
+				resp := *shared.resp
+				resp.Header = shared.resp.Header.Clone()
+				resp.Trailer = shared.resp.Trailer.Clone()
+				resp.Body = io.NopCloser(bytes.NewReader(shared.body))
+				resp.Request = req
+				return &resp, nil
+			}
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package clientx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotEventStream is returned when an SSE endpoint answers with another content type
+var ErrNotEventStream = errors.New("clientx: response is not an event stream")
+
+// Event is a Server-Sent Event
+type Event struct {
+	ID    string        // Last event ID when the event was dispatched
+	Event string        // Event type, "message" when the server sent none
+	Data  string        // Data lines joined with "\n"
+	Retry time.Duration // Reconnection delay requested along with the event, 0 if none
+}
+
+// EventHandler receives the events of an SSE stream, returning an error ends the stream
+type EventHandler func(Event) error
+
+// SSE streams the text/event-stream at url to handler until ctx is done, see Client.SSE
+func SSE(ctx context.Context, url string, handler EventHandler, opts ...OptionFunc) error {
+	return Default().SSE(ctx, url, handler, opts...)
+}
+
+// SSEChan streams the events at url to the returned channel, see Client.SSEChan
+func SSEChan(ctx context.Context, url string, opts ...OptionFunc) (<-chan Event, <-chan error) {
+	return Default().SSEChan(ctx, url, opts...)
+}
+
+// SSE connects to the text/event-stream at url and calls handler for every event until ctx is
+// done, handler fails or the server answers 204 No Content. Dropped connections are resumed
+// with the Last-Event-ID header after the delay requested by the server, or the backoff of the
+// options; connections failing more than the retry count in a row end the stream. The client
+// timeout does not apply to the stream unless WithTimeout is passed
+func (c *Client) SSE(ctx context.Context, url string, handler EventHandler, opts ...OptionFunc) error {
+	options := c.newOption(opts)
+	if options.err != nil {
+		return options.err
+	}
+	// Every connection is sent once, reconnecting is handled here
+	connOpts := append([]OptionFunc{WithTimeout(0)}, opts...)
+	connOpts = append(connOpts, WithRetries(0), WithHeaders(map[string]string{
+		"Accept":        "text/event-stream",
+		"Cache-Control": "no-cache",
+	}))
+
+	var (
+		lastID   string
+		retry    time.Duration
+		failures int
+	)
+	for {
+		headers := map[string]string{}
+		if lastID != "" {
+			headers["Last-Event-ID"] = lastID
+		}
+		resp, err := c.Get(ctx, url, append(connOpts, WithHeaders(headers))...)
+		if err == nil {
+			if resp.StatusCode == http.StatusNoContent {
+				_ = resp.Body.Close()
+				return nil // The server asks not to reconnect
+			}
+			mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+			if mediaType != "text/event-stream" {
+				_ = resp.Body.Close()
+				return fmt.Errorf("%w: %s", ErrNotEventStream, resp.Header.Get("Content-Type"))
+			}
+			failures = 0
+			err = readEvents(resp.Body, func(e Event) error {
+				lastID = e.ID
+				if e.Retry > 0 {
+					retry = e.Retry
+				}
+				if e.Data == "" && e.Event == "" {
+					return nil // Only updates the ID or the retry delay
+				}
+				return handler(e)
+			})
+			_ = resp.Body.Close()
+			var handlerErr *eventHandlerError
+			if errors.As(err, &handlerErr) {
+				return handlerErr.err
+			}
+		} else {
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) && httpErr.StatusCode != 0 && !options.RetryableStatus(httpErr.StatusCode) {
+				return err // e.g. 401 or 404, reconnecting cannot help
+			}
+			failures++
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if failures > options.Retries {
+			return err
+		}
+
+		delay := retry
+		if delay <= 0 || failures > 0 {
+			delay = max(delay, options.Backoff(max(failures-1, 0)))
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// SSEChan is like SSE but delivers events through the returned channel, which is closed when the
+// stream ends; the error channel then receives the error of SSE, nil once ctx is done
+func (c *Client) SSEChan(ctx context.Context, url string, opts ...OptionFunc) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errc := make(chan error, 1)
+	go func() {
+		defer close(events)
+		err := c.SSE(ctx, url, func(e Event) error {
+			select {
+			case events <- e:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}, opts...)
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			err = nil
+		}
+		errc <- err
+	}()
+	return events, errc
+}
+
+// eventHandlerError marks an error returned by the handler
+type eventHandlerError struct {
+	err error
+}
+
+func (e *eventHandlerError) Error() string { return e.err.Error() }
+
+// readEvents parses an event stream (WHATWG HTML, server-sent events), dispatching every
+// event to fn. The id and retry fields are dispatched as an event without type and data
+func readEvents(r io.Reader, fn func(Event) error) error {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64<<10), 1<<20)
+	sc.Split(scanEventLines)
+
+	var (
+		id      string
+		typ     string
+		data    strings.Builder
+		hasData bool
+		retry   time.Duration
+		pending bool // id or retry changed since the last dispatch
+	)
+	first := true
+	for sc.Scan() {
+		line := sc.Text()
+		if first {
+			line = strings.TrimPrefix(line, "\uFEFF") // Byte order mark
+			first = false
+		}
+		if line == "" {
+			// Blank line, dispatch the event
+			if hasData {
+				e := Event{ID: id, Event: typ, Data: strings.TrimSuffix(data.String(), "\n"), Retry: retry}
+				if e.Event == "" {
+					e.Event = "message"
+				}
+				if err := fn(e); err != nil {
+					return &eventHandlerError{err: err}
+				}
+			} else if pending {
+				if err := fn(Event{ID: id, Retry: retry}); err != nil {
+					return &eventHandlerError{err: err}
+				}
+			}
+			typ, hasData, pending, retry = "", false, false, 0
+			data.Reset()
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue // Comment, often sent as keepalive
+		}
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			typ = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				id, pending = value, true
+			}
+		case "retry":
+			if ms, err := strconv.ParseUint(value, 10, 63); err == nil {
+				retry, pending = time.Duration(ms)*time.Millisecond, true
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// scanEventLines splits lines ending in "\r\n", "\n" or "\r"
+func scanEventLines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 == len(data) && !atEOF {
+				return 0, nil, nil // Need more data to tell "\r" from "\r\n"
+			}
+			if i+1 < len(data) && data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil // An unterminated line is discarded by readEvents with the event
+	}
+	return 0, nil, nil
+}
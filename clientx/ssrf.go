@@ -0,0 +1,127 @@
+package clientx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/netx"
+	"net"
+	"net/netip"
+	"strings"
+	"syscall"
+)
+
+// ErrBlockedAddress is returned when WithAllowedHosts or WithBlockPrivateIPs reject a connection
+// Requests failing with it are not retried
+var ErrBlockedAddress = errors.New("clientx: address not allowed")
+
+// WithAllowedHosts only lets the client connect to hosts matching one of patterns, only honored by New
+// A pattern is a host name ("api.example.com"), a wildcard matching a domain and its subdomains
+// ("*.example.com" matches example.com and a.example.com, never evilexample.com),
+// an IP address or a CIDR range; addresses allowed by IP or CIDR are exempt from WithBlockPrivateIPs
+// Checks apply to every connection, redirects included; with a proxy they apply to the proxy
+func WithAllowedHosts(patterns ...string) OptionFunc {
+	names := make([]string, 0, len(patterns))
+	var prefixes []netip.Prefix
+	var err error
+	for _, p := range patterns {
+		p = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(p), "."))
+		if prefix, perr := netip.ParsePrefix(p); perr == nil {
+			prefixes = append(prefixes, prefix.Masked())
+		} else if addr, aerr := netip.ParseAddr(p); aerr == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()))
+		} else if strings.Contains(p, "/") || p == "" {
+			err = errors.Join(err, fmt.Errorf("clientx: invalid allowed host %q", p))
+		} else {
+			names = append(names, p)
+		}
+	}
+	return func(o *Option) {
+		if err != nil {
+			o.err = errors.Join(o.err, err)
+			return
+		}
+		withDialer(func(d *dialer) {
+			g := d.addressGuard()
+			g.restrict = true
+			g.names = append(g.names, names...)
+			g.prefixes = append(g.prefixes, prefixes...)
+		})(o)
+	}
+}
+
+// WithBlockPrivateIPs refuses connections to loopback, private (RFC 1918, RFC 4193), link-local,
+// carrier-grade NAT, unspecified and multicast addresses, only honored by New
+// The check runs on the resolved address right before connecting, so DNS names pointing to
+// internal addresses are rejected too
+func WithBlockPrivateIPs() OptionFunc {
+	return withDialer(func(d *dialer) { d.addressGuard().blockPrivate = true })
+}
+
+// guard validates the addresses a dialer connects to
+type guard struct {
+	restrict     bool // Only allowed names and prefixes may be dialed
+	names        []string
+	prefixes     []netip.Prefix
+	blockPrivate bool
+}
+
+// nameAllowedKey carries whether the dialed host name matched an allowed name
+type nameAllowedKey struct{}
+
+// addressGuard returns the guard of d, installing it on first use
+func (d *dialer) addressGuard() *guard {
+	if d.guard == nil {
+		d.guard = &guard{}
+		d.Dialer.ControlContext = d.guard.control
+	}
+	return d.guard
+}
+
+// allowName reports whether host matches an allowed name
+func (g *guard) allowName(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, name := range g.names {
+		if domain, ok := strings.CutPrefix(name, "*"); ok {
+			// Like NO_PROXY, the match must end on a label boundary
+			domain = strings.TrimPrefix(domain, ".")
+			if host == domain || strings.HasSuffix(host, "."+domain) {
+				return true
+			}
+		} else if host == name {
+			return true
+		}
+	}
+	return false
+}
+
+// control checks the resolved address right before the connection is made
+func (g *guard) control(ctx context.Context, network, address string, _ syscall.RawConn) error {
+	if network == "unix" || network == "unixgram" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	addr = addr.Unmap()
+	allowedIP := false
+	for _, p := range g.prefixes {
+		if p.Contains(addr) {
+			allowedIP = true
+			break
+		}
+	}
+	nameAllowed, _ := ctx.Value(nameAllowedKey{}).(bool)
+	if g.restrict && !nameAllowed && !allowedIP {
+		return fmt.Errorf("%w: %s is not an allowed host", ErrBlockedAddress, address)
+	}
+	if g.blockPrivate && !allowedIP && (netx.IsPrivateIP(addr.String()) || addr.IsMulticast()) {
+		return fmt.Errorf("%w: %s is a private address", ErrBlockedAddress, address)
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package clientx
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// WithRequestTimeout bounds a single call, retries and backoff included, with a context deadline
+// Unlike WithTimeout it never touches the client; the deadline stays active until the body is closed
+func WithRequestTimeout(d time.Duration) OptionFunc {
+	return func(o *Option) { o.RequestTimeout = d }
+}
+
+// cancelBody releases the request context when the response body is closed
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the request context
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,132 @@
+package clientx
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings are the durations of one attempt, measured with net/http/httptrace
+// DNS, Connect and TLS are zero when the attempt reused a pooled connection
+type Timings struct {
+	Attempt int           // Attempt number, 0 for the first one
+	DNS     time.Duration // Host name resolution
+	Connect time.Duration // TCP connection setup
+	TLS     time.Duration // TLS handshake
+	TTFB    time.Duration // From the start of the attempt to the first response byte
+	Total   time.Duration // From the start of the attempt until its body is read or closed, or it fails
+	Reused  bool          // Whether a pooled connection was reused
+	Err     error         // Error of the attempt, nil when a response was received
+}
+
+// TimingsFunc receives the timings of an attempt of req
+type TimingsFunc func(req *http.Request, t Timings)
+
+// WithTrace hands the timings of every attempt to fn, once its response body has been read to
+// the end or closed, or right away when the attempt fails; redirects are part of the attempt
+func WithTrace(fn TimingsFunc) OptionFunc {
+	return func(o *Option) { o.Trace = fn }
+}
+
+// traceMiddleware measures every attempt with an httptrace.ClientTrace
+func traceMiddleware(fn TimingsFunc) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			var (
+				mu                            sync.Mutex
+				t                             = Timings{Attempt: AttemptFromContext(req.Context())}
+				dnsStart, connStart, tlsStart time.Time
+			)
+			start := time.Now()
+			// Callbacks run on transport goroutines, dialing several addresses concurrently
+			ct := &httptrace.ClientTrace{
+				DNSStart: func(httptrace.DNSStartInfo) {
+					mu.Lock()
+					dnsStart = time.Now()
+					mu.Unlock()
+				},
+				DNSDone: func(httptrace.DNSDoneInfo) {
+					mu.Lock()
+					t.DNS = time.Since(dnsStart)
+					mu.Unlock()
+				},
+				ConnectStart: func(string, string) {
+					mu.Lock()
+					if connStart.IsZero() {
+						connStart = time.Now()
+					}
+					mu.Unlock()
+				},
+				ConnectDone: func(_, _ string, err error) {
+					mu.Lock()
+					if err == nil && t.Connect == 0 {
+						t.Connect = time.Since(connStart)
+					}
+					mu.Unlock()
+				},
+				TLSHandshakeStart: func() {
+					mu.Lock()
+					tlsStart = time.Now()
+					mu.Unlock()
+				},
+				TLSHandshakeDone: func(tls.ConnectionState, error) {
+					mu.Lock()
+					t.TLS = time.Since(tlsStart)
+					mu.Unlock()
+				},
+				GotConn: func(info httptrace.GotConnInfo) {
+					mu.Lock()
+					t.Reused = info.Reused
+					mu.Unlock()
+				},
+				GotFirstResponseByte: func() {
+					mu.Lock()
+					t.TTFB = time.Since(start)
+					mu.Unlock()
+				},
+			}
+			var once sync.Once
+			finish := func(err error) {
+				once.Do(func() {
+					mu.Lock()
+					t.Total, t.Err = time.Since(start), err
+					timings := t
+					mu.Unlock()
+					fn(req, timings)
+				})
+			}
+
+			resp, err := next(req.WithContext(httptrace.WithClientTrace(req.Context(), ct)))
+			if err != nil {
+				finish(err)
+				return nil, err
+			}
+			resp.Body = &timedBody{ReadCloser: resp.Body, finish: finish}
+			return resp, nil
+		}
+	}
+}
+
+// timedBody reports the end of an attempt once read to the end or closed
+type timedBody struct {
+	io.ReadCloser
+	finish func(error)
+}
+
+// Read implements io.Reader
+func (b *timedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.finish(nil)
+	}
+	return n, err
+}
+
+// Close implements io.Closer
+func (b *timedBody) Close() error {
+	b.finish(nil)
+	return b.ReadCloser.Close()
+}
@@ -0,0 +1,69 @@
+package clientx
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// WithTLSConfig replaces the TLS configuration of the transport, only honored by New
+// Later TLS options (WithRootCAs, WithClientCert...) modify a copy of cfg
+func WithTLSConfig(cfg *tls.Config) OptionFunc {
+	return func(o *Option) {
+		o.transportFuncs = append(o.transportFuncs, func(t *http.Transport) { t.TLSClientConfig = cfg.Clone() })
+	}
+}
+
+// WithRootCAs trusts the PEM encoded CA certificates instead of the system pool, only honored by New
+// Invalid PEM makes every request fail with the parse error
+func WithRootCAs(pem []byte) OptionFunc {
+	pool := x509.NewCertPool()
+	var err error
+	if !pool.AppendCertsFromPEM(pem) {
+		err = errors.New("clientx: no valid CA certificate in PEM")
+	}
+	return func(o *Option) {
+		if err != nil {
+			o.err = errors.Join(o.err, err)
+			return
+		}
+		o.transportFuncs = append(o.transportFuncs, func(t *http.Transport) { tlsConfig(t).RootCAs = pool })
+	}
+}
+
+// WithClientCert presents the PEM encoded certificate and key for mutual TLS, only honored by New
+// An invalid pair makes every request fail with the parse error
+func WithClientCert(certPEM, keyPEM []byte) OptionFunc {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		err = fmt.Errorf("clientx: invalid client certificate: %w", err)
+	}
+	return func(o *Option) {
+		if err != nil {
+			o.err = errors.Join(o.err, err)
+			return
+		}
+		o.transportFuncs = append(o.transportFuncs, func(t *http.Transport) {
+			cfg := tlsConfig(t)
+			cfg.Certificates = append(cfg.Certificates, cert)
+		})
+	}
+}
+
+// WithInsecureSkipVerify disables certificate verification, only honored by New
+// Use it for local testing only, it makes TLS connections open to interception
+func WithInsecureSkipVerify(skip bool) OptionFunc {
+	return func(o *Option) {
+		o.transportFuncs = append(o.transportFuncs, func(t *http.Transport) { tlsConfig(t).InsecureSkipVerify = skip })
+	}
+}
+
+// tlsConfig returns the TLS configuration of t, creating it if missing
+func tlsConfig(t *http.Transport) *tls.Config {
+	if t.TLSClientConfig == nil {
+		t.TLSClientConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	return t.TLSClientConfig
+}
@@ -7,5 +7,10 @@ import (
 
 // Trace request
 func Trace(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
-	return Request(ctx, http.MethodTrace, url, body, opts...)
+	return Default().Trace(ctx, url, body, opts...)
+}
+
+// Trace request
+func (c *Client) Trace(ctx context.Context, url string, body []byte, opts ...OptionFunc) (*http.Response, error) {
+	return c.Request(ctx, http.MethodTrace, url, body, opts...)
 }
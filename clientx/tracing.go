@@ -0,0 +1,86 @@
+package clientx
+
+import (
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// tracerName is the instrumentation scope of clientx spans
+const tracerName = "github.com/chihqiang/gox/clientx"
+
+// tracePropagator injects W3C trace context and baggage regardless of the global propagator
+var tracePropagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+
+// WithTracing starts an OpenTelemetry client span for every attempt and injects the W3C
+// traceparent header. Spans carry the HTTP semantic convention attributes and the resend count,
+// and end when the response body is closed. A nil provider uses otel.GetTracerProvider()
+func WithTracing(tp trace.TracerProvider) OptionFunc {
+	return func(o *Option) {
+		if tp == nil {
+			tp = otel.GetTracerProvider()
+		}
+		o.Tracer = tp.Tracer(tracerName)
+	}
+}
+
+// tracingMiddleware wraps every attempt in a client span
+func tracingMiddleware(tracer trace.Tracer) Middleware {
+	return func(next func(*http.Request) (*http.Response, error)) func(*http.Request) (*http.Response, error) {
+		return func(req *http.Request) (*http.Response, error) {
+			attrs := []attribute.KeyValue{
+				semconv.HTTPRequestMethodKey.String(req.Method),
+				semconv.URLFull(req.URL.Redacted()),
+				semconv.ServerAddress(req.URL.Hostname()),
+			}
+			if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+				attrs = append(attrs, semconv.ServerPort(port))
+			}
+			if attempt := AttemptFromContext(req.Context()); attempt > 0 {
+				attrs = append(attrs, semconv.HTTPRequestResendCount(attempt))
+			}
+			ctx, span := tracer.Start(req.Context(), req.Method,
+				trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+
+			req = req.Clone(ctx)
+			tracePropagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+			resp, err := next(req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				span.SetAttributes(semconv.ErrorTypeKey.String("transport"))
+				span.End()
+				return resp, err
+			}
+
+			span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+			if resp.StatusCode >= 400 {
+				span.SetStatus(codes.Error, "")
+				span.SetAttributes(semconv.ErrorTypeKey.String(strconv.Itoa(resp.StatusCode)))
+			}
+			resp.Body = &spanBody{ReadCloser: resp.Body, span: span}
+			return resp, nil
+		}
+	}
+}
+
+// spanBody ends the attempt span when the response body is closed
+type spanBody struct {
+	io.ReadCloser
+	span trace.Span
+	once sync.Once
+}
+
+// Close closes the body and ends the span
+func (b *spanBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.span.End() })
+	return err
+}
@@ -0,0 +1,230 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/chihqiang/gox/clientx"
+	"github.com/gorilla/websocket"
+	"sync"
+	"time"
+)
+
+// Conn is a WebSocket connection, safe for one concurrent reader and any number of writers
+// With WithReconnect a dropped connection is redialed transparently: the failing read is
+// resumed on the new connection and the failing write is sent again once
+type Conn struct {
+	url    string
+	opt    *Option
+	req    *clientx.Option
+	dialer *websocket.Dialer
+
+	mu     sync.Mutex
+	ws     *websocket.Conn
+	gen    int           // Incremented on every reconnect
+	stop   chan struct{} // Stops the pinger of ws
+	closed bool
+	done   chan struct{} // Closed by Close
+
+	writeMu     sync.Mutex
+	reconnectMu sync.Mutex
+}
+
+// attach makes ws the current connection and starts its keepalive
+func (c *Conn) attach(ws *websocket.Conn) error {
+	stop := make(chan struct{})
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		_ = ws.Close()
+		return ErrClosed
+	}
+	if c.stop != nil {
+		close(c.stop)
+	}
+	c.ws, c.stop = ws, stop
+	c.gen++
+	c.mu.Unlock()
+
+	interval := c.opt.PingInterval
+	if interval <= 0 {
+		return nil
+	}
+	ws.SetPongHandler(func(string) error {
+		return ws.SetReadDeadline(time.Now().Add(2 * interval))
+	})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				// Control frames may be written concurrently with WriteMessage
+				if err := ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(interval)); err != nil {
+					return
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// current returns the current connection and its generation
+func (c *Conn) current() (*websocket.Conn, int, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return nil, 0, ErrClosed
+	}
+	return c.ws, c.gen, nil
+}
+
+// reconnect replaces the connection of generation gen after it failed with cause
+// Concurrent callers share one redial
+func (c *Conn) reconnect(gen int, cause error) error {
+	c.reconnectMu.Lock()
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		c.reconnectMu.Unlock()
+		return ErrClosed
+	}
+	if c.gen != gen {
+		c.mu.Unlock()
+		c.reconnectMu.Unlock()
+		return nil // Another caller already reconnected
+	}
+	_ = c.ws.Close()
+	c.mu.Unlock()
+
+	if c.opt.OnDisconnect != nil {
+		c.opt.OnDisconnect(c, cause)
+	}
+	if !c.opt.Reconnect || websocket.IsCloseError(cause, websocket.CloseNormalClosure) {
+		c.reconnectMu.Unlock()
+		return cause
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		select {
+		case <-c.done:
+			cancel() // Close aborts the redial
+		case <-ctx.Done():
+		}
+	}()
+	ws, err := c.dial(ctx)
+	cancel()
+	if err != nil {
+		c.reconnectMu.Unlock()
+		return errors.Join(cause, err)
+	}
+	err = c.attach(ws)
+	c.reconnectMu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if c.opt.OnReconnect != nil {
+		if err := c.opt.OnReconnect(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadMessage reads the next data message, TextMessage or BinaryMessage
+// Pongs are only processed while a read is in progress, keep reading to keep the connection alive
+func (c *Conn) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		ws, gen, err := c.current()
+		if err != nil {
+			return 0, nil, err
+		}
+		if c.opt.PingInterval > 0 {
+			// Pongs extend the deadline while the read waits
+			_ = ws.SetReadDeadline(time.Now().Add(2 * c.opt.PingInterval))
+		}
+		messageType, data, err = ws.ReadMessage()
+		if err == nil {
+			return messageType, data, nil
+		}
+		if _, _, closedErr := c.current(); closedErr != nil {
+			return 0, nil, closedErr
+		}
+		if err := c.reconnect(gen, err); err != nil {
+			return 0, nil, err
+		}
+	}
+}
+
+// WriteMessage sends a data message, TextMessage or BinaryMessage
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	for retried := false; ; retried = true {
+		ws, gen, err := c.current()
+		if err != nil {
+			return err
+		}
+		err = ws.WriteMessage(messageType, data)
+		if err == nil || retried {
+			return err
+		}
+		if _, _, closedErr := c.current(); closedErr != nil {
+			return closedErr
+		}
+		// OnReconnect may write, which needs the lock
+		c.writeMu.Unlock()
+		err = c.reconnect(gen, err)
+		c.writeMu.Lock()
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// ReadJSON reads the next message and decodes it as JSON into v
+func (c *Conn) ReadJSON(v any) error {
+	_, data, err := c.ReadMessage()
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// WriteJSON sends v encoded as JSON in a text message
+func (c *Conn) WriteJSON(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return c.WriteMessage(TextMessage, data)
+}
+
+// Subprotocol returns the subprotocol chosen by the server
+func (c *Conn) Subprotocol() string {
+	ws, _, err := c.current()
+	if err != nil {
+		return ""
+	}
+	return ws.Subprotocol()
+}
+
+// Close sends a close frame and closes the connection, stopping reconnects
+func (c *Conn) Close() error {
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		return nil
+	}
+	c.closed = true
+	close(c.done)
+	close(c.stop)
+	ws := c.ws
+	c.mu.Unlock()
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+	return ws.Close()
+}
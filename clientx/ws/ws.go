@@ -0,0 +1,172 @@
+// Package ws dials WebSocket endpoints through a clientx.Client, sharing its transport settings
+// (TLS, dialer, proxy) and the request options of clientx (headers, proxy, retries), and keeps
+// connections alive with pings and automatic reconnects. The client must use an *http.Transport
+package ws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/clientx"
+	"github.com/gorilla/websocket"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Message types, as in RFC 6455
+const (
+	TextMessage   = websocket.TextMessage
+	BinaryMessage = websocket.BinaryMessage
+)
+
+// ErrClosed is returned by the methods of a Conn closed with Close
+var ErrClosed = errors.New("ws: connection closed")
+
+// ErrUnsupportedTransport is returned by Dial when the client does not use an *http.Transport,
+// e.g. one set with clientx.WithTransport, whose dialer, proxy and TLS settings cannot be shared
+var ErrUnsupportedTransport = errors.New("ws: client transport is not an *http.Transport")
+
+// Option WebSocket configuration structure
+type Option struct {
+	Client       *clientx.Client          // Client whose transport and defaults are used, default clientx.Default()
+	Request      []clientx.OptionFunc     // Request options of the handshake, e.g. clientx.WithHeaders
+	Subprotocols []string                 // Subprotocols offered to the server
+	PingInterval time.Duration            // Ping period, a connection without pong for twice as long is dropped, default 30s
+	Reconnect    bool                     // Redial dropped connections
+	OnReconnect  func(c *Conn) error      // Called after every reconnect, e.g. to subscribe again
+	OnDisconnect func(c *Conn, err error) // Called when the connection drops
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithClient dials through c instead of clientx.Default()
+func WithClient(c *clientx.Client) OptionFunc {
+	return func(o *Option) { o.Client = c }
+}
+
+// WithRequestOptions applies clientx options to the handshake: headers, proxy, retries and
+// backoff of the initial dial and of reconnects. Middlewares and signers are not run
+func WithRequestOptions(opts ...clientx.OptionFunc) OptionFunc {
+	return func(o *Option) { o.Request = append(o.Request, opts...) }
+}
+
+// WithSubprotocols offers subprotocols to the server, see Conn.Subprotocol
+func WithSubprotocols(protocols ...string) OptionFunc {
+	return func(o *Option) { o.Subprotocols = protocols }
+}
+
+// WithPingInterval pings the server every d, 0 disables keepalive
+func WithPingInterval(d time.Duration) OptionFunc {
+	return func(o *Option) { o.PingInterval = d }
+}
+
+// WithReconnect redials dropped connections with the retries and backoff of the request options;
+// fn, if not nil, runs on the new connection before reads and writes resume
+func WithReconnect(fn func(c *Conn) error) OptionFunc {
+	return func(o *Option) {
+		o.Reconnect = true
+		o.OnReconnect = fn
+	}
+}
+
+// OnDisconnect calls fn with the error that dropped the connection
+func OnDisconnect(fn func(c *Conn, err error)) OptionFunc {
+	return func(o *Option) { o.OnDisconnect = fn }
+}
+
+// Dial opens a WebSocket connection to url (ws://, wss://, http:// or https://)
+// Failed handshakes are retried like requests: network errors and retryable statuses, up to the
+// retry count of the request options; a rejected handshake returns *clientx.HTTPError
+func Dial(ctx context.Context, url string, opts ...OptionFunc) (*Conn, error) {
+	o := &Option{Client: clientx.Default(), PingInterval: 30 * time.Second}
+	for _, opt := range opts {
+		opt(o)
+	}
+	req, err := o.Client.ResolveOptions(o.Request...)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(url, "http://") || strings.HasPrefix(url, "https://") {
+		url = "ws" + strings.TrimPrefix(url, "http")
+	}
+	dialer, err := newDialer(o.Client, o.Subprotocols)
+	if err != nil {
+		return nil, err
+	}
+	c := &Conn{url: url, opt: o, req: req, dialer: dialer, done: make(chan struct{})}
+	ws, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.attach(ws) // c is not shared yet, it cannot be closed
+	return c, nil
+}
+
+// newDialer builds a websocket dialer from the transport of client
+// The proxy of the transport reads the per-request proxy from the context set up in dial
+func newDialer(client *clientx.Client, subprotocols []string) (*websocket.Dialer, error) {
+	hc := client.HTTPClient()
+	rt := hc.Transport
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	t, ok := rt.(*http.Transport)
+	if !ok {
+		// Falling back to a plain dialer would bypass the SSRF guard, proxy and TLS settings
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedTransport, rt)
+	}
+	d := &websocket.Dialer{
+		Proxy:            t.Proxy,
+		NetDialContext:   t.DialContext,
+		HandshakeTimeout: 45 * time.Second,
+		Jar:              hc.Jar,
+		Subprotocols:     subprotocols,
+	}
+	if hc.Timeout > 0 {
+		d.HandshakeTimeout = hc.Timeout
+	}
+	if t.TLSClientConfig != nil {
+		d.TLSClientConfig = t.TLSClientConfig.Clone()
+		d.TLSClientConfig.NextProtos = nil // The handshake is HTTP/1.1 only
+	}
+	return d, nil
+}
+
+// dial runs the handshake, retrying it like a request
+func (c *Conn) dial(ctx context.Context) (*websocket.Conn, error) {
+	ctx = c.req.Context(ctx)
+	header := make(http.Header)
+	for k, v := range c.req.Headers {
+		header.Set(k, v)
+	}
+	var lastErr error
+	for attempt := 0; attempt <= c.req.Retries; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(c.req.Backoff(attempt - 1)):
+			case <-ctx.Done():
+				return nil, errors.Join(lastErr, ctx.Err())
+			}
+		}
+		ws, resp, err := c.dialer.DialContext(ctx, c.url, header)
+		if err == nil {
+			return ws, nil
+		}
+		lastErr = err
+		if resp != nil {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			_ = resp.Body.Close()
+			lastErr = &clientx.HTTPError{StatusCode: resp.StatusCode, Method: http.MethodGet, URL: c.url, Body: body, Err: err}
+			if !c.req.RetryableStatus(resp.StatusCode) {
+				return nil, lastErr
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, lastErr
+		}
+	}
+	return nil, lastErr
+}
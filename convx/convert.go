@@ -0,0 +1,168 @@
+package convx
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/chihqiang/gox/timex"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// ToStringE converts v to string
+// Numbers and booleans are formatted with strconv, []byte is converted directly,
+// fmt.Stringer and error use their methods
+func ToStringE(v any) (string, error) {
+	v = indirect(v)
+	switch s := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return s, nil
+	case []byte:
+		return string(s), nil
+	case bool:
+		return strconv.FormatBool(s), nil
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(s), 'f', -1, 32), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(s), nil
+	case json.Number:
+		return s.String(), nil
+	case fmt.Stringer:
+		return s.String(), nil
+	case error:
+		return s.Error(), nil
+	default:
+		return "", fmt.Errorf("convx: cannot convert %T to string", v)
+	}
+}
+
+// ToString converts v to string, returning "" when conversion fails
+func ToString(v any) string {
+	s, _ := ToStringE(v)
+	return s
+}
+
+// ToTimeE converts v to time.Time
+// Strings are parsed with timex.Parse (layouts and unix timestamps), integers are unix seconds
+func ToTimeE(v any) (time.Time, error) {
+	v = indirect(v)
+	switch t := v.(type) {
+	case time.Time:
+		return t, nil
+	case string:
+		return timex.Parse(t)
+	case []byte:
+		return timex.Parse(string(t))
+	case json.Number:
+		return timex.Parse(t.String())
+	case nil:
+		return time.Time{}, nil
+	default:
+		sec, err := ToInt64E(v)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("convx: cannot convert %T to time.Time", v)
+		}
+		return time.Unix(sec, 0), nil
+	}
+}
+
+// ToTime converts v to time.Time, returning the zero time when conversion fails
+func ToTime(v any) time.Time {
+	t, _ := ToTimeE(v)
+	return t
+}
+
+// ToStringSliceE converts v to []string
+// Slices and arrays have every element converted with ToStringE, a single string becomes one element
+func ToStringSliceE(v any) ([]string, error) {
+	v = indirect(v)
+	switch s := v.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return s, nil
+	case string:
+		return []string{s}, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("convx: cannot convert %T to []string", v)
+	}
+	out := make([]string, rv.Len())
+	for i := range out {
+		str, err := ToStringE(rv.Index(i).Interface())
+		if err != nil {
+			return nil, fmt.Errorf("convx: element %d: %w", i, err)
+		}
+		out[i] = str
+	}
+	return out, nil
+}
+
+// ToStringSlice converts v to []string, returning nil when conversion fails
+func ToStringSlice(v any) []string {
+	s, _ := ToStringSliceE(v)
+	return s
+}
+
+// ToMapStringAnyE converts v to map[string]any
+// Any map type is accepted with keys converted by ToStringE; JSON object strings are decoded
+func ToMapStringAnyE(v any) (map[string]any, error) {
+	v = indirect(v)
+	switch m := v.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		return m, nil
+	case string:
+		var out map[string]any
+		if err := json.Unmarshal([]byte(m), &out); err != nil {
+			return nil, fmt.Errorf("convx: cannot convert string to map[string]any: %w", err)
+		}
+		return out, nil
+	case []byte:
+		return ToMapStringAnyE(string(m))
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map {
+		return nil, fmt.Errorf("convx: cannot convert %T to map[string]any", v)
+	}
+	out := make(map[string]any, rv.Len())
+	iter := rv.MapRange()
+	for iter.Next() {
+		key, err := ToStringE(iter.Key().Interface())
+		if err != nil {
+			return nil, fmt.Errorf("convx: map key: %w", err)
+		}
+		out[key] = iter.Value().Interface()
+	}
+	return out, nil
+}
+
+// ToMapStringAny converts v to map[string]any, returning nil when conversion fails
+func ToMapStringAny(v any) map[string]any {
+	m, _ := ToMapStringAnyE(v)
+	return m
+}
+
+// indirect dereferences pointers until a non-pointer value (or nil) is reached
+func indirect(v any) any {
+	if v == nil {
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer {
+		return v
+	}
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil
+		}
+		rv = rv.Elem()
+	}
+	return rv.Interface()
+}
@@ -0,0 +1,205 @@
+package convx
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ToInt64E converts v to int64
+// Accepts all integer and float types (floats must be integral), bool, numeric strings,
+// []byte, json.Number, time.Duration and pointers to those
+func ToInt64E(v any) (int64, error) {
+	v = indirect(v)
+	switch n := v.(type) {
+	case nil:
+		return 0, nil
+	case int:
+		return int64(n), nil
+	case int8:
+		return int64(n), nil
+	case int16:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case uint:
+		return uintToInt64(uint64(n))
+	case uint8:
+		return int64(n), nil
+	case uint16:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	case uint64:
+		return uintToInt64(n)
+	case float32:
+		return floatToInt64(float64(n))
+	case float64:
+		return floatToInt64(n)
+	case bool:
+		if n {
+			return 1, nil
+		}
+		return 0, nil
+	case time.Duration:
+		return int64(n), nil
+	case json.Number:
+		return parseInt(string(n))
+	case string:
+		return parseInt(n)
+	case []byte:
+		return parseInt(string(n))
+	default:
+		return 0, fmt.Errorf("convx: cannot convert %T to int64", v)
+	}
+}
+
+// ToInt64 converts v to int64, returning 0 when conversion fails
+func ToInt64(v any) int64 {
+	n, _ := ToInt64E(v)
+	return n
+}
+
+// ToIntE converts v to int, see ToInt64E
+func ToIntE(v any) (int, error) {
+	n, err := ToInt64E(v)
+	if err != nil {
+		return 0, err
+	}
+	if n > math.MaxInt || n < math.MinInt {
+		return 0, fmt.Errorf("convx: %d overflows int", n)
+	}
+	return int(n), nil
+}
+
+// ToInt converts v to int, returning 0 when conversion fails
+func ToInt(v any) int {
+	n, _ := ToIntE(v)
+	return n
+}
+
+// ToFloatE converts v to float64
+// Accepts all numeric types, bool, numeric strings, []byte, json.Number and pointers to those
+func ToFloatE(v any) (float64, error) {
+	v = indirect(v)
+	switch n := v.(type) {
+	case nil:
+		return 0, nil
+	case float64:
+		return n, nil
+	case float32:
+		return float64(n), nil
+	case json.Number:
+		return n.Float64()
+	case string:
+		return parseFloat(n)
+	case []byte:
+		return parseFloat(string(n))
+	case bool:
+		if n {
+			return 1, nil
+		}
+		return 0, nil
+	default:
+		i, err := ToInt64E(v)
+		if err != nil {
+			return 0, fmt.Errorf("convx: cannot convert %T to float64", v)
+		}
+		return float64(i), nil
+	}
+}
+
+// ToFloat converts v to float64, returning 0 when conversion fails
+func ToFloat(v any) float64 {
+	f, _ := ToFloatE(v)
+	return f
+}
+
+// ToBoolE converts v to bool
+// Numbers are true when non-zero; strings accept strconv.ParseBool values plus yes/no, y/n, on/off
+func ToBoolE(v any) (bool, error) {
+	v = indirect(v)
+	switch b := v.(type) {
+	case nil:
+		return false, nil
+	case bool:
+		return b, nil
+	case string:
+		return parseBool(b)
+	case []byte:
+		return parseBool(string(b))
+	case json.Number:
+		f, err := b.Float64()
+		return f != 0, err
+	default:
+		f, err := ToFloatE(v)
+		if err != nil {
+			return false, fmt.Errorf("convx: cannot convert %T to bool", v)
+		}
+		return f != 0, nil
+	}
+}
+
+// ToBool converts v to bool, returning false when conversion fails
+func ToBool(v any) bool {
+	b, _ := ToBoolE(v)
+	return b
+}
+
+func parseInt(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, nil
+	}
+	// Allow integral decimal floats such as "3.0" or "1e3", not hex floats or underscores
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || strings.ContainsAny(s, "xX_") {
+		return 0, fmt.Errorf("convx: cannot convert %q to int64", s)
+	}
+	return floatToInt64(f)
+}
+
+func parseFloat(s string) (float64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("convx: cannot convert %q to float64", s)
+	}
+	return f, nil
+}
+
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "1", "t", "true", "y", "yes", "on":
+		return true, nil
+	case "", "0", "f", "false", "n", "no", "off":
+		return false, nil
+	default:
+		return false, fmt.Errorf("convx: cannot convert %q to bool", s)
+	}
+}
+
+func floatToInt64(f float64) (int64, error) {
+	if f != math.Trunc(f) || math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, fmt.Errorf("convx: %v is not an integral int64 value", f)
+	}
+	return int64(f), nil
+}
+
+func uintToInt64(n uint64) (int64, error) {
+	if n > math.MaxInt64 {
+		return 0, fmt.Errorf("convx: %d overflows int64", n)
+	}
+	return int64(n), nil
+}
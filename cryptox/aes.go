@@ -0,0 +1,109 @@
+package cryptox
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// KeySize is the AES-256 key length in bytes
+	KeySize = 32
+	// SaltSize is the recommended salt length in bytes for key derivation
+	SaltSize = 16
+	// PBKDF2Iterations is the iteration count used by DeriveKey (OWASP 2023 recommendation for SHA-256)
+	PBKDF2Iterations = 600_000
+
+	// aesGCMVersion1 prefixes ciphertexts produced by Encrypt: version || nonce || sealed data
+	aesGCMVersion1 byte = 1
+)
+
+var (
+	// ErrInvalidKey is returned when the key is not KeySize bytes long
+	ErrInvalidKey = errors.New("cryptox: key must be 32 bytes for AES-256")
+	// ErrInvalidCiphertext is returned when the input is malformed, truncated or of an unknown version
+	ErrInvalidCiphertext = errors.New("cryptox: invalid ciphertext")
+)
+
+// Encrypt encrypts plaintext with AES-256-GCM under a random nonce
+// The result is base64 encoded and carries a version byte so the format can evolve
+func Encrypt(plaintext, key []byte) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+	out := make([]byte, 1+gcm.NonceSize(), 1+gcm.NonceSize()+len(plaintext)+gcm.Overhead())
+	out[0] = aesGCMVersion1
+	if _, err := rand.Read(out[1:]); err != nil {
+		return "", fmt.Errorf("cryptox: generate nonce failed: %w", err)
+	}
+	out = gcm.Seal(out, out[1:], plaintext, nil)
+	return base64.StdEncoding.EncodeToString(out), nil
+}
+
+// Decrypt reverses Encrypt, authenticating the data before returning the plaintext
+func Decrypt(ciphertext string, key []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	if len(data) < 1+gcm.NonceSize()+gcm.Overhead() || data[0] != aesGCMVersion1 {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, sealed := data[1:1+gcm.NonceSize()], data[1+gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, fmt.Errorf("cryptox: decrypt failed: %w", err)
+	}
+	return plaintext, nil
+}
+
+// NewKey returns a random AES-256 key
+func NewKey() ([]byte, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// NewSalt returns a random salt for DeriveKey/DeriveKeyScrypt
+func NewSalt() ([]byte, error) {
+	salt := make([]byte, SaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+// DeriveKey derives an AES-256 key from a passphrase using PBKDF2-HMAC-SHA256
+// The salt must be stored alongside the ciphertext to derive the same key again
+func DeriveKey(passphrase, salt []byte) []byte {
+	return pbkdf2.Key(passphrase, salt, PBKDF2Iterations, KeySize, sha256.New)
+}
+
+// DeriveKeyScrypt derives an AES-256 key from a passphrase using scrypt (N=32768, r=8, p=1)
+func DeriveKeyScrypt(passphrase, salt []byte) ([]byte, error) {
+	return scrypt.Key(passphrase, salt, 1<<15, 8, 1, KeySize)
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
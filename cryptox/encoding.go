@@ -0,0 +1,21 @@
+package cryptox
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Hex encodes b as a lowercase hex string
+func Hex(b []byte) string {
+	return hex.EncodeToString(b)
+}
+
+// Base64 encodes b using standard base64 with padding
+func Base64(b []byte) string {
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// Base64URL encodes b using URL-safe base64 without padding
+func Base64URL(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
@@ -0,0 +1,174 @@
+package cryptox
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"strings"
+)
+
+// Algorithm identifies a supported hash algorithm, the zero value is not a valid algorithm
+type Algorithm int
+
+const (
+	AlgMD5    Algorithm = iota + 1 // MD5, only for checksums and legacy interop
+	AlgSHA1                        // SHA-1, only for checksums and legacy interop
+	AlgSHA256                      // SHA-256
+	AlgSHA512                      // SHA-512
+)
+
+// String returns the lowercase algorithm name, e.g. "sha256"
+func (a Algorithm) String() string {
+	switch a {
+	case AlgMD5:
+		return "md5"
+	case AlgSHA1:
+		return "sha1"
+	case AlgSHA256:
+		return "sha256"
+	case AlgSHA512:
+		return "sha512"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", int(a))
+	}
+}
+
+// New returns a new hash.Hash for the algorithm
+// It panics on unknown values, which are a programming error; validate names with ParseAlgorithm
+func (a Algorithm) New() hash.Hash {
+	switch a {
+	case AlgMD5:
+		return md5.New()
+	case AlgSHA1:
+		return sha1.New()
+	case AlgSHA256:
+		return sha256.New()
+	case AlgSHA512:
+		return sha512.New()
+	default:
+		panic("cryptox: unknown hash algorithm " + a.String())
+	}
+}
+
+// ParseAlgorithm parses an algorithm name such as "sha256" or "SHA-256"
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch strings.ReplaceAll(strings.ToLower(name), "-", "") {
+	case "md5":
+		return AlgMD5, nil
+	case "sha1":
+		return AlgSHA1, nil
+	case "sha256":
+		return AlgSHA256, nil
+	case "sha512":
+		return AlgSHA512, nil
+	default:
+		return 0, fmt.Errorf("unsupported hash algorithm: %s", name)
+	}
+}
+
+// Sum returns the raw digest of data
+func Sum(alg Algorithm, data []byte) []byte {
+	h := alg.New()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// SumReader streams r through the hash and returns the raw digest
+func SumReader(alg Algorithm, r io.Reader) ([]byte, error) {
+	h := alg.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return nil, fmt.Errorf("hash %s failed: %w", alg, err)
+	}
+	return h.Sum(nil), nil
+}
+
+// SumFile streams the file at path through the hash and returns the raw digest
+func SumFile(alg Algorithm, path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return SumReader(alg, f)
+}
+
+// MD5 returns the hex-encoded MD5 digest of s
+func MD5(s string) string {
+	return Hex(Sum(AlgMD5, []byte(s)))
+}
+
+// SHA1 returns the hex-encoded SHA-1 digest of s
+func SHA1(s string) string {
+	return Hex(Sum(AlgSHA1, []byte(s)))
+}
+
+// SHA256 returns the hex-encoded SHA-256 digest of s
+func SHA256(s string) string {
+	return Hex(Sum(AlgSHA256, []byte(s)))
+}
+
+// SHA512 returns the hex-encoded SHA-512 digest of s
+func SHA512(s string) string {
+	return Hex(Sum(AlgSHA512, []byte(s)))
+}
+
+// MD5File returns the hex-encoded MD5 digest of the file at path
+func MD5File(path string) (string, error) {
+	return sumFileHex(AlgMD5, path)
+}
+
+// SHA1File returns the hex-encoded SHA-1 digest of the file at path
+func SHA1File(path string) (string, error) {
+	return sumFileHex(AlgSHA1, path)
+}
+
+// SHA256File returns the hex-encoded SHA-256 digest of the file at path
+func SHA256File(path string) (string, error) {
+	return sumFileHex(AlgSHA256, path)
+}
+
+// SHA512File returns the hex-encoded SHA-512 digest of the file at path
+func SHA512File(path string) (string, error) {
+	return sumFileHex(AlgSHA512, path)
+}
+
+// HMAC returns the raw HMAC of data keyed with key
+func HMAC(alg Algorithm, key, data []byte) []byte {
+	m := hmac.New(alg.New, key)
+	m.Write(data)
+	return m.Sum(nil)
+}
+
+// HMACSHA256 returns the raw HMAC-SHA256 of data keyed with key
+func HMACSHA256(key, data []byte) []byte {
+	return HMAC(AlgSHA256, key, data)
+}
+
+// HMACSHA256Hex returns the hex-encoded HMAC-SHA256 of data keyed with key
+func HMACSHA256Hex(key, data string) string {
+	return Hex(HMACSHA256([]byte(key), []byte(data)))
+}
+
+// HMACSHA256Base64 returns the standard base64-encoded HMAC-SHA256 of data keyed with key
+func HMACSHA256Base64(key, data string) string {
+	return Base64(HMACSHA256([]byte(key), []byte(data)))
+}
+
+// VerifyHMAC reports whether mac is the valid HMAC of data, compared in constant time
+func VerifyHMAC(alg Algorithm, key, data, mac []byte) bool {
+	return hmac.Equal(HMAC(alg, key, data), mac)
+}
+
+func sumFileHex(alg Algorithm, path string) (string, error) {
+	sum, err := SumFile(alg, path)
+	if err != nil {
+		return "", err
+	}
+	return Hex(sum), nil
+}
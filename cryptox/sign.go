@@ -0,0 +1,169 @@
+package cryptox
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrInvalidPEM is returned when no PEM block could be decoded from the input
+var ErrInvalidPEM = errors.New("cryptox: no PEM block found")
+
+// ParsePrivateKeyPEM parses a PEM encoded private key in PKCS#8, PKCS#1 (RSA) or SEC 1 (EC) form
+// The returned key is *rsa.PrivateKey, *ecdsa.PrivateKey or ed25519.PrivateKey
+func ParsePrivateKeyPEM(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("cryptox: unsupported private key type %T", key)
+		}
+		return signer, nil
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("cryptox: unsupported private key block %q", block.Type)
+}
+
+// ParsePublicKeyPEM parses a PEM encoded public key in PKIX or PKCS#1 (RSA) form,
+// or extracts the public key from a certificate
+func ParsePublicKeyPEM(data []byte) (crypto.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, ErrInvalidPEM
+	}
+	switch block.Type {
+	case "CERTIFICATE":
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("cryptox: parse certificate failed: %w", err)
+		}
+		return cert.PublicKey, nil
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	}
+}
+
+// LoadPrivateKey reads and parses a PEM private key file
+func LoadPrivateKey(path string) (crypto.Signer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePrivateKeyPEM(data)
+}
+
+// LoadPublicKey reads and parses a PEM public key or certificate file
+func LoadPublicKey(path string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePublicKeyPEM(data)
+}
+
+// MarshalPrivateKeyPEM encodes a private key as a PKCS#8 "PRIVATE KEY" PEM block
+func MarshalPrivateKeyPEM(key crypto.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM encodes a public key as a PKIX "PUBLIC KEY" PEM block
+func MarshalPublicKeyPEM(key crypto.PublicKey) ([]byte, error) {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// SignPKCS1v15 signs the digest of data with RSASSA-PKCS1-v1_5
+func SignPKCS1v15(key *rsa.PrivateKey, alg Algorithm, data []byte) ([]byte, error) {
+	h, err := cryptoHash(alg)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.SignPKCS1v15(rand.Reader, key, h, Sum(alg, data))
+}
+
+// VerifyPKCS1v15 verifies an RSASSA-PKCS1-v1_5 signature over data, nil means valid
+func VerifyPKCS1v15(key *rsa.PublicKey, alg Algorithm, data, sig []byte) error {
+	h, err := cryptoHash(alg)
+	if err != nil {
+		return err
+	}
+	return rsa.VerifyPKCS1v15(key, h, Sum(alg, data), sig)
+}
+
+// SignPSS signs the digest of data with RSASSA-PSS using a salt as long as the hash
+func SignPSS(key *rsa.PrivateKey, alg Algorithm, data []byte) ([]byte, error) {
+	h, err := cryptoHash(alg)
+	if err != nil {
+		return nil, err
+	}
+	return rsa.SignPSS(rand.Reader, key, h, Sum(alg, data), &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+}
+
+// VerifyPSS verifies an RSASSA-PSS signature over data, nil means valid
+// Any salt length is accepted so signatures from other implementations verify too
+func VerifyPSS(key *rsa.PublicKey, alg Algorithm, data, sig []byte) error {
+	h, err := cryptoHash(alg)
+	if err != nil {
+		return err
+	}
+	return rsa.VerifyPSS(key, h, Sum(alg, data), sig, &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto})
+}
+
+// SignECDSA signs the digest of data with ECDSA, returning an ASN.1 DER encoded signature
+func SignECDSA(key *ecdsa.PrivateKey, alg Algorithm, data []byte) ([]byte, error) {
+	return ecdsa.SignASN1(rand.Reader, key, Sum(alg, data))
+}
+
+// VerifyECDSA verifies an ASN.1 DER encoded ECDSA signature over data
+func VerifyECDSA(key *ecdsa.PublicKey, alg Algorithm, data, sig []byte) bool {
+	return ecdsa.VerifyASN1(key, Sum(alg, data), sig)
+}
+
+// EncryptOAEP encrypts plaintext with RSA-OAEP using SHA-256, label may be nil
+func EncryptOAEP(key *rsa.PublicKey, plaintext, label []byte) ([]byte, error) {
+	return rsa.EncryptOAEP(AlgSHA256.New(), rand.Reader, key, plaintext, label)
+}
+
+// DecryptOAEP decrypts an RSA-OAEP ciphertext produced with SHA-256
+func DecryptOAEP(key *rsa.PrivateKey, ciphertext, label []byte) ([]byte, error) {
+	return rsa.DecryptOAEP(AlgSHA256.New(), rand.Reader, key, ciphertext, label)
+}
+
+// cryptoHash maps an Algorithm to its crypto.Hash identifier
+func cryptoHash(alg Algorithm) (crypto.Hash, error) {
+	switch alg {
+	case AlgMD5:
+		return crypto.MD5, nil
+	case AlgSHA1:
+		return crypto.SHA1, nil
+	case AlgSHA256:
+		return crypto.SHA256, nil
+	case AlgSHA512:
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("cryptox: unsupported hash algorithm %s", alg)
+	}
+}
@@ -0,0 +1,321 @@
+package csvx
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// utf8BOM is written before the header when Option.BOM is set, so Excel detects UTF-8
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// Option CSV configuration structure
+type Option struct {
+	Comma      rune   // Field delimiter, default ','
+	BOM        bool   // Write a UTF-8 byte order mark before the header
+	TimeLayout string // Layout used to format time.Time fields, default time.RFC3339
+	NoHeader   bool   // Do not write or expect a header row, columns follow field order
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithDelimiter sets the field delimiter, e.g. ';' or '\t'
+func WithDelimiter(r rune) OptionFunc {
+	return func(o *Option) { o.Comma = r }
+}
+
+// WithBOM writes a UTF-8 byte order mark, readers always strip it
+func WithBOM() OptionFunc {
+	return func(o *Option) { o.BOM = true }
+}
+
+// WithTimeLayout sets the layout used to format time.Time fields
+func WithTimeLayout(layout string) OptionFunc {
+	return func(o *Option) { o.TimeLayout = layout }
+}
+
+// WithoutHeader disables the header row, columns are mapped by field order
+func WithoutHeader() OptionFunc {
+	return func(o *Option) { o.NoHeader = true }
+}
+
+func newOption(opts []OptionFunc) *Option {
+	o := &Option{Comma: ',', TimeLayout: time.RFC3339}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// encoder writes struct values of one type as CSV records
+type encoder struct {
+	w       *csv.Writer
+	out     io.Writer
+	opt     *Option
+	fields  []field
+	record  []string
+	started bool
+}
+
+func newEncoder(w io.Writer, t reflect.Type, opts []OptionFunc) *encoder {
+	o := newOption(opts)
+	cw := csv.NewWriter(w)
+	cw.Comma = o.Comma
+	fields := fieldsOf(t)
+	return &encoder{w: cw, out: w, opt: o, fields: fields, record: make([]string, len(fields))}
+}
+
+// start writes the BOM and header once
+func (e *encoder) start() error {
+	if e.started {
+		return nil
+	}
+	e.started = true
+	if e.opt.BOM {
+		if _, err := e.out.Write(utf8BOM); err != nil {
+			return err
+		}
+	}
+	if e.opt.NoHeader {
+		return nil
+	}
+	return e.w.Write(header(e.fields))
+}
+
+// encode writes v, a struct or pointer to struct; a nil pointer becomes an empty record
+func (e *encoder) encode(v reflect.Value) error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			clear(e.record)
+			return e.w.Write(e.record)
+		}
+		v = v.Elem()
+	}
+	for i, f := range e.fields {
+		s, err := formatValue(v.FieldByIndex(f.index), e.opt)
+		if err != nil {
+			return fmt.Errorf("csvx: column %q: %w", f.name, err)
+		}
+		e.record[i] = s
+	}
+	return e.w.Write(e.record)
+}
+
+func (e *encoder) flush() error {
+	if err := e.start(); err != nil {
+		return err
+	}
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// decoder reads CSV records into struct values of one type
+type decoder struct {
+	r       *csv.Reader
+	columns []*field // Struct field of each column, nil for unknown columns
+	line    int
+}
+
+// newDecoder strips a BOM and consumes the header row unless NoHeader is set
+func newDecoder(r io.Reader, t reflect.Type, opts []OptionFunc) (*decoder, error) {
+	o := newOption(opts)
+	br := bufio.NewReader(r)
+	if b, err := br.Peek(len(utf8BOM)); err == nil && bytes.Equal(b, utf8BOM) {
+		_, _ = br.Discard(len(utf8BOM))
+	}
+	cr := csv.NewReader(br)
+	cr.Comma = o.Comma
+	cr.FieldsPerRecord = -1
+	cr.ReuseRecord = true
+
+	fields := fieldsOf(t)
+	d := &decoder{r: cr}
+	if o.NoHeader {
+		for i := range fields {
+			d.columns = append(d.columns, &fields[i])
+		}
+		return d, nil
+	}
+	names, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	d.line = 1
+	for _, name := range names {
+		var match *field
+		for i := range fields {
+			if strings.EqualFold(fields[i].name, strings.TrimSpace(name)) {
+				match = &fields[i]
+				break
+			}
+		}
+		d.columns = append(d.columns, match)
+	}
+	return d, nil
+}
+
+// decode reads the next record into v, a settable struct value
+func (d *decoder) decode(v reflect.Value) error {
+	record, err := d.r.Read()
+	if err != nil {
+		return err
+	}
+	d.line++
+	for i, s := range record {
+		if i >= len(d.columns) || d.columns[i] == nil {
+			continue
+		}
+		f := d.columns[i]
+		if err := parseValue(v.FieldByIndex(f.index), s); err != nil {
+			return fmt.Errorf("csvx: line %d, column %q: %w", d.line, f.name, err)
+		}
+	}
+	return nil
+}
+
+// Writer encodes values of type T as CSV rows, T may be a struct or a pointer to struct
+type Writer[T any] struct {
+	enc *encoder
+}
+
+// NewWriter creates a Writer, the header is written with the first row (or by Flush)
+func NewWriter[T any](w io.Writer, opts ...OptionFunc) (*Writer[T], error) {
+	t, err := structType(reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
+	}
+	return &Writer[T]{enc: newEncoder(w, t, opts)}, nil
+}
+
+// Write encodes one row, rows are buffered until Flush
+func (w *Writer[T]) Write(row T) error {
+	return w.enc.encode(reflect.ValueOf(&row).Elem())
+}
+
+// Flush writes buffered rows to the underlying writer
+func (w *Writer[T]) Flush() error {
+	return w.enc.flush()
+}
+
+// Reader decodes CSV rows into values of type T, T may be a struct or a pointer to struct
+type Reader[T any] struct {
+	dec *decoder
+	typ reflect.Type
+	ptr bool
+}
+
+// NewReader creates a Reader and consumes the header row unless WithoutHeader is set
+// Header names are matched to fields case-insensitively, unknown columns are ignored
+func NewReader[T any](r io.Reader, opts ...OptionFunc) (*Reader[T], error) {
+	rt := reflect.TypeFor[T]()
+	t, err := structType(rt)
+	if err != nil {
+		return nil, err
+	}
+	dec, err := newDecoder(r, t, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader[T]{dec: dec, typ: t, ptr: rt.Kind() == reflect.Pointer}, nil
+}
+
+// Read decodes the next row, returning io.EOF when there are no more rows
+func (r *Reader[T]) Read() (T, error) {
+	var row T
+	v := reflect.New(r.typ)
+	if err := r.dec.decode(v.Elem()); err != nil {
+		return row, err
+	}
+	if r.ptr {
+		return v.Interface().(T), nil
+	}
+	return v.Elem().Interface().(T), nil
+}
+
+// All iterates over the remaining rows, stopping after the first error
+func (r *Reader[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			row, err := r.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			if !yield(row, err) || err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Marshal encodes rows, a slice of structs or struct pointers, as CSV with a header row
+func Marshal(rows any, opts ...OptionFunc) ([]byte, error) {
+	var buf bytes.Buffer
+	err := Encode(&buf, rows, opts...)
+	return buf.Bytes(), err
+}
+
+// Encode writes rows, a slice of structs or struct pointers, to w as CSV
+func Encode(w io.Writer, rows any, opts ...OptionFunc) error {
+	v := reflect.ValueOf(rows)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return fmt.Errorf("csvx: expected slice, got %T", rows)
+	}
+	t, err := structType(v.Type().Elem())
+	if err != nil {
+		return err
+	}
+	enc := newEncoder(w, t, opts)
+	for i := 0; i < v.Len(); i++ {
+		if err := enc.encode(v.Index(i)); err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	return enc.flush()
+}
+
+// Unmarshal decodes CSV from r into out, a pointer to a slice of structs or struct pointers
+func Unmarshal(r io.Reader, out any, opts ...OptionFunc) error {
+	pv := reflect.ValueOf(out)
+	if pv.Kind() != reflect.Pointer || pv.IsNil() || pv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("csvx: expected pointer to slice, got %T", out)
+	}
+	slice := pv.Elem()
+	elem := slice.Type().Elem()
+	t, err := structType(elem)
+	if err != nil {
+		return err
+	}
+	dec, err := newDecoder(r, t, opts)
+	if err != nil {
+		return err
+	}
+	for {
+		row := reflect.New(t)
+		err := dec.decode(row.Elem())
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if elem.Kind() == reflect.Pointer {
+			slice.Set(reflect.Append(slice, row))
+		} else {
+			slice.Set(reflect.Append(slice, row.Elem()))
+		}
+	}
+}
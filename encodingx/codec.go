@@ -0,0 +1,149 @@
+package encodingx
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Built-in formats
+const (
+	JSON    = "json"
+	YAML    = "yaml"
+	TOML    = "toml"
+	XML     = "xml"
+	MsgPack = "msgpack"
+)
+
+// Codec converts values to and from one serialization format
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+	ContentType() string // MIME type written in Content-Type headers
+}
+
+// UnknownFormatError is returned when no codec is registered for a format
+type UnknownFormatError struct {
+	Format string
+}
+
+func (e *UnknownFormatError) Error() string {
+	return fmt.Sprintf("encodingx: unknown format %q", e.Format)
+}
+
+var (
+	mu      sync.RWMutex
+	codecs  = make(map[string]Codec)  // Format name -> codec
+	aliases = make(map[string]string) // Extension or MIME type -> format name
+)
+
+// Register registers c under format, replacing any previous codec of that name
+// aliases are extra names resolving to the format, typically file extensions ("yml") and
+// MIME types ("application/x-yaml"); the codec content type is always an alias
+func Register(format string, c Codec, alias ...string) {
+	format = normalize(format)
+	mu.Lock()
+	defer mu.Unlock()
+	codecs[format] = c
+	for _, a := range append(alias, c.ContentType()) {
+		aliases[normalize(a)] = format
+	}
+}
+
+// Lookup returns the codec for a format name, file extension (".yml") or MIME type
+// ("application/json; charset=utf-8"). Any "+json" style structured syntax suffix resolves too
+func Lookup(name string) (Codec, bool) {
+	key := normalize(name)
+	mu.RLock()
+	defer mu.RUnlock()
+	if c, ok := codecs[key]; ok {
+		return c, true
+	}
+	if format, ok := aliases[key]; ok {
+		return codecs[format], true
+	}
+	// application/problem+json, application/vnd.api+json, ...
+	if _, suffix, ok := strings.Cut(key, "+"); ok {
+		if c, ok := codecs[suffix]; ok {
+			return c, true
+		}
+	}
+	return nil, false
+}
+
+// Formats returns the registered format names
+func Formats() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+	return names
+}
+
+// FormatOf returns the format name for a file path by its extension, or "" if none matches
+func FormatOf(path string) string {
+	ext := normalize(filepath.Ext(path))
+	mu.RLock()
+	defer mu.RUnlock()
+	if _, ok := codecs[ext]; ok {
+		return ext
+	}
+	return aliases[ext]
+}
+
+// Marshal encodes v in the given format
+func Marshal(format string, v any) ([]byte, error) {
+	c, ok := Lookup(format)
+	if !ok {
+		return nil, &UnknownFormatError{Format: format}
+	}
+	return c.Marshal(v)
+}
+
+// Unmarshal decodes data in the given format into v
+func Unmarshal(format string, data []byte, v any) error {
+	c, ok := Lookup(format)
+	if !ok {
+		return &UnknownFormatError{Format: format}
+	}
+	return c.Unmarshal(data, v)
+}
+
+// Encode writes v to w in the given format
+func Encode(w io.Writer, format string, v any) error {
+	b, err := Marshal(format, v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+// Decode reads r to the end and decodes it in the given format into v
+func Decode(r io.Reader, format string, v any) error {
+	c, ok := Lookup(format)
+	if !ok {
+		return &UnknownFormatError{Format: format}
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(b, v)
+}
+
+// normalize lowercases name, strips a leading dot and MIME parameters
+func normalize(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if strings.Contains(name, "/") {
+		if mt, _, err := mime.ParseMediaType(name); err == nil {
+			return mt
+		}
+	}
+	return strings.TrimPrefix(name, ".")
+}
@@ -0,0 +1,55 @@
+package encodingx
+
+import (
+	"bytes"
+	"encoding/xml"
+	"github.com/BurntSushi/toml"
+	"github.com/chihqiang/gox/jsonx"
+	"github.com/vmihailenco/msgpack/v5"
+	"gopkg.in/yaml.v3"
+)
+
+func init() {
+	Register(JSON, jsonCodec{}, "text/json")
+	Register(YAML, yamlCodec{}, "yml", "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml")
+	Register(TOML, tomlCodec{}, "text/toml")
+	Register(XML, xmlCodec{}, "text/xml")
+	Register(MsgPack, msgpackCodec{}, "mpk", "application/vnd.msgpack", "application/x-msgpack")
+}
+
+// jsonCodec uses jsonx, so numbers decoded into interfaces become json.Number
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return jsonx.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return jsonx.Unmarshal(data, v) }
+func (jsonCodec) ContentType() string                { return "application/json" }
+
+type yamlCodec struct{}
+
+func (yamlCodec) Marshal(v any) ([]byte, error)      { return yaml.Marshal(v) }
+func (yamlCodec) Unmarshal(data []byte, v any) error { return yaml.Unmarshal(data, v) }
+func (yamlCodec) ContentType() string                { return "application/yaml" }
+
+type tomlCodec struct{}
+
+func (tomlCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := toml.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+func (tomlCodec) Unmarshal(data []byte, v any) error { return toml.Unmarshal(data, v) }
+func (tomlCodec) ContentType() string                { return "application/toml" }
+
+type xmlCodec struct{}
+
+func (xmlCodec) Marshal(v any) ([]byte, error)      { return xml.Marshal(v) }
+func (xmlCodec) Unmarshal(data []byte, v any) error { return xml.Unmarshal(data, v) }
+func (xmlCodec) ContentType() string                { return "application/xml" }
+
+type msgpackCodec struct{}
+
+func (msgpackCodec) Marshal(v any) ([]byte, error)      { return msgpack.Marshal(v) }
+func (msgpackCodec) Unmarshal(data []byte, v any) error { return msgpack.Unmarshal(data, v) }
+func (msgpackCodec) ContentType() string                { return "application/msgpack" }
@@ -0,0 +1,268 @@
+package errorx
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// maxStackDepth limits the number of frames captured per error
+const maxStackDepth = 32
+
+// Error is an error with an optional business code, HTTP status, key/value metadata,
+// a wrapped cause and the call stack captured where it was created
+type Error struct {
+	msg    string
+	code   int            // Business code, 0 means unset
+	status int            // HTTP status, 0 means unset
+	fields map[string]any // Key/value metadata
+	cause  error          // Wrapped error
+	stack  []uintptr      // Program counters captured at creation
+}
+
+// New creates an error with msg and captures the current stack
+func New(msg string) *Error {
+	return &Error{msg: msg, stack: callers()}
+}
+
+// Newf creates an error with a formatted message and captures the current stack
+func Newf(format string, args ...any) *Error {
+	return &Error{msg: fmt.Sprintf(format, args...), stack: callers()}
+}
+
+// Wrap annotates err with msg and captures the current stack, nil err returns nil
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{msg: msg, cause: err, stack: callers()}
+}
+
+// Wrapf annotates err with a formatted message, nil err returns nil
+func Wrapf(err error, format string, args ...any) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{msg: fmt.Sprintf(format, args...), cause: err, stack: callers()}
+}
+
+// WrapCode annotates err with a business code and msg, nil err returns nil
+func WrapCode(err error, code int, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &Error{msg: msg, code: code, cause: err, stack: callers()}
+}
+
+// WithCode returns a copy of e carrying the business code
+func (e *Error) WithCode(code int) *Error {
+	c := e.clone()
+	c.code = code
+	return c
+}
+
+// WithStatus returns a copy of e carrying the HTTP status used by httpx responses
+func (e *Error) WithStatus(status int) *Error {
+	c := e.clone()
+	c.status = status
+	return c
+}
+
+// With returns a copy of e with key/value pairs added to its metadata
+// Keys are formatted with fmt.Sprint, a trailing key without value is stored as nil
+func (e *Error) With(kv ...any) *Error {
+	c := e.clone()
+	c.fields = maps.Clone(e.fields)
+	if c.fields == nil {
+		c.fields = make(map[string]any, len(kv)/2+1)
+	}
+	for i := 0; i < len(kv); i += 2 {
+		var v any
+		if i+1 < len(kv) {
+			v = kv[i+1]
+		}
+		c.fields[fmt.Sprint(kv[i])] = v
+	}
+	return c
+}
+
+// Error implements error, the message is followed by the wrapped cause
+func (e *Error) Error() string {
+	if e.cause == nil {
+		return e.msg
+	}
+	if e.msg == "" {
+		return e.cause.Error()
+	}
+	return e.msg + ": " + e.cause.Error()
+}
+
+// Unwrap returns the wrapped cause so errors.Is and errors.As can walk the chain
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is a coded *Error with the same code and message,
+// so copies made by WithStatus or With still match their sentinel
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	if !ok {
+		return false
+	}
+	return e == t || (t.code != 0 && e.code == t.code && e.msg == t.msg && t.cause == nil)
+}
+
+// Message returns the message of e without the wrapped cause
+func (e *Error) Message() string {
+	return e.msg
+}
+
+// Code returns the business code of e, or the first one set along its chain
+func (e *Error) Code() int {
+	return Code(e)
+}
+
+// HTTPStatus returns the HTTP status of e, or the first one set along its chain
+func (e *Error) HTTPStatus() int {
+	for err := error(e); err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*Error); ok && x.status != 0 {
+			return x.status
+		}
+	}
+	return 0
+}
+
+// Fields returns the metadata of the whole chain, outer errors override inner ones
+func (e *Error) Fields() map[string]any {
+	return Fields(e)
+}
+
+// StackTrace returns the stack of the innermost *Error in the chain, one frame per two lines
+func (e *Error) StackTrace() string {
+	return Stack(e)
+}
+
+// Format implements fmt.Formatter, %+v prints the message, metadata and stack trace
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			_, _ = io.WriteString(s, e.Error())
+			if fields := e.Fields(); len(fields) > 0 {
+				_, _ = io.WriteString(s, " "+formatFields(fields))
+			}
+			_, _ = io.WriteString(s, "\n"+e.StackTrace())
+			return
+		}
+		_, _ = io.WriteString(s, e.Error())
+	case 's', 'q', 'x', 'X':
+		_, _ = fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+	default:
+		// Report the bad verb the way fmt does
+		_, _ = fmt.Fprintf(s, "%%!%c(%T=%s)", verb, e, e.Error())
+	}
+}
+
+func (e *Error) clone() *Error {
+	c := *e
+	return &c
+}
+
+// Code returns the first business code found in the chain of err, 0 if none
+func Code(err error) int {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*Error); ok && x.code != 0 {
+			return x.code
+		}
+	}
+	return 0
+}
+
+// Fields returns the metadata collected along the chain of err, outer errors override inner ones
+func Fields(err error) map[string]any {
+	var chain []*Error
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*Error); ok {
+			chain = append(chain, x)
+		}
+	}
+	var fields map[string]any
+	for i := len(chain) - 1; i >= 0; i-- {
+		if len(chain[i].fields) == 0 {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]any)
+		}
+		maps.Copy(fields, chain[i].fields)
+	}
+	return fields
+}
+
+// Stack returns the stack trace of the innermost *Error in the chain of err, "" if none
+func Stack(err error) string {
+	var pcs []uintptr
+	for ; err != nil; err = errors.Unwrap(err) {
+		if x, ok := err.(*Error); ok && len(x.stack) > 0 {
+			pcs = x.stack
+		}
+	}
+	if len(pcs) == 0 {
+		return ""
+	}
+	var sb strings.Builder
+	frames := runtime.CallersFrames(pcs)
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+	}
+	return sb.String()
+}
+
+// Cause returns the innermost error of the chain
+func Cause(err error) error {
+	for {
+		next := errors.Unwrap(err)
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+}
+
+// Is is errors.Is, re-exported for convenience
+func Is(err, target error) bool {
+	return errors.Is(err, target)
+}
+
+// As is errors.As, re-exported for convenience
+func As(err error, target any) bool {
+	return errors.As(err, target)
+}
+
+// callers captures the stack of the function calling the errorx constructor
+func callers() []uintptr {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(3, pcs)
+	return pcs[:n]
+}
+
+func formatFields(fields map[string]any) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s=%v", k, fields[k])
+	}
+	return strings.Join(parts, " ")
+}
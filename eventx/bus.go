@@ -0,0 +1,250 @@
+package eventx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/logx"
+	"runtime/debug"
+	"sync"
+)
+
+// ErrClosed is returned by Publish after the bus is closed
+var ErrClosed = errors.New("eventx: bus is closed")
+
+// Handler processes an event of type T
+type Handler[T any] func(ctx context.Context, event T) error
+
+// ErrorHandler receives errors and recovered panics of asynchronous subscribers
+type ErrorHandler func(topic string, err error)
+
+// Option bus configuration structure
+type Option struct {
+	OnError ErrorHandler // Receives async handler errors, defaults to logging via logx
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithErrorHandler sets the handler for errors raised by asynchronous subscribers
+func WithErrorHandler(fn ErrorHandler) OptionFunc {
+	return func(o *Option) { o.OnError = fn }
+}
+
+// SubscribeOption subscription configuration structure
+type SubscribeOption struct {
+	Async  bool // Deliver on a dedicated goroutine instead of the publisher's
+	Buffer int  // Queue size of an async subscriber, Publish blocks while it is full
+}
+
+// SubscribeOptionFunc functional subscription configuration type
+type SubscribeOptionFunc func(*SubscribeOption)
+
+// WithAsync delivers events on a dedicated goroutine with a queue of buffer events
+// Events are handled in publish order; Publish waits for space when the queue is full
+func WithAsync(buffer int) SubscribeOptionFunc {
+	return func(o *SubscribeOption) {
+		o.Async = true
+		o.Buffer = buffer
+	}
+}
+
+// delivery is an event queued for an async subscriber
+type delivery struct {
+	ctx   context.Context
+	event any
+}
+
+// subscriber is a type-erased handler registered on a topic
+type subscriber struct {
+	id      uint64
+	topic   string
+	handle  func(ctx context.Context, event any) error // Ignores events of another type
+	queue   chan delivery                              // nil for sync subscribers
+	quit    chan struct{}                              // Closed to tell the async worker to drain and exit
+	done    chan struct{}                              // Closed when the async worker exits
+	stopped sync.Once
+}
+
+// Bus is an in-process publish/subscribe event bus
+type Bus struct {
+	opt    Option
+	mu     sync.RWMutex
+	topics map[string][]*subscriber
+	nextID uint64
+	closed bool
+}
+
+// New creates an event bus
+func New(opts ...OptionFunc) *Bus {
+	b := &Bus{topics: make(map[string][]*subscriber)}
+	for _, opt := range opts {
+		opt(&b.opt)
+	}
+	if b.opt.OnError == nil {
+		b.opt.OnError = func(topic string, err error) {
+			logx.Error("eventx: topic %s: %v", topic, err)
+		}
+	}
+	return b
+}
+
+// Subscribe registers fn for events of type T published on topic and returns a function
+// that removes the subscription. Events published on the topic with another type are not delivered
+func Subscribe[T any](b *Bus, topic string, fn Handler[T], opts ...SubscribeOptionFunc) (unsubscribe func()) {
+	var so SubscribeOption
+	for _, opt := range opts {
+		opt(&so)
+	}
+	s := &subscriber{
+		topic: topic,
+		handle: func(ctx context.Context, event any) error {
+			ev, ok := event.(T)
+			if !ok {
+				return nil
+			}
+			return safeCall(ctx, fn, ev)
+		},
+	}
+	if so.Async {
+		s.queue = make(chan delivery, max(so.Buffer, 0))
+		s.quit = make(chan struct{})
+		s.done = make(chan struct{})
+		go b.worker(s)
+	}
+
+	b.mu.Lock()
+	b.nextID++
+	s.id = b.nextID
+	if b.closed {
+		b.mu.Unlock()
+		b.stop(s)
+		return func() {}
+	}
+	b.topics[topic] = append(b.topics[topic], s)
+	b.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			subs := b.topics[topic]
+			for i, x := range subs {
+				if x == s {
+					b.topics[topic] = append(subs[:i:i], subs[i+1:]...)
+					break
+				}
+			}
+			b.mu.Unlock()
+			b.stop(s)
+		})
+	}
+}
+
+// Publish delivers event to every subscriber of topic registered for type T
+// Sync subscribers run in the calling goroutine and their errors, including recovered panics,
+// are joined into the returned error; async subscribers are queued and report errors to
+// the bus ErrorHandler. Publish returns ctx.Err() if it is cancelled while waiting for queue space
+func Publish[T any](ctx context.Context, b *Bus, topic string, event T) error {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return ErrClosed
+	}
+	subs := append([]*subscriber(nil), b.topics[topic]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, s := range subs {
+		if s.queue == nil {
+			if err := s.handle(ctx, event); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		select {
+		case s.queue <- delivery{ctx: context.WithoutCancel(ctx), event: event}:
+		case <-s.quit:
+		case <-ctx.Done():
+			return errors.Join(append(errs, ctx.Err())...)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Close stops accepting events and waits until async subscribers have drained their queues
+// or ctx is done, in which case ctx.Err() is returned
+func (b *Bus) Close(ctx context.Context) error {
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return nil
+	}
+	b.closed = true
+	var subs []*subscriber
+	for _, list := range b.topics {
+		subs = append(subs, list...)
+	}
+	b.topics = make(map[string][]*subscriber)
+	b.mu.Unlock()
+
+	for _, s := range subs {
+		b.stop(s)
+	}
+	for _, s := range subs {
+		if s.done == nil {
+			continue
+		}
+		select {
+		case <-s.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// stop tells the worker of an async subscriber to drain its queue and exit
+func (b *Bus) stop(s *subscriber) {
+	if s.quit != nil {
+		s.stopped.Do(func() { close(s.quit) })
+	}
+}
+
+// worker delivers queued events to an async subscriber in order
+// Once stopped it handles the events already queued and exits; the queue is never closed,
+// so a concurrent Publish cannot panic and events it queues after the drain are dropped
+func (b *Bus) worker(s *subscriber) {
+	defer close(s.done)
+	for {
+		select {
+		case d := <-s.queue:
+			b.deliver(s, d)
+		case <-s.quit:
+			for {
+				select {
+				case d := <-s.queue:
+					b.deliver(s, d)
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (b *Bus) deliver(s *subscriber, d delivery) {
+	if err := s.handle(d.ctx, d.event); err != nil {
+		b.opt.OnError(s.topic, err)
+	}
+}
+
+// safeCall runs fn, converting a panic into an error so one subscriber cannot break the others
+func safeCall[T any](ctx context.Context, fn Handler[T], event T) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("eventx: handler panicked: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return fn(ctx, event)
+}
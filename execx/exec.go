@@ -0,0 +1,257 @@
+package execx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/logx"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Stream identifies the output stream a line was read from
+type Stream int
+
+const (
+	Stdout Stream = iota // Standard output
+	Stderr               // Standard error
+)
+
+// String returns "stdout" or "stderr"
+func (s Stream) String() string {
+	if s == Stderr {
+		return "stderr"
+	}
+	return "stdout"
+}
+
+// Option command configuration structure
+type Option struct {
+	Dir       string                           // Working directory, empty means the current one
+	Env       []string                         // Extra "KEY=value" entries appended to the inherited environment
+	Timeout   time.Duration                    // Maximum run time, 0 means no limit besides ctx
+	Stdin     io.Reader                        // Standard input, nil means empty
+	OnLine    func(stream Stream, line string) // Called for every output line as it is produced
+	LogOutput bool                             // Stream output lines to logx (stdout as Info, stderr as Warn)
+	WaitDelay time.Duration                    // Time to wait for output pipes after the process is killed, default 2s
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithDir sets the working directory
+func WithDir(dir string) OptionFunc {
+	return func(o *Option) { o.Dir = dir }
+}
+
+// WithEnv appends "KEY=value" entries to the inherited environment, later entries win
+func WithEnv(kv ...string) OptionFunc {
+	return func(o *Option) { o.Env = append(o.Env, kv...) }
+}
+
+// WithTimeout kills the command when it runs longer than d
+func WithTimeout(d time.Duration) OptionFunc {
+	return func(o *Option) { o.Timeout = d }
+}
+
+// WithStdin sets the standard input of the command
+func WithStdin(r io.Reader) OptionFunc {
+	return func(o *Option) { o.Stdin = r }
+}
+
+// WithLineHandler calls fn for every output line while the command runs
+func WithLineHandler(fn func(stream Stream, line string)) OptionFunc {
+	return func(o *Option) { o.OnLine = fn }
+}
+
+// WithLogOutput streams output lines to logx while the command runs
+func WithLogOutput() OptionFunc {
+	return func(o *Option) { o.LogOutput = true }
+}
+
+// Result holds the outcome of a finished command
+type Result struct {
+	Stdout   []byte        // Captured standard output
+	Stderr   []byte        // Captured standard error
+	ExitCode int           // Exit code, -1 if the process was killed by a signal or did not start
+	Duration time.Duration // Wall time of the run
+}
+
+// ExitError is returned when the command exits with a non-zero code or is killed
+type ExitError struct {
+	Name     string
+	ExitCode int
+	Stderr   string // Last part of standard error, for diagnostics
+	Err      error  // Underlying *exec.ExitError or context error
+}
+
+// Error implements error
+func (e *ExitError) Error() string {
+	msg := fmt.Sprintf("execx: %s exited with code %d", e.Name, e.ExitCode)
+	if e.Stderr != "" {
+		msg += ": " + e.Stderr
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error, e.g. context.DeadlineExceeded after a timeout
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// Cmd is a command to run, configured with options
+type Cmd struct {
+	Name string
+	Args []string
+	opt  Option
+}
+
+// Command returns a Cmd running name with args
+func Command(name string, args ...string) *Cmd {
+	return &Cmd{Name: name, Args: args, opt: Option{WaitDelay: 2 * time.Second}}
+}
+
+// With applies options to c and returns it
+func (c *Cmd) With(opts ...OptionFunc) *Cmd {
+	for _, opt := range opts {
+		opt(&c.opt)
+	}
+	return c
+}
+
+// Run runs name with args and default options, see Cmd.Run
+func Run(ctx context.Context, name string, args ...string) (*Result, error) {
+	return Command(name, args...).Run(ctx)
+}
+
+// Run starts the command and waits for it to finish
+// The command runs in its own process group, which is killed as a whole when ctx is done
+// or the timeout expires, so children spawned by the command are cleaned up too.
+// A non-zero exit returns the Result together with an *ExitError
+func (c *Cmd) Run(ctx context.Context) (*Result, error) {
+	if c.opt.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.opt.Timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(ctx, c.Name, c.Args...)
+	cmd.Dir = c.opt.Dir
+	if len(c.opt.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.opt.Env...)
+	}
+	cmd.Stdin = c.opt.Stdin
+	cmd.WaitDelay = c.opt.WaitDelay
+	setProcessGroup(cmd)
+
+	var stdout, stderr bytes.Buffer
+	outW := c.lineWriter(Stdout, &stdout)
+	errW := c.lineWriter(Stderr, &stderr)
+	cmd.Stdout, cmd.Stderr = outW, errW
+
+	begin := time.Now()
+	err := cmd.Run()
+	outW.flush()
+	errW.flush()
+
+	res := &Result{
+		Stdout:   stdout.Bytes(),
+		Stderr:   stderr.Bytes(),
+		ExitCode: cmd.ProcessState.ExitCode(),
+		Duration: time.Since(begin),
+	}
+	if err == nil {
+		return res, nil
+	}
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) && cmd.ProcessState == nil {
+		// The process did not start
+		res.ExitCode = -1
+		return res, err
+	}
+	if ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	return res, &ExitError{Name: c.Name, ExitCode: res.ExitCode, Stderr: tail(res.Stderr, 512), Err: err}
+}
+
+// Output runs the command and returns its trimmed standard output
+func (c *Cmd) Output(ctx context.Context) (string, error) {
+	res, err := c.Run(ctx)
+	if res == nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(res.Stdout)), err
+}
+
+func (c *Cmd) lineWriter(stream Stream, buf *bytes.Buffer) *lineWriter {
+	w := &lineWriter{buf: buf}
+	if c.opt.OnLine == nil && !c.opt.LogOutput {
+		return w
+	}
+	name := c.Name
+	onLine, logOutput := c.opt.OnLine, c.opt.LogOutput
+	w.emit = func(line string) {
+		if logOutput {
+			if stream == Stderr {
+				logx.Warn("[%s] %s", name, line)
+			} else {
+				logx.Info("[%s] %s", name, line)
+			}
+		}
+		if onLine != nil {
+			onLine(stream, line)
+		}
+	}
+	return w
+}
+
+// lineWriter captures output and emits complete lines as they arrive
+type lineWriter struct {
+	mu      sync.Mutex
+	buf     *bytes.Buffer
+	emit    func(line string)
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf.Write(p)
+	if w.emit == nil {
+		return len(p), nil
+	}
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(strings.TrimRight(string(w.partial[:i]), "\r"))
+		w.partial = w.partial[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush emits a trailing line without newline
+func (w *lineWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.emit != nil && len(w.partial) > 0 {
+		w.emit(strings.TrimRight(string(w.partial), "\r"))
+		w.partial = nil
+	}
+}
+
+// tail returns at most the last n bytes of b as a trimmed string
+func tail(b []byte, n int) string {
+	if len(b) > n {
+		b = b[len(b)-n:]
+	}
+	return strings.TrimSpace(string(b))
+}
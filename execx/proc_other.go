@@ -0,0 +1,8 @@
+//go:build !unix
+
+package execx
+
+import "os/exec"
+
+// setProcessGroup is a no-op where process groups are not supported, only the process is killed
+func setProcessGroup(cmd *exec.Cmd) {}
@@ -0,0 +1,38 @@
+package filex
+
+import (
+	"fmt"
+	"github.com/chihqiang/gox/cryptox"
+	"strings"
+)
+
+// Checksum returns the hex-encoded digest of the file at path
+func Checksum(path string, alg cryptox.Algorithm) (string, error) {
+	sum, err := cryptox.SumFile(alg, path)
+	if err != nil {
+		return "", err
+	}
+	return cryptox.Hex(sum), nil
+}
+
+// SHA256 returns the hex-encoded SHA-256 digest of the file at path
+func SHA256(path string) (string, error) {
+	return Checksum(path, cryptox.AlgSHA256)
+}
+
+// MD5 returns the hex-encoded MD5 digest of the file at path
+func MD5(path string) (string, error) {
+	return Checksum(path, cryptox.AlgMD5)
+}
+
+// VerifyChecksum checks the file at path against an expected hex digest (case-insensitive)
+func VerifyChecksum(path string, alg cryptox.Algorithm, expected string) error {
+	got, err := Checksum(path, alg)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(got, strings.TrimSpace(expected)) {
+		return fmt.Errorf("%s checksum mismatch for %s: expected %s, got %s", alg, path, expected, got)
+	}
+	return nil
+}
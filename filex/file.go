@@ -0,0 +1,199 @@
+package filex
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// DefaultDirPerm is used when creating directories
+	DefaultDirPerm fs.FileMode = 0o755
+	// DefaultFilePerm is used when creating new files
+	DefaultFilePerm fs.FileMode = 0o644
+)
+
+// Exists reports whether path exists (file, directory or anything else)
+// Paths that cannot be checked, e.g. for lack of permission, are reported as missing
+func Exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// IsDir reports whether path exists and is a directory
+func IsDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// IsFile reports whether path exists and is a regular file
+func IsFile(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.Mode().IsRegular()
+}
+
+// EnsureDir creates path and any missing parents with DefaultDirPerm
+func EnsureDir(path string) error {
+	return os.MkdirAll(path, DefaultDirPerm)
+}
+
+// ReadLines reads the file at path and returns its lines without line terminators
+func ReadLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	return lines, scanner.Err()
+}
+
+// AtomicWrite writes data to path so that readers see either the old or the new content, never
+// a partial file: data is written to a temp file in the same directory, fsynced, then renamed
+// over path. An existing file's permissions are preserved, new files get DefaultFilePerm
+func AtomicWrite(path string, data []byte) error {
+	return AtomicWriteReader(path, bytes.NewReader(data))
+}
+
+// AtomicWriteReader is like AtomicWrite but streams the content from r
+func AtomicWriteReader(path string, r io.Reader) (err error) {
+	perm := DefaultFilePerm
+	if info, statErr := os.Stat(path); statErr == nil {
+		perm = info.Mode().Perm()
+	}
+	dir := filepath.Dir(path)
+	if err := EnsureDir(dir); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// Remove the temp file on any failure
+	defer func() {
+		if err != nil {
+			_ = tmp.Close()
+			_ = os.Remove(tmp.Name())
+		}
+	}()
+
+	if _, err = io.Copy(tmp, r); err != nil {
+		return fmt.Errorf("write %s failed: %w", tmp.Name(), err)
+	}
+	if err = tmp.Chmod(perm); err != nil {
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+	// Persist the rename itself
+	return syncDir(dir)
+}
+
+// CopyFile copies the regular file src to dst, preserving the permission bits
+// dst is written atomically and its parent directories are created if missing
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("copy %s: not a regular file", src)
+	}
+	if err := AtomicWriteReader(dst, in); err != nil {
+		return err
+	}
+	return os.Chmod(dst, info.Mode().Perm())
+}
+
+// CopyDir recursively copies the directory src to dst, preserving permissions
+// Symbolic links are recreated as links rather than followed
+func CopyDir(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("copy %s: not a directory", src)
+	}
+	absSrc, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	absDst, err := filepath.Abs(dst)
+	if err != nil {
+		return err
+	}
+	// Copying into itself would walk the copies as they are made
+	if rel, err := filepath.Rel(absSrc, absDst); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("copy %s: destination %s is inside the source", src, dst)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+
+		switch {
+		case d.IsDir():
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			return os.MkdirAll(target, info.Mode().Perm())
+		case d.Type()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case d.Type().IsRegular():
+			return CopyFile(path, target)
+		default:
+			return nil // Skip devices, sockets and pipes
+		}
+	})
+}
+
+// syncDir fsyncs a directory so that entry changes (create/rename) are durable
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	// Windows doesn't support syncing directories
+	if err := d.Sync(); err != nil && runtime.GOOS != "windows" {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,188 @@
+package filex
+
+import (
+	"context"
+	"github.com/fsnotify/fsnotify"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Op describes a set of file operations
+type Op uint32
+
+const (
+	OpCreate Op = 1 << iota // A new file or directory was created
+	OpWrite                 // The file content was written
+	OpRemove                // The file was removed
+	OpRename                // The file was renamed or moved away
+	OpChmod                 // The file attributes changed
+)
+
+// Has reports whether o contains op
+func (o Op) Has(op Op) bool {
+	return o&op != 0
+}
+
+// Event is a debounced change notification for a single path
+// Op accumulates every operation seen for the path during the debounce window
+type Event struct {
+	Path string
+	Op   Op
+}
+
+// WatchOption watcher configuration structure
+type WatchOption struct {
+	Recursive bool          // Watch sub-directories, including ones created later
+	Debounce  time.Duration // Quiet period before delivering a batch, default 100ms
+	Include   []string      // Glob patterns a path must match (base name or full path), empty means all
+	Exclude   []string      // Glob patterns that drop matching paths, checked after Include
+}
+
+// WatchOptionFunc functional configuration type
+type WatchOptionFunc func(*WatchOption)
+
+// WithRecursive watches directories recursively
+func WithRecursive() WatchOptionFunc {
+	return func(o *WatchOption) { o.Recursive = true }
+}
+
+// WithDebounce sets the quiet period used to coalesce bursts of events
+func WithDebounce(d time.Duration) WatchOptionFunc {
+	return func(o *WatchOption) { o.Debounce = d }
+}
+
+// WithInclude only reports paths matching one of the glob patterns, e.g. "*.yaml"
+func WithInclude(patterns ...string) WatchOptionFunc {
+	return func(o *WatchOption) { o.Include = append(o.Include, patterns...) }
+}
+
+// WithExclude drops paths matching one of the glob patterns, e.g. "*.swp"
+func WithExclude(patterns ...string) WatchOptionFunc {
+	return func(o *WatchOption) { o.Exclude = append(o.Exclude, patterns...) }
+}
+
+// Watch watches paths (files or directories) and calls fn with batches of debounced events
+// until ctx is done. Events are sorted by path and fn is never called concurrently
+// Watch blocks, and returns nil when ctx is cancelled or an error if watching fails
+func Watch(ctx context.Context, paths []string, fn func(events []Event), opts ...WatchOptionFunc) error {
+	o := &WatchOption{Debounce: 100 * time.Millisecond}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	for _, p := range paths {
+		if err := addWatch(w, p, o.Recursive); err != nil {
+			return err
+		}
+	}
+
+	pending := make(map[string]Op)
+	timer := time.NewTimer(o.Debounce)
+	timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			return err
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			// Follow newly created directories when watching recursively
+			if o.Recursive && ev.Has(fsnotify.Create) && IsDir(ev.Name) {
+				_ = addWatch(w, ev.Name, true)
+			}
+			if !o.match(ev.Name) {
+				continue
+			}
+			pending[ev.Name] |= convertOp(ev.Op)
+			timer.Reset(o.Debounce)
+		case <-timer.C:
+			if len(pending) == 0 {
+				continue
+			}
+			events := make([]Event, 0, len(pending))
+			for path, op := range pending {
+				events = append(events, Event{Path: path, Op: op})
+			}
+			sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+			pending = make(map[string]Op)
+			fn(events)
+		}
+	}
+}
+
+// addWatch adds path to the watcher, walking sub-directories when recursive
+func addWatch(w *fsnotify.Watcher, path string, recursive bool) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if !recursive || !info.IsDir() {
+		return w.Add(path)
+	}
+	return filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return w.Add(p)
+		}
+		return nil
+	})
+}
+
+// match applies the include and exclude glob filters
+func (o *WatchOption) match(path string) bool {
+	if len(o.Include) > 0 && !matchAny(o.Include, path) {
+		return false
+	}
+	return !matchAny(o.Exclude, path)
+}
+
+func matchAny(patterns []string, path string) bool {
+	base := filepath.Base(path)
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func convertOp(op fsnotify.Op) Op {
+	var o Op
+	if op.Has(fsnotify.Create) {
+		o |= OpCreate
+	}
+	if op.Has(fsnotify.Write) {
+		o |= OpWrite
+	}
+	if op.Has(fsnotify.Remove) {
+		o |= OpRemove
+	}
+	if op.Has(fsnotify.Rename) {
+		o |= OpRename
+	}
+	if op.Has(fsnotify.Chmod) {
+		o |= OpChmod
+	}
+	return o
+}
@@ -0,0 +1,129 @@
+package flagx
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+)
+
+// Command is a command line (sub)command whose flags are bound to a config struct
+type Command struct {
+	Name        string                                         // Name used to select the subcommand
+	Usage       string                                         // One-line description shown in help
+	Config      any                                            // Pointer to the struct bound to flags, may be nil
+	Run         func(ctx context.Context, args []string) error // Receives the positional arguments
+	Subcommands []*Command
+}
+
+// Execute parses the arguments against the command tree rooted at root and runs the selected command
+// Flags of a command come before the name of its subcommand: "app -v serve -port 80 extra-arg".
+// A command without Run prints its help, -h prints the help of the current command and returns flag.ErrHelp
+func Execute(ctx context.Context, root *Command, opts ...OptionFunc) error {
+	o := newOption(opts)
+	name := root.Name
+	if name == "" {
+		name = o.Name
+	}
+	err := execute(ctx, root, name, o.Description, o.Args, o)
+	if o.ExitOnError && err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintln(o.Output, err)
+		os.Exit(2)
+	}
+	return err
+}
+
+func execute(ctx context.Context, cmd *Command, path, desc string, args []string, o *Option) error {
+	fs := flag.NewFlagSet(path, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fields, err := bind(fs, cmd.Config, o)
+	if err != nil {
+		return err
+	}
+	if desc == "" {
+		desc = cmd.Usage
+	}
+	help := func() { printHelp(o.Output, path, desc, cmd, fields) }
+
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			help()
+			return err
+		}
+		return fmt.Errorf("flagx: %s: %w", path, err)
+	}
+	rest := fs.Args()
+	if len(rest) > 0 {
+		for _, sub := range cmd.Subcommands {
+			if sub.Name == rest[0] {
+				return execute(ctx, sub, path+" "+sub.Name, "", rest[1:], o)
+			}
+		}
+	}
+	if cmd.Run == nil {
+		help()
+		if len(rest) > 0 {
+			return fmt.Errorf("flagx: %s: unknown command %q", path, rest[0])
+		}
+		return flag.ErrHelp
+	}
+	return cmd.Run(ctx, rest)
+}
+
+// printHelp writes the generated help screen of cmd
+func printHelp(w io.Writer, path, desc string, cmd *Command, fields []*field) {
+	usage := "Usage: " + path
+	if len(cmd.Subcommands) > 0 {
+		usage += " [flags] <command>"
+	} else if len(fields) > 0 {
+		usage += " [flags]"
+	}
+	fmt.Fprintln(w, usage)
+	if desc != "" {
+		fmt.Fprintf(w, "\n%s\n", desc)
+	}
+
+	if len(cmd.Subcommands) > 0 {
+		fmt.Fprintln(w, "\nCommands:")
+		tw := tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+		for _, sub := range cmd.Subcommands {
+			fmt.Fprintf(tw, "  %s\t%s\n", sub.Name, sub.Usage)
+		}
+		tw.Flush()
+	}
+
+	if len(fields) > 0 {
+		fmt.Fprintln(w, "\nFlags:")
+		for _, f := range fields {
+			names := "-" + f.name
+			if f.short != "" {
+				names = "-" + f.short + ", " + names
+			}
+			if t := f.value.typeName(); t != "" {
+				names += " " + t
+			}
+			var extra []string
+			if f.def != "" {
+				extra = append(extra, "default "+f.def)
+			}
+			if f.env != "" {
+				extra = append(extra, "env $"+f.env)
+			}
+			line := f.usage
+			if len(extra) > 0 {
+				line = strings.TrimSpace(line + " (" + strings.Join(extra, ", ") + ")")
+			}
+			fmt.Fprintf(w, "  %s\n    \t%s\n", names, line)
+		}
+	}
+	if len(cmd.Subcommands) > 0 {
+		fmt.Fprintf(w, "\nRun '%s <command> -h' for more information on a command.\n", path)
+	}
+}
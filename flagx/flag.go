@@ -0,0 +1,196 @@
+package flagx
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"github.com/chihqiang/gox/stringx"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// Option parsing configuration structure
+type Option struct {
+	Name        string    // Program name shown in help, defaults to the base name of os.Args[0]
+	Description string    // Text printed below the usage line of the root command
+	Args        []string  // Arguments to parse, defaults to os.Args[1:]
+	EnvPrefix   string    // Derive an env var for every flag, e.g. "APP" maps -db-host to APP_DB_HOST
+	Output      io.Writer // Destination of help and error messages, defaults to os.Stderr
+	ExitOnError bool      // Exit the process on parse errors (status 2) and -h (status 0)
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithName sets the program name shown in help
+func WithName(name string) OptionFunc {
+	return func(o *Option) { o.Name = name }
+}
+
+// WithDescription sets the text printed below the root usage line
+func WithDescription(desc string) OptionFunc {
+	return func(o *Option) { o.Description = desc }
+}
+
+// WithArgs parses args instead of os.Args[1:]
+func WithArgs(args []string) OptionFunc {
+	return func(o *Option) { o.Args = args }
+}
+
+// WithEnvPrefix reads every flag without an explicit env tag from PREFIX_FLAG_NAME
+func WithEnvPrefix(prefix string) OptionFunc {
+	return func(o *Option) { o.EnvPrefix = prefix }
+}
+
+// WithOutput sets the destination of help and error messages
+func WithOutput(w io.Writer) OptionFunc {
+	return func(o *Option) { o.Output = w }
+}
+
+// WithExitOnError exits the process on parse errors and after printing help, like flag.ExitOnError
+func WithExitOnError() OptionFunc {
+	return func(o *Option) { o.ExitOnError = true }
+}
+
+func newOption(opts []OptionFunc) *Option {
+	o := &Option{Args: os.Args[1:], Output: os.Stderr}
+	if len(os.Args) > 0 {
+		o.Name = filepath.Base(os.Args[0])
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Parse binds the fields of the struct pointed to by cfg to command line flags and parses os.Args[1:]
+//
+//	type Config struct {
+//		Port    int           `flag:"port" short:"p" default:"8080" usage:"listen port"`
+//		Timeout time.Duration `default:"5s" usage:"request timeout" env:"TIMEOUT"`
+//		DB      struct {
+//			Host string `default:"localhost"`
+//		} // -db-host
+//	}
+//
+// Flag names come from the flag tag or the kebab-cased field name, nested structs prefix their
+// fields with their own name, and `flag:"-"` skips a field. Values are resolved as flag > env var >
+// default tag, which is parsed like a flag value and only fills zero fields. -h prints the help and
+// returns flag.ErrHelp
+func Parse(cfg any, opts ...OptionFunc) error {
+	_, err := ParseArgs(cfg, opts...)
+	return err
+}
+
+// ParseArgs is like Parse and returns the positional arguments left after the flags
+func ParseArgs(cfg any, opts ...OptionFunc) ([]string, error) {
+	var rest []string
+	cmd := &Command{Config: cfg, Run: func(_ context.Context, args []string) error {
+		rest = args
+		return nil
+	}}
+	return rest, Execute(context.Background(), cmd, opts...)
+}
+
+// field is a struct field bound to a flag
+type field struct {
+	name  string
+	short string
+	usage string
+	env   string
+	def   string // Default shown in help, captured before env vars are applied
+	value *fieldValue
+}
+
+// bind applies env vars to cfg and registers its fields on fs
+func bind(fs *flag.FlagSet, cfg any, o *Option) ([]*field, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	v := reflect.ValueOf(cfg)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("flagx: expected non-nil pointer to struct, got %T", cfg)
+	}
+	var fields []*field
+	if err := collect(v.Elem(), "", o, &fields); err != nil {
+		return nil, err
+	}
+	for _, f := range fields {
+		if !f.value.v.IsZero() {
+			f.def = f.value.String()
+		}
+		if f.env != "" {
+			if s, ok := os.LookupEnv(f.env); ok {
+				if err := f.value.Set(s); err != nil {
+					return nil, fmt.Errorf("flagx: invalid value %q for env %s: %w", s, f.env, err)
+				}
+				// A flag given on the command line replaces the env value instead of appending to it
+				f.value.changed = false
+			}
+		}
+		fs.Var(f.value, f.name, f.usage)
+		if f.short != "" {
+			fs.Var(f.value, f.short, f.usage)
+		}
+	}
+	return fields, nil
+}
+
+// collect walks the struct fields of v, prefixing flag names with prefix and applying default tags
+func collect(v reflect.Value, prefix string, o *Option, fields *[]*field) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag := sf.Tag.Get("flag")
+		if !sf.IsExported() || tag == "-" {
+			continue
+		}
+		fv := v.Field(i)
+		if fv.Kind() == reflect.Pointer && fv.Type().Elem().Kind() == reflect.Struct && !supported(fv.Type().Elem()) {
+			if fv.IsNil() {
+				fv.Set(reflect.New(fv.Type().Elem()))
+			}
+			fv = fv.Elem()
+		}
+
+		name := tag
+		if name == "" {
+			name = stringx.KebabCase(sf.Name)
+		}
+		if fv.Kind() == reflect.Struct && !supported(fv.Type()) {
+			next := prefix + name + "-"
+			if sf.Anonymous && tag == "" {
+				next = prefix
+			}
+			if err := collect(fv, next, o, fields); err != nil {
+				return err
+			}
+			continue
+		}
+		if !supported(fv.Type()) {
+			return fmt.Errorf("flagx: field %s: unsupported type %s", sf.Name, fv.Type())
+		}
+
+		f := &field{
+			name:  prefix + name,
+			short: sf.Tag.Get("short"),
+			usage: sf.Tag.Get("usage"),
+			env:   sf.Tag.Get("env"),
+			value: &fieldValue{v: fv},
+		}
+		if def, ok := sf.Tag.Lookup("default"); ok && fv.IsZero() {
+			if err := f.value.Set(def); err != nil {
+				return fmt.Errorf("flagx: invalid default %q for field %s: %w", def, sf.Name, err)
+			}
+			f.value.changed = false
+		}
+		if f.env == "" && o.EnvPrefix != "" {
+			f.env = strings.ToUpper(o.EnvPrefix + "_" + strings.ReplaceAll(f.name, "-", "_"))
+		}
+		*fields = append(*fields, f)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package flagx
+
+import (
+	"net"
+	"testing"
+	"time"
+)
+
+func TestParseTextDefaults(t *testing.T) {
+	var cfg struct {
+		Addr  net.IP    `default:"127.0.0.1"`
+		Since time.Time `default:"2024-05-06T07:08:09Z"`
+		Port  int       `default:"8080"`
+	}
+	if err := Parse(&cfg, WithArgs(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Addr.Equal(net.IPv4(127, 0, 0, 1)) {
+		t.Errorf("Addr = %v, want 127.0.0.1", cfg.Addr)
+	}
+	if want := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC); !cfg.Since.Equal(want) {
+		t.Errorf("Since = %v, want %v", cfg.Since, want)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", cfg.Port)
+	}
+}
+
+func TestParseDefaultOverridden(t *testing.T) {
+	var cfg struct {
+		Addr net.IP   `default:"127.0.0.1"`
+		Tags []string `default:"a,b"`
+	}
+	if err := Parse(&cfg, WithArgs([]string{"-addr", "10.0.0.1", "-tags", "c"})); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Addr.Equal(net.IPv4(10, 0, 0, 1)) {
+		t.Errorf("Addr = %v, want 10.0.0.1", cfg.Addr)
+	}
+	if len(cfg.Tags) != 1 || cfg.Tags[0] != "c" {
+		t.Errorf("Tags = %v, want [c]", cfg.Tags)
+	}
+
+	preset := struct {
+		Port int `default:"8080"`
+	}{Port: 9000}
+	if err := Parse(&preset, WithArgs(nil)); err != nil {
+		t.Fatal(err)
+	}
+	if preset.Port != 9000 {
+		t.Errorf("Port = %d, want the preset 9000", preset.Port)
+	}
+}
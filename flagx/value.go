@@ -0,0 +1,159 @@
+package flagx
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// fieldValue is a flag.Value writing into a struct field
+type fieldValue struct {
+	v       reflect.Value
+	changed bool // Set was called, slices replace their default on the first call and append afterwards
+}
+
+// String implements flag.Value, it must cope with the zero fieldValue the flag package creates
+func (f *fieldValue) String() string {
+	if f == nil || !f.v.IsValid() {
+		return ""
+	}
+	return format(f.v)
+}
+
+// Set implements flag.Value, slices accept comma-separated values and repeated flags
+func (f *fieldValue) Set(s string) error {
+	if f.v.Kind() == reflect.Slice && !isText(f.v) {
+		if !f.changed {
+			f.v.Set(reflect.MakeSlice(f.v.Type(), 0, 0))
+		}
+		f.changed = true
+		for _, part := range strings.Split(s, ",") {
+			elem := reflect.New(f.v.Type().Elem()).Elem()
+			if err := parse(elem, strings.TrimSpace(part)); err != nil {
+				return err
+			}
+			f.v.Set(reflect.Append(f.v, elem))
+		}
+		return nil
+	}
+	f.changed = true
+	return parse(f.v, s)
+}
+
+// IsBoolFlag lets bool fields be given as -verbose without a value
+func (f *fieldValue) IsBoolFlag() bool {
+	return f.v.IsValid() && f.v.Kind() == reflect.Bool
+}
+
+// typeName describes the expected value in help output, empty for bools
+func (f *fieldValue) typeName() string {
+	t := f.v.Type()
+	switch {
+	case t.Kind() == reflect.Bool:
+		return ""
+	case t == durationType:
+		return "duration"
+	case isText(f.v):
+		return "value"
+	case t.Kind() == reflect.Slice:
+		return strings.ToLower(t.Elem().Kind().String()) + "s"
+	default:
+		return strings.ToLower(t.Kind().String())
+	}
+}
+
+// isText reports whether v parses itself from text, e.g. time.Time or net.IP
+func isText(v reflect.Value) bool {
+	_, ok := v.Addr().Interface().(encoding.TextUnmarshaler)
+	return ok
+}
+
+// supported reports whether a field of type t can be bound to a flag
+func supported(t reflect.Type) bool {
+	if reflect.PointerTo(t).Implements(reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Slice:
+		return t.Elem().Kind() != reflect.Slice && supported(t.Elem())
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// parse converts s to the type of v and stores it
+func parse(v reflect.Value, s string) error {
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		return u.UnmarshalText([]byte(s))
+	}
+	if v.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 0, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+	default:
+		return fmt.Errorf("unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+// format renders v the way parse accepts it
+func format(v reflect.Value) string {
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		if v.Kind() == reflect.Struct && v.IsZero() {
+			return ""
+		}
+		b, _ := m.MarshalText()
+		return string(b)
+	}
+	if v.Type() == durationType {
+		return time.Duration(v.Int()).String()
+	}
+	if v.Kind() == reflect.Slice {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = format(v.Index(i))
+		}
+		return strings.Join(parts, ",")
+	}
+	return fmt.Sprint(v.Interface())
+}
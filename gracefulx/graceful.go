@@ -0,0 +1,290 @@
+package gracefulx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/logx"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ErrAlreadyRunning is returned when Run is called more than once
+var ErrAlreadyRunning = errors.New("gracefulx: manager already running")
+
+// Hook is a component managed by the Manager
+// OnStart must return once the component is started; OnStop must release it before ctx expires
+type Hook struct {
+	Name         string                          // Name used in logs and errors
+	OnStart      func(ctx context.Context) error // Optional, called in registration order
+	OnStop       func(ctx context.Context) error // Optional, called in reverse registration order
+	StartTimeout time.Duration                   // Overrides Option.StartTimeout when > 0
+	StopTimeout  time.Duration                   // Overrides Option.StopTimeout when > 0
+}
+
+// Option manager configuration structure
+type Option struct {
+	Signals      []os.Signal   // Signals triggering shutdown, default SIGINT and SIGTERM
+	StartTimeout time.Duration // Time allowed for each OnStart, default 15s
+	StopTimeout  time.Duration // Time allowed for each OnStop, default 10s
+	Logger       logx.ILogger  // Progress logger, default is the global logx logger
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithSignals replaces the signals that trigger shutdown
+func WithSignals(sig ...os.Signal) OptionFunc {
+	return func(o *Option) { o.Signals = sig }
+}
+
+// WithStartTimeout sets the default time allowed for each OnStart
+func WithStartTimeout(d time.Duration) OptionFunc {
+	return func(o *Option) { o.StartTimeout = d }
+}
+
+// WithStopTimeout sets the default time allowed for each OnStop
+func WithStopTimeout(d time.Duration) OptionFunc {
+	return func(o *Option) { o.StopTimeout = d }
+}
+
+// WithLogger sets the logger used to report lifecycle progress
+func WithLogger(l logx.ILogger) OptionFunc {
+	return func(o *Option) { o.Logger = l }
+}
+
+// Manager starts components in order, waits for a signal, context cancellation or a component
+// failure, and then stops the started components in reverse order
+type Manager struct {
+	opt     Option
+	mu      sync.Mutex
+	hooks   []Hook
+	running bool
+	done    chan struct{} // Closed when shutdown is requested
+	once    sync.Once
+	failErr error
+}
+
+// New creates a Manager
+func New(opts ...OptionFunc) *Manager {
+	m := &Manager{
+		opt: Option{
+			Signals:      []os.Signal{os.Interrupt, syscall.SIGTERM},
+			StartTimeout: 15 * time.Second,
+			StopTimeout:  10 * time.Second,
+		},
+		done: make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(&m.opt)
+	}
+	return m
+}
+
+// Append registers a hook, hooks start in registration order and stop in reverse
+func (m *Manager) Append(h Hook) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.hooks = append(m.hooks, h)
+}
+
+// Add registers a component by its start and stop functions, either may be nil
+func (m *Manager) Add(name string, start, stop func(ctx context.Context) error) {
+	m.Append(Hook{Name: name, OnStart: start, OnStop: stop})
+}
+
+// Go registers a long-running function, such as a consumer loop
+// run gets a context cancelled at shutdown; a non-nil error returned before that triggers shutdown
+func (m *Manager) Go(name string, run func(ctx context.Context) error) {
+	var (
+		cancel context.CancelFunc
+		exited chan struct{}
+	)
+	m.Append(Hook{
+		Name: name,
+		OnStart: func(context.Context) error {
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			exited = make(chan struct{})
+			go func() {
+				defer close(exited)
+				if err := run(ctx); err != nil && ctx.Err() == nil {
+					m.Fail(fmt.Errorf("%s: %w", name, err))
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-exited:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
+
+// HTTPServer registers srv: the listener is opened on start so bind errors abort startup,
+// requests are served in the background and srv.Shutdown drains them on stop
+func (m *Manager) HTTPServer(name string, srv *http.Server) {
+	m.Append(Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			addr := srv.Addr
+			if addr == "" {
+				addr = ":http"
+			}
+			var lc net.ListenConfig
+			ln, err := lc.Listen(ctx, "tcp", addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				var err error
+				if srv.TLSConfig != nil {
+					err = srv.ServeTLS(ln, "", "")
+				} else {
+					err = srv.Serve(ln)
+				}
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					m.Fail(fmt.Errorf("%s: %w", name, err))
+				}
+			}()
+			m.logger().Info("gracefulx: %s listening on %s", name, ln.Addr())
+			return nil
+		},
+		OnStop: srv.Shutdown,
+	})
+}
+
+// Fail requests shutdown because of err, Run returns it; only the first failure is kept
+func (m *Manager) Fail(err error) {
+	m.once.Do(func() {
+		m.failErr = err
+		close(m.done)
+	})
+}
+
+// Shutdown requests a graceful shutdown, Run then stops all components and returns
+func (m *Manager) Shutdown() {
+	m.once.Do(func() { close(m.done) })
+}
+
+// Run starts every component and blocks until a signal, ctx cancellation, Shutdown or Fail,
+// then stops the started components in reverse order. If a component fails to start,
+// the ones already started are stopped and the start error is returned. Stop errors are
+// joined with the failure that caused the shutdown, if any
+func (m *Manager) Run(ctx context.Context) error {
+	m.mu.Lock()
+	if m.running {
+		m.mu.Unlock()
+		return ErrAlreadyRunning
+	}
+	m.running = true
+	hooks := append([]Hook(nil), m.hooks...)
+	m.mu.Unlock()
+
+	sigCh := make(chan os.Signal, 1)
+	if len(m.opt.Signals) > 0 {
+		signal.Notify(sigCh, m.opt.Signals...)
+		defer signal.Stop(sigCh)
+	}
+
+	started, err := m.start(ctx, hooks)
+	if err != nil {
+		return errors.Join(err, m.stop(started))
+	}
+	m.logger().Info("gracefulx: %d components started", len(started))
+
+	select {
+	case sig := <-sigCh:
+		m.logger().Info("gracefulx: received %s, shutting down", sig)
+	case <-ctx.Done():
+		m.logger().Info("gracefulx: context done, shutting down")
+	case <-m.done:
+		if m.failErr != nil {
+			m.logger().Error("gracefulx: %v, shutting down", m.failErr)
+		} else {
+			m.logger().Info("gracefulx: shutdown requested")
+		}
+	}
+	m.Shutdown()
+	return errors.Join(m.failErr, m.stop(started))
+}
+
+// start runs OnStart hooks in order and returns the hooks started successfully
+func (m *Manager) start(ctx context.Context, hooks []Hook) ([]Hook, error) {
+	started := make([]Hook, 0, len(hooks))
+	for _, h := range hooks {
+		if h.OnStart != nil {
+			timeout := h.StartTimeout
+			if timeout <= 0 {
+				timeout = m.opt.StartTimeout
+			}
+			startCtx, cancel := context.WithTimeout(ctx, timeout)
+			err := h.OnStart(startCtx)
+			cancel()
+			if err != nil {
+				m.logger().Error("gracefulx: start %s failed: %v", h.Name, err)
+				return started, fmt.Errorf("gracefulx: start %s: %w", h.Name, err)
+			}
+			m.logger().Debug("gracefulx: started %s", h.Name)
+		}
+		started = append(started, h)
+	}
+	return started, nil
+}
+
+// stop runs OnStop hooks in reverse order, each bounded by its stop timeout
+func (m *Manager) stop(hooks []Hook) error {
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		h := hooks[i]
+		if h.OnStop == nil {
+			continue
+		}
+		timeout := h.StopTimeout
+		if timeout <= 0 {
+			timeout = m.opt.StopTimeout
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		begin := time.Now()
+		err := h.OnStop(ctx)
+		cancel()
+		if err != nil {
+			m.logger().Error("gracefulx: stop %s failed: %v", h.Name, err)
+			errs = append(errs, fmt.Errorf("gracefulx: stop %s: %w", h.Name, err))
+			continue
+		}
+		m.logger().Info("gracefulx: stopped %s in %s", h.Name, time.Since(begin).Round(time.Millisecond))
+	}
+	return errors.Join(errs...)
+}
+
+// printer is the subset of logx.ILogger used by the manager
+type printer interface {
+	Debug(format string, v ...any)
+	Info(format string, v ...any)
+	Error(format string, v ...any)
+}
+
+func (m *Manager) logger() printer {
+	if m.opt.Logger != nil {
+		return m.opt.Logger
+	}
+	return stdLogger{}
+}
+
+// stdLogger forwards to the global logx functions
+type stdLogger struct{}
+
+func (stdLogger) Debug(format string, v ...any) { logx.Debug(format, v...) }
+func (stdLogger) Info(format string, v ...any)  { logx.Info(format, v...) }
+func (stdLogger) Error(format string, v ...any) { logx.Error(format, v...) }
@@ -0,0 +1,56 @@
+package hashx
+
+import (
+	"github.com/cespare/xxhash/v2"
+	"hash/fnv"
+)
+
+// FNV32a returns the 32-bit FNV-1a hash of s
+func FNV32a(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
+
+// FNV64a returns the 64-bit FNV-1a hash of s
+func FNV64a(s string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum64()
+}
+
+// XXHash64 returns the 64-bit xxHash of s, faster than FNV on long keys
+func XXHash64(s string) uint64 {
+	return xxhash.Sum64String(s)
+}
+
+// XXHash64Bytes returns the 64-bit xxHash of b
+func XXHash64Bytes(b []byte) uint64 {
+	return xxhash.Sum64(b)
+}
+
+// Shard maps key to a shard in [0, n) using xxHash, n <= 0 returns 0
+// Adding shards moves most keys, use JumpHash or Ring when resizing must be cheap
+func Shard(key string, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	return int(XXHash64(key) % uint64(n))
+}
+
+// JumpHash maps key to a bucket in [0, buckets) with Google's jump consistent hash:
+// growing from n to n+1 buckets moves only 1/(n+1) of the keys. buckets <= 0 returns 0
+func JumpHash(key uint64, buckets int) int {
+	var b, j int64 = -1, 0
+	for j < int64(buckets) {
+		b = j
+		key = key*2862933555777941757 + 1
+		j = int64(float64(b+1) * (float64(int64(1)<<31) / float64((key>>33)+1)))
+	}
+	return int(max(b, 0))
+}
+
+// JumpShard is JumpHash over the xxHash of a string key
+func JumpShard(key string, buckets int) int {
+	return JumpHash(XXHash64(key), buckets)
+}
@@ -0,0 +1,149 @@
+package hashx
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// HashFunc maps a key to a point on the ring
+type HashFunc func(key string) uint64
+
+// Ring is a thread-safe consistent-hash ring with virtual nodes
+// Each member is placed replicas times (times its weight) on the ring, so adding or
+// removing a member only moves the keys of its own segments
+type Ring struct {
+	mu       sync.RWMutex
+	hash     HashFunc
+	replicas int
+	points   []uint64          // Sorted virtual node positions
+	owners   map[uint64]string // Virtual node position -> member
+	members  map[string]int    // Member -> weight
+}
+
+// NewRing creates a ring placing each member replicas times, default 100; hash defaults to XXHash64
+func NewRing(replicas int, hash HashFunc) *Ring {
+	if replicas <= 0 {
+		replicas = 100
+	}
+	if hash == nil {
+		hash = XXHash64
+	}
+	return &Ring{hash: hash, replicas: replicas, owners: make(map[uint64]string), members: make(map[string]int)}
+}
+
+// Add adds members with weight 1, existing members are left unchanged
+func (r *Ring) Add(members ...string) {
+	for _, m := range members {
+		r.AddWeighted(m, 1)
+	}
+}
+
+// AddWeighted adds member owning weight times the default number of virtual nodes
+// Re-adding a member replaces its weight
+func (r *Ring) AddWeighted(member string, weight int) {
+	if weight <= 0 {
+		weight = 1
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if w, ok := r.members[member]; ok {
+		if w == weight {
+			return
+		}
+		r.remove(member)
+	}
+	r.members[member] = weight
+	for i := 0; i < r.replicas*weight; i++ {
+		p := r.hash(strconv.Itoa(i) + "#" + member)
+		// On the rare collision the first owner keeps the point
+		if _, taken := r.owners[p]; taken {
+			continue
+		}
+		r.owners[p] = member
+		r.points = append(r.points, p)
+	}
+	slices.Sort(r.points)
+}
+
+// Remove removes member from the ring
+func (r *Ring) Remove(member string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(member)
+}
+
+// remove drops the virtual nodes of member, r.mu must be held
+func (r *Ring) remove(member string) {
+	if _, ok := r.members[member]; !ok {
+		return
+	}
+	delete(r.members, member)
+	points := r.points[:0]
+	for _, p := range r.points {
+		if r.owners[p] == member {
+			delete(r.owners, p)
+			continue
+		}
+		points = append(points, p)
+	}
+	r.points = points
+}
+
+// GetNode returns the member owning key, or "" when the ring is empty
+func (r *Ring) GetNode(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return ""
+	}
+	return r.owners[r.points[r.search(key)]]
+}
+
+// GetNodes returns up to n distinct members for key in ring order, e.g. for replicas
+func (r *Ring) GetNodes(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 || n <= 0 {
+		return nil
+	}
+	n = min(n, len(r.members))
+	nodes := make([]string, 0, n)
+	for i, start := 0, r.search(key); i < len(r.points) && len(nodes) < n; i++ {
+		owner := r.owners[r.points[(start+i)%len(r.points)]]
+		if !slices.Contains(nodes, owner) {
+			nodes = append(nodes, owner)
+		}
+	}
+	return nodes
+}
+
+// Members returns the members of the ring, sorted
+func (r *Ring) Members() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	members := make([]string, 0, len(r.members))
+	for m := range r.members {
+		members = append(members, m)
+	}
+	sort.Strings(members)
+	return members
+}
+
+// Len returns the number of members
+func (r *Ring) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return len(r.members)
+}
+
+// search returns the index of the first point at or after the hash of key, wrapping around
+func (r *Ring) search(key string) int {
+	h := r.hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return i
+}
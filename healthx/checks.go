@@ -0,0 +1,37 @@
+package healthx
+
+import (
+	"context"
+	"database/sql"
+	"github.com/chihqiang/gox/clientx"
+	"net"
+)
+
+// TCPCheck reports whether a TCP connection to addr ("host:port") can be established
+func TCPCheck(addr string) CheckFunc {
+	return func(ctx context.Context) error {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+	}
+}
+
+// HTTPCheck reports whether a GET of url answers with a 2xx status, without retries
+func HTTPCheck(url string, opts ...clientx.OptionFunc) CheckFunc {
+	return func(ctx context.Context) error {
+		opts := append([]clientx.OptionFunc{clientx.WithRetries(0)}, opts...)
+		resp, err := clientx.Get(ctx, url, opts...)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+}
+
+// PingCheck reports whether db answers a ping
+func PingCheck(db *sql.DB) CheckFunc {
+	return db.PingContext
+}
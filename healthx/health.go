@@ -0,0 +1,206 @@
+package healthx
+
+import (
+	"context"
+	"fmt"
+	"github.com/chihqiang/gox/httpx"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Status is the health of a check or of the whole registry
+type Status string
+
+const (
+	StatusUp       Status = "up"       // Every check passes
+	StatusDegraded Status = "degraded" // Only non-critical checks fail
+	StatusDown     Status = "down"     // At least one critical check fails
+)
+
+// CheckFunc reports a problem by returning an error, it must honour ctx cancellation
+type CheckFunc func(ctx context.Context) error
+
+// CheckOption check configuration structure
+type CheckOption struct {
+	Timeout  time.Duration // Maximum run time of the check, default 5s
+	Critical bool          // A failing critical check marks the registry down, default true
+	CacheTTL time.Duration // Reuse the last result for this long, 0 runs the check every time
+}
+
+// CheckOptionFunc functional configuration type
+type CheckOptionFunc func(*CheckOption)
+
+// WithTimeout sets the maximum run time of the check
+func WithTimeout(d time.Duration) CheckOptionFunc {
+	return func(o *CheckOption) { o.Timeout = d }
+}
+
+// WithNonCritical makes failures of the check degrade rather than take down the overall status
+func WithNonCritical() CheckOptionFunc {
+	return func(o *CheckOption) { o.Critical = false }
+}
+
+// WithCacheTTL caches the result of expensive checks for ttl
+func WithCacheTTL(ttl time.Duration) CheckOptionFunc {
+	return func(o *CheckOption) { o.CacheTTL = ttl }
+}
+
+// Result is the outcome of one check
+type Result struct {
+	Status    Status        `json:"status"`
+	Error     string        `json:"error,omitempty"`
+	Critical  bool          `json:"critical"`
+	Duration  time.Duration `json:"duration"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+// Report is the aggregated outcome of all checks
+type Report struct {
+	Status    Status            `json:"status"`
+	Checks    map[string]Result `json:"checks"`
+	CheckedAt time.Time         `json:"checked_at"`
+}
+
+// check is a registered check with its cached result
+type check struct {
+	name string
+	fn   CheckFunc
+	opt  CheckOption
+
+	mu   sync.Mutex // Serializes runs so concurrent probes share the cache
+	last *Result
+}
+
+// Registry holds named health checks
+type Registry struct {
+	mu     sync.RWMutex
+	checks map[string]*check
+}
+
+// New creates an empty registry
+func New() *Registry {
+	return &Registry{checks: make(map[string]*check)}
+}
+
+// Register adds or replaces the check called name
+func (r *Registry) Register(name string, fn CheckFunc, opts ...CheckOptionFunc) {
+	o := CheckOption{Timeout: 5 * time.Second, Critical: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks[name] = &check{name: name, fn: fn, opt: o}
+}
+
+// Unregister removes the check called name
+func (r *Registry) Unregister(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.checks, name)
+}
+
+// Names returns the registered check names, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.checks))
+	for name := range r.checks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check runs every check concurrently and aggregates the results
+// An empty registry is up
+func (r *Registry) Check(ctx context.Context) Report {
+	r.mu.RLock()
+	checks := make([]*check, 0, len(r.checks))
+	for _, c := range r.checks {
+		checks = append(checks, c)
+	}
+	r.mu.RUnlock()
+
+	report := Report{Status: StatusUp, Checks: make(map[string]Result, len(checks)), CheckedAt: time.Now()}
+	results := make([]Result, len(checks))
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	for i, c := range checks {
+		res := results[i]
+		report.Checks[c.name] = res
+		if res.Status == StatusUp {
+			continue
+		}
+		if c.opt.Critical {
+			report.Status = StatusDown
+		} else if report.Status == StatusUp {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// Handler serves the report as JSON: 200 when up or degraded, 503 when down
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		report := r.Check(req.Context())
+		status := http.StatusOK
+		if report.Status == StatusDown {
+			status = http.StatusServiceUnavailable
+		}
+		w.Header().Set("Cache-Control", "no-store")
+		_ = httpx.JSON(w, status, report)
+	})
+}
+
+// run executes the check, or returns the cached result while it is fresh
+func (c *check) run(ctx context.Context) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.last != nil && c.opt.CacheTTL > 0 && time.Since(c.last.CheckedAt) < c.opt.CacheTTL {
+		return *c.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, c.opt.Timeout)
+	defer cancel()
+	begin := time.Now()
+	err := safeRun(ctx, c.fn)
+	res := Result{Status: StatusUp, Critical: c.opt.Critical, Duration: time.Since(begin), CheckedAt: begin}
+	if err != nil {
+		res.Status = StatusDown
+		res.Error = err.Error()
+	}
+	c.last = &res
+	return res
+}
+
+// safeRun runs fn and stops waiting for it once ctx is done, so a check ignoring ctx
+// cannot stall the report; a panic is reported as an error
+func safeRun(ctx context.Context, fn CheckFunc) error {
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("healthx: check panicked: %v", r)
+			}
+		}()
+		done <- fn(ctx)
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return fmt.Errorf("healthx: check timed out: %w", ctx.Err())
+	}
+}
@@ -0,0 +1,16 @@
+package httpx
+
+import (
+	"mime"
+	"net/http"
+)
+
+// Attachment sets the headers of a file download named filename, write the body afterwards
+// e.g. httpx.Attachment(w, "logs.zip", "application/zip"); zipx.WriteZip(w, dir)
+func Attachment(w http.ResponseWriter, filename, contentType string) {
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+}
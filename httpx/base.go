@@ -2,6 +2,7 @@ package httpx
 
 import (
 	"encoding/xml"
+	"errors"
 	"net/http"
 )
 
@@ -45,6 +46,12 @@ func wrapBaseResponse[T any](v T) BaseResponse[T] {
 		resp.Code = data.Code
 		resp.Msg = data.Msg
 	case error:
+		var coder Coder
+		if errors.As(data, &coder) && coder.Code() != 0 {
+			resp.Code = coder.Code()
+			resp.Msg = coder.Message()
+			break
+		}
 		resp.Code = BusinessCodeError
 		resp.Msg = data.Error()
 	default:
@@ -1,6 +1,10 @@
 package httpx
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
 
 // NewCodeMsg constructor
 func NewCodeMsg(code int, msg string) *CodeMsg {
@@ -20,3 +24,32 @@ type CodeMsg struct {
 func (e *CodeMsg) Error() string {
 	return fmt.Sprintf("code=%d, msg=%s", e.Code, e.Msg)
 }
+
+// Coder is implemented by errors carrying a business code, such as *errorx.Error
+// Responses built from a Coder use its code and message instead of BusinessCodeError
+type Coder interface {
+	error
+	Code() int
+	Message() string
+}
+
+// StatusCoder is implemented by errors carrying an HTTP status, such as *errorx.Error
+type StatusCoder interface {
+	error
+	HTTPStatus() int
+}
+
+// ErrorStatus returns the HTTP status for err, taken from a StatusCoder in its chain
+// or http.StatusInternalServerError when none is set
+func ErrorStatus(err error) int {
+	var sc StatusCoder
+	if errors.As(err, &sc) && sc.HTTPStatus() != 0 {
+		return sc.HTTPStatus()
+	}
+	return http.StatusInternalServerError
+}
+
+// ErrorResponse writes err as a JSON BaseResponse with the status from ErrorStatus
+func ErrorResponse(w http.ResponseWriter, err error) error {
+	return JSON(w, ErrorStatus(err), wrapBaseResponse[error](err))
+}
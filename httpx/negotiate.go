@@ -0,0 +1,79 @@
+package httpx
+
+import (
+	"fmt"
+	"github.com/chihqiang/gox/encodingx"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Render writes v with the codec that best matches the request Accept header,
+// falling back to JSON when nothing registered in encodingx matches
+func Render(w http.ResponseWriter, r *http.Request, status int, v any) error {
+	codec := negotiate(r.Header.Get("Accept"))
+	b, err := codec.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s response: %w", codec.ContentType(), err)
+	}
+	w.Header().Set("Content-Type", codec.ContentType())
+	w.WriteHeader(status)
+	_, err = w.Write(b)
+	return err
+}
+
+// DefaultBindLimit is the largest request body read by Bind
+const DefaultBindLimit = 10 << 20
+
+// Bind decodes the request body into v according to its Content-Type, JSON when absent
+// Bodies larger than DefaultBindLimit fail with *http.MaxBytesError
+func Bind(r *http.Request, v any) error {
+	return BindLimit(r, v, DefaultBindLimit)
+}
+
+// BindLimit is Bind reading at most limit bytes of the body, limit <= 0 means unlimited
+func BindLimit(r *http.Request, v any, limit int64) error {
+	ct := r.Header.Get("Content-Type")
+	if ct == "" {
+		ct = encodingx.JSON
+	}
+	body := r.Body
+	if limit > 0 {
+		body = http.MaxBytesReader(nil, body, limit)
+	}
+	return encodingx.Decode(body, ct, v)
+}
+
+// negotiate picks the registered codec with the highest Accept quality
+func negotiate(accept string) encodingx.Codec {
+	type candidate struct {
+		mediaType string
+		q         float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{mt, q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+	for _, c := range candidates {
+		if codec, ok := encodingx.Lookup(c.mediaType); ok {
+			return codec
+		}
+	}
+	codec, _ := encodingx.Lookup(encodingx.JSON)
+	return codec
+}
@@ -0,0 +1,18 @@
+package httpx
+
+import "net/http"
+
+// Page is the data of a paginated response
+// meta is typically paginationx.Meta or paginationx.CursorMeta
+type Page[T any, M any] struct {
+	Items []T `json:"items" xml:"items"`
+	Meta  M   `json:"meta" xml:"meta"`
+}
+
+// PageResponse writes items and their pagination metadata into w with http.StatusOK.
+func PageResponse[T any, M any](w http.ResponseWriter, items []T, meta M) error {
+	if items == nil {
+		items = []T{}
+	}
+	return JsonResponse(w, Page[T, M]{Items: items, Meta: meta})
+}
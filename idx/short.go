@@ -0,0 +1,65 @@
+package idx
+
+import (
+	"crypto/rand"
+	"math/big"
+	"strings"
+)
+
+// ShortCode returns a random human-friendly code of n characters from Crockford's base32
+// alphabet (no I, L, O or U), suitable for invite codes and order references read aloud
+func ShortCode(n int) string {
+	max := big.NewInt(int64(len(crockford)))
+	var sb strings.Builder
+	sb.Grow(n)
+	for i := 0; i < n; i++ {
+		d, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			panic("idx: crypto/rand unavailable: " + err.Error())
+		}
+		sb.WriteByte(crockford[d.Int64()])
+	}
+	return sb.String()
+}
+
+// FormatShort encodes a non-negative ID such as a Snowflake ID in Crockford base32,
+// shortening a 19-digit number to at most 13 characters
+func FormatShort(id int64) string {
+	if id <= 0 {
+		return "0"
+	}
+	var buf [13]byte
+	i := len(buf)
+	for n := uint64(id); n > 0; n >>= 5 {
+		i--
+		buf[i] = crockford[n&31]
+	}
+	return string(buf[i:])
+}
+
+// ParseShort decodes a code produced by FormatShort; it is case-insensitive and, like
+// Crockford's spec, reads I and L as 1 and O as 0
+func ParseShort(s string) (int64, bool) {
+	if s == "" || len(s) > 13 {
+		return 0, false
+	}
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		c := upper(s[i])
+		switch c {
+		case 'I', 'L':
+			c = '1'
+		case 'O':
+			c = '0'
+		}
+		d := strings.IndexByte(crockford, c)
+		if d < 0 {
+			return 0, false
+		}
+		if n > (1<<63-1)>>5 {
+			return 0, false // Another digit would exceed the int64 range
+		}
+		n = n<<5 | uint64(d)
+	}
+	return int64(n), true
+}
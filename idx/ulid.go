@@ -0,0 +1,94 @@
+package idx
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// crockford is Crockford's base32 alphabet, it omits I, L, O and U to avoid confusion
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NewULID returns a 26-character ULID: 48-bit millisecond timestamp followed by 80 random bits,
+// Crockford base32 encoded so that IDs sort lexicographically by creation time
+func NewULID() string {
+	return ulidAt(time.Now())
+}
+
+func ulidAt(t time.Time) string {
+	var b [16]byte
+	ms := uint64(t.UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(b[2:], uint32(ms))
+	mustRead(b[6:])
+
+	// 128 bits -> 26 base32 characters, the first character carries only 3 bits
+	var out [26]byte
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// ULIDTime extracts the timestamp of a ULID
+func ULIDTime(id string) (time.Time, error) {
+	if len(id) != 26 {
+		return time.Time{}, fmt.Errorf("idx: invalid ULID length %d", len(id))
+	}
+	var ms uint64
+	// The first 10 characters hold the 48-bit timestamp (3 bits + 9 x 5 bits)
+	for i := 0; i < 10; i++ {
+		d := strings.IndexByte(crockford, upper(id[i]))
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("idx: invalid ULID character %q", id[i])
+		}
+		ms = ms<<5 | uint64(d)
+	}
+	return time.UnixMilli(int64(ms)), nil
+}
+
+// NewUUIDv7 returns an RFC 9562 version 7 UUID: a 48-bit millisecond timestamp followed by
+// random bits, formatted as xxxxxxxx-xxxx-7xxx-yxxx-xxxxxxxxxxxx. IDs sort by creation time
+func NewUUIDv7() string {
+	var b [16]byte
+	ms := uint64(time.Now().UnixMilli())
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	binary.BigEndian.PutUint32(b[2:], uint32(ms))
+	mustRead(b[6:])
+	b[6] = b[6]&0x0f | 0x70 // Version 7
+	b[8] = b[8]&0x3f | 0x80 // RFC 9562 variant
+
+	var out [36]byte
+	hex.Encode(out[0:8], b[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], b[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], b[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], b[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], b[10:])
+	return string(out[:])
+}
+
+func mustRead(b []byte) {
+	if _, err := rand.Read(b); err != nil {
+		panic("idx: crypto/rand unavailable: " + err.Error())
+	}
+}
+
+func upper(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
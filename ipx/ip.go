@@ -0,0 +1,145 @@
+package ipx
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"net/netip"
+	"strings"
+)
+
+// ErrNotIPv4 is returned by IPv4-only helpers for other addresses
+var ErrNotIPv4 = errors.New("ipx: not an IPv4 address")
+
+// ParsePrefix parses a CIDR such as "10.0.0.0/8", a bare IP is treated as a single-address prefix
+// The returned prefix is masked, so "10.1.2.3/8" becomes "10.0.0.0/8"
+func ParsePrefix(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if p.Addr().Is4In6() && p.Bits() >= 96 {
+		p = netip.PrefixFrom(p.Addr().Unmap(), p.Bits()-96)
+	}
+	return p.Masked(), nil
+}
+
+// Contains reports whether ip belongs to cidr
+func Contains(cidr, ip string) (bool, error) {
+	p, err := ParsePrefix(cidr)
+	if err != nil {
+		return false, err
+	}
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return false, err
+	}
+	return p.Contains(addr.Unmap()), nil
+}
+
+// IPToUint32 converts an IPv4 address to its big-endian integer form
+func IPToUint32(ip string) (uint32, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return 0, err
+	}
+	addr = addr.Unmap()
+	if !addr.Is4() {
+		return 0, ErrNotIPv4
+	}
+	b := addr.As4()
+	return binary.BigEndian.Uint32(b[:]), nil
+}
+
+// Uint32ToIP converts a big-endian integer to an IPv4 address string
+func Uint32ToIP(n uint32) string {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], n)
+	return netip.AddrFrom4(b).String()
+}
+
+// Range iterates over every address from start to end inclusive
+// Nothing is yielded when start is after end or the families differ
+func Range(start, end netip.Addr) iter.Seq[netip.Addr] {
+	return func(yield func(netip.Addr) bool) {
+		if !start.IsValid() || start.BitLen() != end.BitLen() || start.Compare(end) > 0 {
+			return
+		}
+		for a := start; ; a = a.Next() {
+			if !yield(a) || a == end {
+				return
+			}
+		}
+	}
+}
+
+// Addrs iterates over every address of cidr, including network and broadcast addresses
+func Addrs(cidr string) (iter.Seq[netip.Addr], error) {
+	p, err := ParsePrefix(cidr)
+	if err != nil {
+		return nil, err
+	}
+	return Range(p.Addr(), LastAddr(p)), nil
+}
+
+// Expand returns every address of cidr as strings, refusing ranges larger than limit
+func Expand(cidr string, limit int) ([]string, error) {
+	p, err := ParsePrefix(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if hostBits := p.Addr().BitLen() - p.Bits(); hostBits >= 63 || 1<<hostBits > limit {
+		return nil, fmt.Errorf("ipx: %s has more than %d addresses", p, limit)
+	}
+	var out []string
+	for a := range Range(p.Addr(), LastAddr(p)) {
+		out = append(out, a.String())
+	}
+	return out, nil
+}
+
+// LastAddr returns the last address of p, the broadcast address for IPv4 networks
+func LastAddr(p netip.Prefix) netip.Addr {
+	p = p.Masked()
+	b := p.Addr().AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	addr, _ := netip.AddrFromSlice(b)
+	return addr
+}
+
+// Split divides cidr into consecutive subnets of prefix length bits
+//
+//	Split("10.0.0.0/24", 26) // 10.0.0.0/26, 10.0.0.64/26, 10.0.0.128/26, 10.0.0.192/26
+func Split(cidr string, bits int) ([]netip.Prefix, error) {
+	p, err := ParsePrefix(cidr)
+	if err != nil {
+		return nil, err
+	}
+	if bits < p.Bits() || bits > p.Addr().BitLen() {
+		return nil, fmt.Errorf("ipx: cannot split %s into /%d subnets", p, bits)
+	}
+	if bits-p.Bits() > 16 {
+		return nil, fmt.Errorf("ipx: splitting %s into /%d yields too many subnets", p, bits)
+	}
+	n := 1 << (bits - p.Bits())
+	out := make([]netip.Prefix, 0, n)
+	addr := p.Addr()
+	for i := 0; i < n; i++ {
+		sub := netip.PrefixFrom(addr, bits)
+		out = append(out, sub)
+		addr = LastAddr(sub).Next()
+	}
+	return out, nil
+}
@@ -0,0 +1,101 @@
+package ipx
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// Matcher matches addresses against a set of CIDRs, e.g. trusted reverse proxies
+type Matcher struct {
+	prefixes []netip.Prefix
+}
+
+// NewMatcher parses CIDRs or bare IPs into a Matcher
+func NewMatcher(cidrs ...string) (*Matcher, error) {
+	m := &Matcher{prefixes: make([]netip.Prefix, 0, len(cidrs))}
+	for _, c := range cidrs {
+		p, err := ParsePrefix(c)
+		if err != nil {
+			return nil, err
+		}
+		m.prefixes = append(m.prefixes, p)
+	}
+	return m, nil
+}
+
+// MustMatcher is like NewMatcher but panics on invalid input
+func MustMatcher(cidrs ...string) *Matcher {
+	m, err := NewMatcher(cidrs...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// PrivateNetworks is a Matcher for loopback and private ranges, a common trusted-proxy default
+var PrivateNetworks = MustMatcher(
+	"127.0.0.0/8", "10.0.0.0/8", "172.16.0.0/12", "192.168.0.0/16",
+	"::1/128", "fc00::/7",
+)
+
+// Contains reports whether ip (optionally with a port) belongs to one of the CIDRs
+func (m *Matcher) Contains(ip string) bool {
+	addr, ok := parseHost(ip)
+	if !ok || m == nil {
+		return false
+	}
+	for _, p := range m.prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the client address of a request given its remote address and the
+// X-Forwarded-For header values. Forwarded addresses are only honoured while the hop
+// that added them is trusted: the chain is walked from the right and the first address
+// not in m is returned. With a nil or empty Matcher the remote address is returned
+func (m *Matcher) ClientIP(remoteAddr string, forwardedFor ...string) string {
+	remote, ok := parseHost(remoteAddr)
+	if !ok {
+		return ""
+	}
+	if !m.Contains(remote.String()) {
+		return remote.String()
+	}
+	var hops []string
+	for _, header := range forwardedFor {
+		for _, h := range strings.Split(header, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				hops = append(hops, h)
+			}
+		}
+	}
+	client := remote
+	for i := len(hops) - 1; i >= 0; i-- {
+		addr, ok := parseHost(hops[i])
+		if !ok {
+			break
+		}
+		client = addr
+		if !m.Contains(addr.String()) {
+			break
+		}
+	}
+	return client.String()
+}
+
+// parseHost parses an address with or without port
+func parseHost(s string) (netip.Addr, bool) {
+	s = strings.TrimSpace(s)
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+	addr, err := netip.ParseAddr(strings.Trim(s, "[]"))
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return addr.Unmap(), true
+}
@@ -0,0 +1,131 @@
+package jsonx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Marshal encodes v without HTML escaping and without a trailing newline
+// Object keys of maps are always sorted, so equal values produce identical bytes
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(buf.Bytes(), "\n"), nil
+}
+
+// MarshalIndent is like Marshal but indents the output with two spaces
+func MarshalIndent(v any) ([]byte, error) {
+	b, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return Pretty(b)
+}
+
+// Unmarshal decodes data into v, numbers decoded into interface values become json.Number
+// instead of float64 so integers beyond 2^53 are not rounded
+func Unmarshal(data []byte, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+	if dec.More() {
+		return fmt.Errorf("jsonx: unexpected data after top-level value")
+	}
+	return nil
+}
+
+// Canonical re-encodes a JSON document with sorted object keys and no insignificant whitespace
+// Number literals are kept verbatim, which makes the output suitable for hashing or signing
+func Canonical(data []byte) ([]byte, error) {
+	var doc any
+	if err := Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return Marshal(doc)
+}
+
+// Pretty indents a JSON document with two spaces
+func Pretty(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, data, "", "  "); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Minify removes insignificant whitespace from a JSON document
+func Minify(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Valid reports whether data is a valid JSON document
+func Valid(data []byte) bool {
+	return json.Valid(data)
+}
+
+// Int is an int64 that also accepts quoted numbers ("42"), empty strings and null when decoding
+type Int int64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *Int) UnmarshalJSON(b []byte) error {
+	s, err := numberText(b)
+	if err != nil || s == "" {
+		return err
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		*n = Int(i)
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || f != float64(int64(f)) {
+		return fmt.Errorf("jsonx: cannot decode %s into Int", b)
+	}
+	*n = Int(f)
+	return nil
+}
+
+// Float is a float64 that also accepts quoted numbers ("1.5"), empty strings and null when decoding
+type Float float64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *Float) UnmarshalJSON(b []byte) error {
+	s, err := numberText(b)
+	if err != nil || s == "" {
+		return err
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("jsonx: cannot decode %s into Float", b)
+	}
+	*n = Float(f)
+	return nil
+}
+
+// numberText strips quotes from a JSON number or numeric string, null yields ""
+func numberText(b []byte) (string, error) {
+	s := strings.TrimSpace(string(b))
+	if s == "null" {
+		return "", nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		var str string
+		if err := json.Unmarshal(b, &str); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(str), nil
+	}
+	return s, nil
+}
@@ -0,0 +1,39 @@
+package jsonx
+
+// Merge deep merges JSON objects from left to right and returns the encoded result
+// Nested objects are merged recursively; any other value, including arrays, is replaced
+// by the later document. A non-object document replaces the result entirely
+func Merge(docs ...[]byte) ([]byte, error) {
+	var result any
+	for _, data := range docs {
+		var doc any
+		if err := Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+		result = mergeValue(result, doc)
+	}
+	return Marshal(result)
+}
+
+// MergeMaps deep merges src into dst in place and returns dst, see Merge for the rules
+func MergeMaps(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+	for k, v := range src {
+		dst[k] = mergeValue(dst[k], v)
+	}
+	return dst
+}
+
+func mergeValue(dst, src any) any {
+	srcMap, ok := src.(map[string]any)
+	if !ok {
+		return src
+	}
+	dstMap, ok := dst.(map[string]any)
+	if !ok {
+		dstMap = nil
+	}
+	return MergeMaps(dstMap, srcMap)
+}
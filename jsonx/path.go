@@ -0,0 +1,194 @@
+package jsonx
+
+import (
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/convx"
+	"strconv"
+	"strings"
+)
+
+// ErrNotFound is returned when a path does not exist in the document
+var ErrNotFound = errors.New("jsonx: path not found")
+
+// segment is one step of a path, either an object key or an array index
+type segment struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+func (s segment) String() string {
+	if s.isIdx {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	return s.key
+}
+
+// parsePath splits "a.b[0].c" into segments, an empty path addresses the root
+func parsePath(path string) ([]segment, error) {
+	var segs []segment
+	for i := 0; i < len(path); {
+		switch path[i] {
+		case '.':
+			i++
+		case '[':
+			end := strings.IndexByte(path[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("jsonx: unclosed '[' in path %q", path)
+			}
+			n, err := strconv.Atoi(path[i+1 : i+end])
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("jsonx: invalid index %q in path %q", path[i+1:i+end], path)
+			}
+			segs = append(segs, segment{index: n, isIdx: true})
+			i += end + 1
+		default:
+			end := strings.IndexAny(path[i:], ".[")
+			if end < 0 {
+				end = len(path) - i
+			}
+			segs = append(segs, segment{key: path[i : i+end]})
+			i += end
+		}
+	}
+	return segs, nil
+}
+
+// Get decodes data and returns the value at path, e.g. Get(data, "items[0].name")
+// Numbers are returned as json.Number so large integers keep their precision
+func Get(data []byte, path string) (any, error) {
+	var doc any
+	if err := Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	return GetValue(doc, path)
+}
+
+// GetValue returns the value at path inside an already decoded document
+func GetValue(doc any, path string) (any, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	cur := doc
+	for _, seg := range segs {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[seg.key]
+			if seg.isIdx || !ok {
+				return nil, fmt.Errorf("%w: %q at %s", ErrNotFound, path, seg)
+			}
+			cur = v
+		case []any:
+			if !seg.isIdx || seg.index >= len(node) {
+				return nil, fmt.Errorf("%w: %q at %s", ErrNotFound, path, seg)
+			}
+			cur = node[seg.index]
+		default:
+			return nil, fmt.Errorf("%w: %q at %s", ErrNotFound, path, seg)
+		}
+	}
+	return cur, nil
+}
+
+// GetString returns the value at path as a string, numbers and booleans are formatted
+func GetString(data []byte, path string) (string, error) {
+	v, err := Get(data, path)
+	if err != nil {
+		return "", err
+	}
+	return convx.ToStringE(v)
+}
+
+// GetInt64 returns the value at path as int64, numeric strings such as "42" are accepted
+func GetInt64(data []byte, path string) (int64, error) {
+	v, err := Get(data, path)
+	if err != nil {
+		return 0, err
+	}
+	return convx.ToInt64E(v)
+}
+
+// GetFloat returns the value at path as float64, numeric strings are accepted
+func GetFloat(data []byte, path string) (float64, error) {
+	v, err := Get(data, path)
+	if err != nil {
+		return 0, err
+	}
+	return convx.ToFloatE(v)
+}
+
+// GetBool returns the value at path as bool, strings such as "true" or "1" are accepted
+func GetBool(data []byte, path string) (bool, error) {
+	v, err := Get(data, path)
+	if err != nil {
+		return false, err
+	}
+	return convx.ToBoolE(v)
+}
+
+// Set returns a copy of data with value stored at path
+// Missing objects and arrays along the path are created; an index equal to the array
+// length appends, larger indexes fail so a path cannot grow an array arbitrarily
+func Set(data []byte, path string, value any) ([]byte, error) {
+	var doc any
+	if len(strings.TrimSpace(string(data))) > 0 {
+		if err := Unmarshal(data, &doc); err != nil {
+			return nil, err
+		}
+	}
+	doc, err := SetValue(doc, path, value)
+	if err != nil {
+		return nil, err
+	}
+	return Marshal(doc)
+}
+
+// SetValue stores value at path inside a decoded document and returns the updated root
+// Maps are modified in place; the root changes only when it had to be created or was an array that grew
+func SetValue(doc any, path string, value any) (any, error) {
+	segs, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return setAt(doc, segs, value, path)
+}
+
+func setAt(node any, segs []segment, value any, path string) (any, error) {
+	if len(segs) == 0 {
+		return value, nil
+	}
+	seg := segs[0]
+	if seg.isIdx {
+		arr, ok := node.([]any)
+		if node != nil && !ok {
+			return nil, fmt.Errorf("jsonx: cannot index non-array at %s in path %q", seg, path)
+		}
+		if seg.index > len(arr) {
+			return nil, fmt.Errorf("jsonx: index %s out of range for array of length %d in path %q", seg, len(arr), path)
+		}
+		if seg.index == len(arr) {
+			arr = append(arr, nil)
+		}
+		child, err := setAt(arr[seg.index], segs[1:], value, path)
+		if err != nil {
+			return nil, err
+		}
+		arr[seg.index] = child
+		return arr, nil
+	}
+	obj, ok := node.(map[string]any)
+	if node != nil && !ok {
+		return nil, fmt.Errorf("jsonx: cannot set key %q on non-object in path %q", seg.key, path)
+	}
+	if obj == nil {
+		obj = make(map[string]any)
+	}
+	child, err := setAt(obj[seg.key], segs[1:], value, path)
+	if err != nil {
+		return nil, err
+	}
+	obj[seg.key] = child
+	return obj, nil
+}
@@ -0,0 +1,124 @@
+package jwtx
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Claims is implemented by claim types accepted by Parse
+// Embedding RegisteredClaims in a struct is enough to satisfy it
+type Claims interface {
+	Registered() RegisteredClaims
+}
+
+// RegisteredClaims holds the registered claim names defined by RFC 7519
+// Time values are seconds since the Unix epoch, zero means absent
+type RegisteredClaims struct {
+	Issuer    string   `json:"iss,omitempty"` // Issuer
+	Subject   string   `json:"sub,omitempty"` // Subject
+	Audience  Audience `json:"aud,omitempty"` // Audience
+	ExpiresAt int64    `json:"exp,omitempty"` // Expiration time
+	NotBefore int64    `json:"nbf,omitempty"` // Not valid before
+	IssuedAt  int64    `json:"iat,omitempty"` // Issued at
+	ID        string   `json:"jti,omitempty"` // Unique token ID
+}
+
+// Registered implements Claims
+func (c RegisteredClaims) Registered() RegisteredClaims {
+	return c
+}
+
+// NewRegisteredClaims returns claims issued now and expiring after ttl
+func NewRegisteredClaims(subject string, ttl time.Duration) RegisteredClaims {
+	now := time.Now()
+	return RegisteredClaims{
+		Subject:   subject,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(ttl).Unix(),
+	}
+}
+
+// Audience is the "aud" claim, which may be a single string or an array of strings on the wire
+type Audience []string
+
+// MarshalJSON encodes a single audience as a plain string, as most issuers do
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON accepts both the string and the array form
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multi []string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return err
+	}
+	*a = multi
+	return nil
+}
+
+// Contains reports whether aud is one of the audiences
+func (a Audience) Contains(aud string) bool {
+	for _, v := range a {
+		if v == aud {
+			return true
+		}
+	}
+	return false
+}
+
+// MapClaims is a free-form claim set for tokens without a fixed schema
+type MapClaims map[string]any
+
+// Registered implements Claims by reading the registered names from the map
+func (m MapClaims) Registered() RegisteredClaims {
+	rc := RegisteredClaims{
+		Issuer:    m.string("iss"),
+		Subject:   m.string("sub"),
+		ExpiresAt: m.int64("exp"),
+		NotBefore: m.int64("nbf"),
+		IssuedAt:  m.int64("iat"),
+		ID:        m.string("jti"),
+	}
+	switch aud := m["aud"].(type) {
+	case string:
+		rc.Audience = Audience{aud}
+	case []string:
+		rc.Audience = aud
+	case []any:
+		for _, v := range aud {
+			if s, ok := v.(string); ok {
+				rc.Audience = append(rc.Audience, s)
+			}
+		}
+	}
+	return rc
+}
+
+func (m MapClaims) string(key string) string {
+	s, _ := m[key].(string)
+	return s
+}
+
+func (m MapClaims) int64(key string) int64 {
+	switch v := m[key].(type) {
+	case float64:
+		return int64(v)
+	case int64:
+		return v
+	case int:
+		return int64(v)
+	case json.Number:
+		f, _ := v.Float64()
+		return int64(f)
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,354 @@
+package jwtx
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Supported signing algorithms
+const (
+	HS256 = "HS256" // HMAC using SHA-256, key is []byte
+	RS256 = "RS256" // RSASSA-PKCS1-v1_5 using SHA-256, key is *rsa.PrivateKey / *rsa.PublicKey
+	ES256 = "ES256" // ECDSA using P-256 and SHA-256, key is a P-256 *ecdsa.PrivateKey / *ecdsa.PublicKey
+)
+
+var (
+	ErrTokenMalformed    = errors.New("jwtx: token is malformed")
+	ErrUnsupportedAlg    = errors.New("jwtx: unsupported or disallowed signing algorithm")
+	ErrInvalidKey        = errors.New("jwtx: key type does not match signing algorithm")
+	ErrInvalidSignature  = errors.New("jwtx: signature is invalid")
+	ErrTokenExpired      = errors.New("jwtx: token is expired")
+	ErrTokenNotValidYet  = errors.New("jwtx: token is not valid yet")
+	ErrInvalidAudience   = errors.New("jwtx: token has invalid audience")
+	ErrInvalidIssuer     = errors.New("jwtx: token has invalid issuer")
+	ErrMissingExpiration = errors.New("jwtx: token has no expiration")
+)
+
+// Header is the JOSE header of a token
+type Header struct {
+	Alg string `json:"alg"`
+	Typ string `json:"typ,omitempty"`
+	Kid string `json:"kid,omitempty"`
+}
+
+// KeyFunc returns the verification key for a token, typically selected by header.Kid
+type KeyFunc func(header Header) (any, error)
+
+// Option signing and parsing configuration structure
+type Option struct {
+	Method     string           // Signing algorithm, inferred from the key type when empty
+	KeyID      string           // "kid" header written by Sign
+	Methods    []string         // Algorithms accepted by Parse, defaults to all supported
+	Audience   string           // Required "aud" value checked by Parse
+	Issuer     string           // Required "iss" value checked by Parse
+	Leeway     time.Duration    // Clock skew tolerated for exp/nbf checks
+	RequireExp bool             // Reject tokens without "exp"
+	Now        func() time.Time // Clock used for validation, defaults to time.Now
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithMethod sets the signing algorithm used by Sign
+func WithMethod(alg string) OptionFunc {
+	return func(o *Option) { o.Method = alg }
+}
+
+// WithKeyID sets the "kid" header used for key rotation
+func WithKeyID(kid string) OptionFunc {
+	return func(o *Option) { o.KeyID = kid }
+}
+
+// WithMethods restricts the algorithms accepted by Parse
+func WithMethods(algs ...string) OptionFunc {
+	return func(o *Option) { o.Methods = algs }
+}
+
+// WithAudience requires the token audience to contain aud
+func WithAudience(aud string) OptionFunc {
+	return func(o *Option) { o.Audience = aud }
+}
+
+// WithIssuer requires the token issuer to equal iss
+func WithIssuer(iss string) OptionFunc {
+	return func(o *Option) { o.Issuer = iss }
+}
+
+// WithLeeway tolerates clock skew between issuer and verifier
+func WithLeeway(d time.Duration) OptionFunc {
+	return func(o *Option) { o.Leeway = d }
+}
+
+// WithRequireExp rejects tokens that carry no expiration time
+func WithRequireExp() OptionFunc {
+	return func(o *Option) { o.RequireExp = true }
+}
+
+// WithNow overrides the validation clock, mainly for tests
+func WithNow(now func() time.Time) OptionFunc {
+	return func(o *Option) { o.Now = now }
+}
+
+// StaticKey returns a KeyFunc that always yields key
+func StaticKey(key any) KeyFunc {
+	return func(Header) (any, error) { return key, nil }
+}
+
+// KeySet returns a KeyFunc that looks the key up by "kid", for key rotation
+func KeySet(keys map[string]any) KeyFunc {
+	return func(h Header) (any, error) {
+		key, ok := keys[h.Kid]
+		if !ok {
+			return nil, fmt.Errorf("jwtx: unknown key id %q", h.Kid)
+		}
+		return key, nil
+	}
+}
+
+func newOption(opts []OptionFunc) *Option {
+	o := &Option{
+		Methods: []string{HS256, RS256, ES256},
+		Now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Sign serializes claims and signs them with key, returning the compact token
+// claims may be any JSON-marshalable value, usually a struct embedding RegisteredClaims
+func Sign(claims any, key any, opts ...OptionFunc) (string, error) {
+	o := newOption(opts)
+	alg := o.Method
+	if alg == "" {
+		alg = methodForKey(key)
+		if alg == "" {
+			return "", ErrInvalidKey
+		}
+	}
+
+	header, err := json.Marshal(Header{Alg: alg, Typ: "JWT", Kid: o.KeyID})
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("jwtx: marshal claims failed: %w", err)
+	}
+	signingInput := encodeSegment(header) + "." + encodeSegment(payload)
+	sig, err := sign(alg, key, []byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encodeSegment(sig), nil
+}
+
+// Parse verifies the token signature with the key returned by keyFunc, decodes the payload
+// into T and validates exp/nbf and the configured audience/issuer
+func Parse[T Claims](token string, keyFunc KeyFunc, opts ...OptionFunc) (T, error) {
+	var claims T
+	o := newOption(opts)
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return claims, ErrTokenMalformed
+	}
+	headerJSON, err := decodeSegment(parts[0])
+	if err != nil {
+		return claims, ErrTokenMalformed
+	}
+	var header Header
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		return claims, ErrTokenMalformed
+	}
+	if !contains(o.Methods, header.Alg) {
+		return claims, ErrUnsupportedAlg
+	}
+	sig, err := decodeSegment(parts[2])
+	if err != nil {
+		return claims, ErrTokenMalformed
+	}
+
+	key, err := keyFunc(header)
+	if err != nil {
+		return claims, err
+	}
+	if err := verify(header.Alg, key, []byte(parts[0]+"."+parts[1]), sig); err != nil {
+		return claims, err
+	}
+
+	payload, err := decodeSegment(parts[1])
+	if err != nil {
+		return claims, ErrTokenMalformed
+	}
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return claims, fmt.Errorf("%w: %v", ErrTokenMalformed, err)
+	}
+	return claims, validate(claims.Registered(), o)
+}
+
+// validate checks the time-based and identity claims
+func validate(rc RegisteredClaims, o *Option) error {
+	now := o.Now()
+	if rc.ExpiresAt == 0 && o.RequireExp {
+		return ErrMissingExpiration
+	}
+	if rc.ExpiresAt != 0 && !now.Before(time.Unix(rc.ExpiresAt, 0).Add(o.Leeway)) {
+		return ErrTokenExpired
+	}
+	if rc.NotBefore != 0 && now.Add(o.Leeway).Before(time.Unix(rc.NotBefore, 0)) {
+		return ErrTokenNotValidYet
+	}
+	if o.Audience != "" && !rc.Audience.Contains(o.Audience) {
+		return ErrInvalidAudience
+	}
+	if o.Issuer != "" && rc.Issuer != o.Issuer {
+		return ErrInvalidIssuer
+	}
+	return nil
+}
+
+// methodForKey infers the signing algorithm from the key type
+func methodForKey(key any) string {
+	switch k := key.(type) {
+	case []byte:
+		return HS256
+	case *rsa.PrivateKey:
+		return RS256
+	case *ecdsa.PrivateKey:
+		if k != nil && isP256(&k.PublicKey) {
+			return ES256
+		}
+		return ""
+	default:
+		return ""
+	}
+}
+
+// isP256 reports whether key is on the P-256 curve, the only one of ES256
+func isP256(key *ecdsa.PublicKey) bool {
+	return key != nil && key.Curve == elliptic.P256()
+}
+
+func sign(alg string, key any, input []byte) ([]byte, error) {
+	digest := sha256.Sum256(input)
+	switch alg {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		m := hmac.New(sha256.New, secret)
+		m.Write(input)
+		return m.Sum(nil), nil
+	case RS256:
+		priv, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, ErrInvalidKey
+		}
+		return rsa.SignPKCS1v15(rand.Reader, priv, crypto.SHA256, digest[:])
+	case ES256:
+		priv, ok := key.(*ecdsa.PrivateKey)
+		if !ok || priv == nil || !isP256(&priv.PublicKey) {
+			return nil, ErrInvalidKey
+		}
+		r, s, err := ecdsa.Sign(rand.Reader, priv, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// JWS uses the fixed-size R || S encoding rather than ASN.1
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, nil
+	default:
+		return nil, ErrUnsupportedAlg
+	}
+}
+
+func verify(alg string, key any, input, sig []byte) error {
+	digest := sha256.Sum256(input)
+	switch alg {
+	case HS256:
+		secret, ok := key.([]byte)
+		if !ok {
+			return ErrInvalidKey
+		}
+		m := hmac.New(sha256.New, secret)
+		m.Write(input)
+		if !hmac.Equal(m.Sum(nil), sig) {
+			return ErrInvalidSignature
+		}
+		return nil
+	case RS256:
+		pub, ok := publicKey(key).(*rsa.PublicKey)
+		if !ok {
+			return ErrInvalidKey
+		}
+		if rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], sig) != nil {
+			return ErrInvalidSignature
+		}
+		return nil
+	case ES256:
+		pub, ok := publicKey(key).(*ecdsa.PublicKey)
+		if !ok || !isP256(pub) {
+			return ErrInvalidKey
+		}
+		if len(sig) != 64 {
+			return ErrInvalidSignature
+		}
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(pub, digest[:], r, s) {
+			return ErrInvalidSignature
+		}
+		return nil
+	default:
+		return ErrUnsupportedAlg
+	}
+}
+
+// publicKey allows private keys to be passed where a verification key is expected
+func publicKey(key any) any {
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		return &k.PublicKey
+	case *ecdsa.PrivateKey:
+		if k == nil {
+			return (*ecdsa.PublicKey)(nil)
+		}
+		return &k.PublicKey
+	default:
+		return key
+	}
+}
+
+func encodeSegment(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeSegment(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(s)
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
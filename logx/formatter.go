@@ -9,13 +9,14 @@ import (
 
 // LogEntry represents a log entry structure
 type LogEntry struct {
-	Time       time.Time `json:"time" xml:"time"`       // Time when the log occurred
-	Level      Level     `json:"level" xml:"level"`     // Log level (e.g., TRACE, INFO, ERROR, etc.)
-	Prefix     string    `json:"prefix" xml:"prefix"`   // Log prefix for distinguishing modules or subsystems, can be empty
-	File       string    `json:"file" xml:"file"`       // File path where the log is located (relative or formatted path)
-	Line       int       `json:"line" xml:"line"`       // Line number in the file where the log is located
-	Message    string    `json:"message" xml:"message"` // Log message content
-	CallerSkip int       `json:"-" xml:"-"`             // Stack depth for determining the call source location (file and line number)
+	Time       time.Time `json:"time" xml:"time"`                       // Time when the log occurred
+	Level      Level     `json:"level" xml:"level"`                     // Log level (e.g., TRACE, INFO, ERROR, etc.)
+	Prefix     string    `json:"prefix" xml:"prefix"`                   // Log prefix for distinguishing modules or subsystems, can be empty
+	File       string    `json:"file" xml:"file"`                       // File path where the log is located (relative or formatted path)
+	Line       int       `json:"line" xml:"line"`                       // Line number in the file where the log is located
+	Message    string    `json:"message" xml:"message"`                 // Log message content
+	Stack      string    `json:"stack,omitempty" xml:"stack,omitempty"` // Stack trace of an error argument implementing StackTracer, Error level only
+	CallerSkip int       `json:"-" xml:"-"`                             // Stack depth for determining the call source location (file and line number)
 }
 
 // Formatter defines a function type for formatting log entries
@@ -43,6 +44,9 @@ var DefaultFormatter Formatter = func(entry LogEntry) []byte {
 		color.New(color.FgHiBlack).Add(color.Bold).Sprint(prefix),
 		entry.Level.Color().Sprint(entry.Message),
 	)
+	if entry.Stack != "" {
+		logStr += "\n" + color.New(color.FgHiBlack).Sprint(strings.TrimRight(entry.Stack, "\n"))
+	}
 	return []byte(logStr + "\n")
 }
 
@@ -1,6 +1,7 @@
 package logx
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -118,6 +119,31 @@ func (l *Logger) log(level Level, format string, v ...any) error {
 		File:       file,
 		Line:       line,
 		Message:    msg,
+		Stack:      stackOf(level, v),
 	}))
 	return err
 }
+
+// StackTracer is implemented by errors that captured a stack trace, such as *errorx.Error
+// When an argument of an Error level log implements it, the stack is rendered after the message
+type StackTracer interface {
+	StackTrace() string
+}
+
+// stackOf returns the stack of the first error argument implementing StackTracer
+func stackOf(level Level, v []any) string {
+	if level < LevelError {
+		return ""
+	}
+	for _, arg := range v {
+		err, ok := arg.(error)
+		if !ok {
+			continue
+		}
+		var st StackTracer
+		if errors.As(err, &st) {
+			return st.StackTrace()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,75 @@
+package mapx
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Keys returns the keys of m in unspecified order
+func Keys[M ~map[K]V, K comparable, V any](m M) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// SortedKeys returns the keys of m in ascending order
+func SortedKeys[M ~map[K]V, K cmp.Ordered, V any](m M) []K {
+	keys := Keys(m)
+	slices.Sort(keys)
+	return keys
+}
+
+// Values returns the values of m in unspecified order
+func Values[M ~map[K]V, K comparable, V any](m M) []V {
+	values := make([]V, 0, len(m))
+	for _, v := range m {
+		values = append(values, v)
+	}
+	return values
+}
+
+// Merge returns a new map containing the entries of all maps, later maps win on key conflicts
+func Merge[M ~map[K]V, K comparable, V any](maps ...M) M {
+	size := 0
+	for _, m := range maps {
+		size += len(m)
+	}
+	out := make(M, size)
+	for _, m := range maps {
+		for k, v := range m {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// Filter returns a new map with the entries for which keep returns true
+func Filter[M ~map[K]V, K comparable, V any](m M, keep func(K, V) bool) M {
+	out := make(M)
+	for k, v := range m {
+		if keep(k, v) {
+			out[k] = v
+		}
+	}
+	return out
+}
+
+// MapValues returns a new map with every value transformed by fn
+func MapValues[M ~map[K]V, K comparable, V, R any](m M, fn func(V) R) map[K]R {
+	out := make(map[K]R, len(m))
+	for k, v := range m {
+		out[k] = fn(v)
+	}
+	return out
+}
+
+// Invert swaps keys and values; when values repeat, an arbitrary key wins
+func Invert[M ~map[K]V, K, V comparable](m M) map[V]K {
+	out := make(map[V]K, len(m))
+	for k, v := range m {
+		out[v] = k
+	}
+	return out
+}
@@ -0,0 +1,162 @@
+package mapx
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GetPath looks up a nested value by a dot separated path, e.g. GetPath(m, "a.b.0.c")
+// Intermediate values may be map[string]any, map[any]any or []any (indexed by number)
+func GetPath(m map[string]any, path string) (any, bool) {
+	var cur any = m
+	for _, key := range strings.Split(path, ".") {
+		switch node := cur.(type) {
+		case map[string]any:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case map[any]any:
+			v, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, false
+			}
+			cur = node[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, true
+}
+
+// GetString returns the string at path, numbers and booleans are formatted
+func GetString(m map[string]any, path string) (string, bool) {
+	v, ok := GetPath(m, path)
+	if !ok || v == nil {
+		return "", false
+	}
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case fmt.Stringer:
+		return s.String(), true
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(s), true
+	default:
+		return "", false
+	}
+}
+
+// GetInt returns the integer at path, accepting any numeric type and numeric strings
+// Floats with a fractional part and values outside the int64 range are rejected
+func GetInt(m map[string]any, path string) (int64, bool) {
+	v, ok := GetPath(m, path)
+	if !ok {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case int:
+		return int64(n), true
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case uint:
+		return uintToInt(uint64(n))
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		return uintToInt(n)
+	case uintptr:
+		return uintToInt(uint64(n))
+	case float32:
+		return floatToInt(float64(n))
+	case float64:
+		return floatToInt(n)
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+		return i, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// uintToInt converts n, reporting false when it overflows int64
+func uintToInt(n uint64) (int64, bool) {
+	if n > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(n), true
+}
+
+// floatToInt converts f, reporting false when it is not an integral value in the int64 range
+func floatToInt(f float64) (int64, bool) {
+	if f != math.Trunc(f) || math.IsNaN(f) || f >= math.MaxInt64 || f < math.MinInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+// GetFloat returns the number at path as float64, accepting numeric strings
+func GetFloat(m map[string]any, path string) (float64, bool) {
+	v, ok := GetPath(m, path)
+	if !ok {
+		return 0, false
+	}
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	default:
+		i, ok := GetInt(m, path)
+		return float64(i), ok
+	}
+}
+
+// GetBool returns the boolean at path, accepting strings understood by strconv.ParseBool
+func GetBool(m map[string]any, path string) (bool, bool) {
+	v, ok := GetPath(m, path)
+	if !ok {
+		return false, false
+	}
+	switch b := v.(type) {
+	case bool:
+		return b, true
+	case string:
+		r, err := strconv.ParseBool(strings.TrimSpace(b))
+		return r, err == nil
+	default:
+		return false, false
+	}
+}
+
+// GetMap returns the nested map at path
+func GetMap(m map[string]any, path string) (map[string]any, bool) {
+	v, ok := GetPath(m, path)
+	if !ok {
+		return nil, false
+	}
+	nested, ok := v.(map[string]any)
+	return nested, ok
+}
@@ -0,0 +1,114 @@
+package metricsx
+
+import (
+	"math"
+	"sort"
+	"sync/atomic"
+)
+
+// Labels are the label name/value pairs identifying a series
+type Labels map[string]string
+
+// Counter is a monotonically increasing value
+type Counter interface {
+	Inc()
+	Add(v float64) // v must not be negative
+}
+
+// Gauge is a value that can go up and down
+type Gauge interface {
+	Set(v float64)
+	Add(v float64)
+	Inc()
+	Dec()
+}
+
+// Histogram samples observations into buckets
+type Histogram interface {
+	Observe(v float64)
+}
+
+// Provider creates metrics, implement it to route metricsx users to another backend
+type Provider interface {
+	Counter(name, help string, labels Labels) Counter
+	Gauge(name, help string, labels Labels) Gauge
+	Histogram(name, help string, buckets []float64, labels Labels) Histogram
+}
+
+// DefBuckets are the default histogram buckets, in seconds, suited to request latencies
+var DefBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+// atomicFloat is a float64 updated with compare-and-swap
+type atomicFloat struct {
+	bits atomic.Uint64
+}
+
+func (f *atomicFloat) Load() float64 {
+	return math.Float64frombits(f.bits.Load())
+}
+
+func (f *atomicFloat) Store(v float64) {
+	f.bits.Store(math.Float64bits(v))
+}
+
+func (f *atomicFloat) Add(v float64) {
+	for {
+		old := f.bits.Load()
+		next := math.Float64bits(math.Float64frombits(old) + v)
+		if f.bits.CompareAndSwap(old, next) {
+			return
+		}
+	}
+}
+
+type counter struct{ v atomicFloat }
+
+func (c *counter) Inc() { c.v.Add(1) }
+
+func (c *counter) Add(v float64) {
+	if v < 0 {
+		return
+	}
+	c.v.Add(v)
+}
+
+type gauge struct{ v atomicFloat }
+
+func (g *gauge) Set(v float64) { g.v.Store(v) }
+func (g *gauge) Add(v float64) { g.v.Add(v) }
+func (g *gauge) Inc()          { g.v.Add(1) }
+func (g *gauge) Dec()          { g.v.Add(-1) }
+
+type histogram struct {
+	upper  []float64       // Sorted bucket upper bounds
+	counts []atomic.Uint64 // Non-cumulative count per bucket, the last one is +Inf
+	sum    atomicFloat
+	count  atomic.Uint64
+}
+
+func newHistogram(buckets []float64) *histogram {
+	if len(buckets) == 0 {
+		buckets = DefBuckets
+	}
+	upper := append([]float64(nil), buckets...)
+	sort.Float64s(upper)
+	return &histogram{upper: upper, counts: make([]atomic.Uint64, len(upper)+1)}
+}
+
+func (h *histogram) Observe(v float64) {
+	i := sort.SearchFloat64s(h.upper, v)
+	h.counts[i].Add(1)
+	h.sum.Add(v)
+	h.count.Add(1)
+}
+
+// cumulative returns the cumulative bucket counts, the last element is the +Inf bucket
+func (h *histogram) cumulative() []uint64 {
+	out := make([]uint64, len(h.counts))
+	var total uint64
+	for i := range h.counts {
+		total += h.counts[i].Load()
+		out[i] = total
+	}
+	return out
+}
@@ -0,0 +1,79 @@
+package metricsx
+
+import (
+	"github.com/chihqiang/gox/logx"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// HTTPMiddleware records server metrics for every request handled by next:
+// http_server_requests_total{method,code}, http_server_request_duration_seconds{method}
+// and http_server_requests_in_flight. A nil provider uses Default()
+func HTTPMiddleware(p Provider) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provider := p
+			if provider == nil {
+				provider = Default()
+			}
+			inFlight := provider.Gauge("http_server_requests_in_flight", "HTTP requests currently being served", nil)
+			inFlight.Inc()
+			defer inFlight.Dec()
+
+			begin := time.Now()
+			rec := &statusRecorder{ResponseWriter: w}
+			next.ServeHTTP(rec, r)
+			if rec.status == 0 {
+				rec.status = http.StatusOK
+			}
+			provider.Counter("http_server_requests_total", "HTTP requests handled",
+				Labels{"method": r.Method, "code": strconv.Itoa(rec.status)}).Inc()
+			provider.Histogram("http_server_request_duration_seconds", "HTTP request latency", nil,
+				Labels{"method": r.Method}).Observe(time.Since(begin).Seconds())
+		})
+	}
+}
+
+// LogFormatter wraps a logx formatter and counts log entries in log_messages_total{level}
+// A nil next uses logx.DefaultFormatter, a nil provider uses Default()
+//
+//	logx.SetFormatter(metricsx.LogFormatter(nil, nil))
+func LogFormatter(p Provider, next logx.Formatter) logx.Formatter {
+	if next == nil {
+		next = logx.DefaultFormatter
+	}
+	return func(entry logx.LogEntry) []byte {
+		provider := p
+		if provider == nil {
+			provider = Default()
+		}
+		provider.Counter("log_messages_total", "Log entries written", Labels{"level": entry.Level.String()}).Inc()
+		return next(entry)
+	}
+}
@@ -0,0 +1,252 @@
+package metricsx
+
+import (
+	"bufio"
+	"expvar"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// kind is the metric type as written in the exposition format
+type kind string
+
+const (
+	kindCounter   kind = "counter"
+	kindGauge     kind = "gauge"
+	kindHistogram kind = "histogram"
+)
+
+// family groups the series sharing a metric name
+type family struct {
+	name   string
+	help   string
+	kind   kind
+	series map[string]*series // Keyed by the rendered label set
+}
+
+type series struct {
+	labels string // Rendered label set, e.g. `method="GET",code="200"`
+	metric any    // *counter, *gauge or *histogram
+}
+
+// Registry is an in-memory Provider that can expose its metrics as Prometheus text or expvar
+type Registry struct {
+	mu       sync.RWMutex
+	families map[string]*family
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]*family)}
+}
+
+var defaultProvider atomic.Pointer[Provider]
+
+var defaultRegistry = NewRegistry()
+
+func init() {
+	var p Provider = defaultRegistry
+	defaultProvider.Store(&p)
+}
+
+// Default returns the Provider used by package-level helpers and middlewares
+func Default() Provider {
+	return *defaultProvider.Load()
+}
+
+// SetDefault replaces the default Provider, e.g. with an adapter to another metrics library
+func SetDefault(p Provider) {
+	defaultProvider.Store(&p)
+}
+
+// DefaultRegistry returns the built-in registry backing Default unless it was replaced
+func DefaultRegistry() *Registry {
+	return defaultRegistry
+}
+
+// Counter returns the counter name{labels}, creating it on first use
+func (r *Registry) Counter(name, help string, labels Labels) Counter {
+	return r.get(name, help, kindCounter, labels, func() any { return &counter{} }).(*counter)
+}
+
+// Gauge returns the gauge name{labels}, creating it on first use
+func (r *Registry) Gauge(name, help string, labels Labels) Gauge {
+	return r.get(name, help, kindGauge, labels, func() any { return &gauge{} }).(*gauge)
+}
+
+// Histogram returns the histogram name{labels}, creating it on first use
+// buckets are only used on creation, nil means DefBuckets
+func (r *Registry) Histogram(name, help string, buckets []float64, labels Labels) Histogram {
+	return r.get(name, help, kindHistogram, labels, func() any { return newHistogram(buckets) }).(*histogram)
+}
+
+// get returns an existing series or creates it, panicking if name is reused with another type
+func (r *Registry) get(name, help string, k kind, labels Labels, create func() any) any {
+	key := renderLabels(labels)
+	r.mu.RLock()
+	if f, ok := r.families[name]; ok && f.kind == k {
+		if s, ok := f.series[key]; ok {
+			r.mu.RUnlock()
+			return s.metric
+		}
+	}
+	r.mu.RUnlock()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.families[name]
+	if !ok {
+		f = &family{name: name, help: help, kind: k, series: make(map[string]*series)}
+		r.families[name] = f
+	}
+	if f.kind != k {
+		panic(fmt.Sprintf("metricsx: %s registered as %s, requested as %s", name, f.kind, k))
+	}
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: key, metric: create()}
+		f.series[key] = s
+	}
+	return s.metric
+}
+
+// WriteText writes all metrics in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	r.mu.RLock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := r.families[name]
+		if f.help != "" {
+			fmt.Fprintf(bw, "# HELP %s %s\n", name, escapeHelp(f.help))
+		}
+		fmt.Fprintf(bw, "# TYPE %s %s\n", name, f.kind)
+		keys := make([]string, 0, len(f.series))
+		for k := range f.series {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			writeSeries(bw, name, f.series[k])
+		}
+	}
+	r.mu.RUnlock()
+	return bw.Flush()
+}
+
+// Handler serves the metrics in the Prometheus text format, e.g. mounted on /metrics
+func (r *Registry) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		_ = r.WriteText(w)
+	})
+}
+
+// Snapshot returns the current values keyed by series, e.g. `http_requests_total{code="200"}`
+// Histograms are reported with their count and sum
+func (r *Registry) Snapshot() map[string]any {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make(map[string]any)
+	for name, f := range r.families {
+		for _, s := range f.series {
+			id := name
+			if s.labels != "" {
+				id += "{" + s.labels + "}"
+			}
+			switch m := s.metric.(type) {
+			case *counter:
+				out[id] = m.v.Load()
+			case *gauge:
+				out[id] = m.v.Load()
+			case *histogram:
+				out[id] = map[string]any{"count": m.count.Load(), "sum": m.sum.Load()}
+			}
+		}
+	}
+	return out
+}
+
+// PublishExpvar exposes Snapshot under name on the expvar /debug/vars endpoint
+// Like expvar.Publish, it panics if name is already published
+func (r *Registry) PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any { return r.Snapshot() }))
+}
+
+func writeSeries(w io.Writer, name string, s *series) {
+	switch m := s.metric.(type) {
+	case *counter:
+		fmt.Fprintf(w, "%s%s %s\n", name, braces(s.labels), formatFloat(m.v.Load()))
+	case *gauge:
+		fmt.Fprintf(w, "%s%s %s\n", name, braces(s.labels), formatFloat(m.v.Load()))
+	case *histogram:
+		counts := m.cumulative()
+		for i, le := range m.upper {
+			fmt.Fprintf(w, "%s_bucket%s %d\n", name, braces(joinLabels(s.labels, `le="`+formatFloat(le)+`"`)), counts[i])
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", name, braces(joinLabels(s.labels, `le="+Inf"`)), counts[len(counts)-1])
+		fmt.Fprintf(w, "%s_sum%s %s\n", name, braces(s.labels), formatFloat(m.sum.Load()))
+		fmt.Fprintf(w, "%s_count%s %d\n", name, braces(s.labels), m.count.Load())
+	}
+}
+
+// renderLabels renders labels sorted by name, the result identifies the series
+func renderLabels(labels Labels) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	names := make([]string, 0, len(labels))
+	for k := range labels {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	parts := make([]string, len(names))
+	for i, k := range names {
+		parts[i] = k + `="` + escapeLabel(labels[k]) + `"`
+	}
+	return strings.Join(parts, ",")
+}
+
+func joinLabels(a, b string) string {
+	if a == "" {
+		return b
+	}
+	return a + "," + b
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+var (
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+)
+
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
@@ -0,0 +1,129 @@
+package netx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"strconv"
+	"time"
+)
+
+// cgnatPrefix is the carrier-grade NAT shared address space (RFC 6598)
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// FreePort asks the kernel for a free TCP port on localhost
+// The port is released before returning, so another process may take it in the meantime
+func FreePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// IsPortOpen reports whether a TCP connection to host:port succeeds within timeout
+func IsPortOpen(host string, port int, timeout time.Duration) bool {
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(host, strconv.Itoa(port)), timeout)
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// WaitForPort blocks until a TCP connection to addr ("host:port") succeeds or ctx is done
+// Attempts are retried with an interval growing from 50ms up to 1s
+func WaitForPort(ctx context.Context, addr string) error {
+	var d net.Dialer
+	interval := 50 * time.Millisecond
+	for {
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			return conn.Close()
+		}
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+		if interval < time.Second {
+			interval = min(interval*2, time.Second)
+		}
+	}
+}
+
+// LocalIPs returns the unicast addresses of all interfaces that are up, loopback excluded
+func LocalIPs() ([]net.IP, error) {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+	var ips []net.IP
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagUp == 0 || iface.Flags&net.FlagLoopback != 0 {
+			continue
+		}
+		addrs, err := iface.Addrs()
+		if err != nil {
+			continue
+		}
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok || ipNet.IP.IsLoopback() || ipNet.IP.IsLinkLocalUnicast() {
+				continue
+			}
+			ips = append(ips, ipNet.IP)
+		}
+	}
+	return ips, nil
+}
+
+// LocalIPv4 returns the first non-loopback IPv4 address of the host
+func LocalIPv4() (net.IP, error) {
+	ips, err := LocalIPs()
+	if err != nil {
+		return nil, err
+	}
+	for _, ip := range ips {
+		if v4 := ip.To4(); v4 != nil {
+			return v4, nil
+		}
+	}
+	return nil, errors.New("netx: no IPv4 address found")
+}
+
+// OutboundIP returns the local address used to reach the internet
+// It opens a UDP socket towards a public address, no packet is sent
+func OutboundIP() (net.IP, error) {
+	conn, err := net.Dial("udp", "8.8.8.8:80")
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).IP, nil
+}
+
+// IsPrivateIP reports whether ip is not publicly routable: RFC 1918 / RFC 4193 private,
+// loopback, link-local, unspecified or carrier-grade NAT. Invalid input returns false
+func IsPrivateIP(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	return addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsUnspecified() || cgnatPrefix.Contains(addr)
+}
+
+// IsPublicIP reports whether ip is a valid, globally routable unicast address
+func IsPublicIP(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	return addr.Unmap().IsGlobalUnicast() && !IsPrivateIP(ip)
+}
@@ -0,0 +1,88 @@
+package paginationx
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded
+var ErrInvalidCursor = errors.New("paginationx: invalid cursor")
+
+// EncodeCursor encodes v (typically the sort key of the last item) into an opaque URL-safe string
+func EncodeCursor(v any) (string, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// DecodeCursor decodes a cursor produced by EncodeCursor into v, an empty cursor leaves v untouched
+func DecodeCursor(cursor string, v any) error {
+	if cursor == "" {
+		return nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+// CursorParams is a cursor page request
+type CursorParams struct {
+	Cursor string `json:"cursor"`
+	Size   int    `json:"page_size"`
+}
+
+// CursorFromQuery reads the "cursor" and page size parameters from a query string
+func CursorFromQuery(q url.Values, opts ...OptionFunc) CursorParams {
+	o := newOption(opts)
+	size, _ := strconv.Atoi(q.Get(o.SizeParam))
+	return CursorParams{Cursor: q.Get("cursor"), Size: NewParams(1, size, opts...).Size}
+}
+
+// Limit returns the number of rows to fetch: one more than the page size,
+// so NewCursorPage can tell whether another page follows
+func (p CursorParams) Limit() int {
+	return p.Size + 1
+}
+
+// CursorMeta describes a cursor page
+type CursorMeta struct {
+	NextCursor string `json:"next_cursor,omitempty" xml:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more" xml:"has_more"`
+}
+
+// CursorPage is a page of items with its cursor metadata
+type CursorPage[T any] struct {
+	Items []T        `json:"items" xml:"items"`
+	Meta  CursorMeta `json:"meta" xml:"meta"`
+}
+
+// NewCursorPage builds a page from up to size+1 fetched items (see CursorParams.Limit),
+// trimming the extra item and encoding the cursor of the last returned item with cursorOf
+func NewCursorPage[T any](items []T, size int, cursorOf func(T) any) (CursorPage[T], error) {
+	page := CursorPage[T]{Items: items}
+	if len(items) > size {
+		page.Items = items[:size]
+		page.Meta.HasMore = true
+	}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+	if page.Meta.HasMore && len(page.Items) > 0 {
+		next, err := EncodeCursor(cursorOf(page.Items[len(page.Items)-1]))
+		if err != nil {
+			return page, err
+		}
+		page.Meta.NextCursor = next
+	}
+	return page, nil
+}
@@ -0,0 +1,128 @@
+package paginationx
+
+import (
+	"github.com/chihqiang/gox/slicex"
+	"net/url"
+	"strconv"
+)
+
+// Option pagination configuration structure
+type Option struct {
+	DefaultSize int    // Page size used when the request gives none, default 20
+	MaxSize     int    // Upper bound of the page size, default 100
+	PageParam   string // Query parameter holding the page number, default "page"
+	SizeParam   string // Query parameter holding the page size, default "page_size"
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithDefaultSize sets the page size used when none is requested
+func WithDefaultSize(n int) OptionFunc {
+	return func(o *Option) { o.DefaultSize = n }
+}
+
+// WithMaxSize caps the page size a client may request
+func WithMaxSize(n int) OptionFunc {
+	return func(o *Option) { o.MaxSize = n }
+}
+
+// WithParams renames the page number and page size query parameters
+func WithParams(page, size string) OptionFunc {
+	return func(o *Option) {
+		o.PageParam = page
+		o.SizeParam = size
+	}
+}
+
+func newOption(opts []OptionFunc) *Option {
+	o := &Option{DefaultSize: 20, MaxSize: 100, PageParam: "page", SizeParam: "page_size"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Params is a 1-based page request
+type Params struct {
+	Page int `json:"page"`
+	Size int `json:"page_size"`
+}
+
+// NewParams returns page and size clamped to the configured default and maximum size
+func NewParams(page, size int, opts ...OptionFunc) Params {
+	o := newOption(opts)
+	if page < 1 {
+		page = 1
+	}
+	if size <= 0 {
+		size = o.DefaultSize
+	}
+	if o.MaxSize > 0 && size > o.MaxSize {
+		size = o.MaxSize
+	}
+	return Params{Page: page, Size: size}
+}
+
+// FromQuery reads the page parameters from a query string, e.g. r.URL.Query()
+// Missing or malformed values fall back to the first page and the default size
+func FromQuery(q url.Values, opts ...OptionFunc) Params {
+	o := newOption(opts)
+	page, _ := strconv.Atoi(q.Get(o.PageParam))
+	size, _ := strconv.Atoi(q.Get(o.SizeParam))
+	return NewParams(page, size, opts...)
+}
+
+// Offset returns the number of items before the page, for SQL OFFSET
+func (p Params) Offset() int {
+	if p.Page < 1 {
+		return 0
+	}
+	return (p.Page - 1) * p.Size
+}
+
+// Limit returns the page size, for SQL LIMIT
+func (p Params) Limit() int {
+	return p.Size
+}
+
+// Meta describes an offset page
+type Meta struct {
+	Page       int  `json:"page" xml:"page"`
+	Size       int  `json:"page_size" xml:"page_size"`
+	Total      int  `json:"total" xml:"total"`
+	TotalPages int  `json:"total_pages" xml:"total_pages"`
+	HasNext    bool `json:"has_next" xml:"has_next"`
+	HasPrev    bool `json:"has_prev" xml:"has_prev"`
+}
+
+// NewMeta computes the metadata of page p of a collection of total items
+func NewMeta(p Params, total int) Meta {
+	m := Meta{Page: p.Page, Size: p.Size, Total: total}
+	if p.Size > 0 {
+		m.TotalPages = (total + p.Size - 1) / p.Size
+	}
+	m.HasNext = p.Page < m.TotalPages
+	m.HasPrev = p.Page > 1
+	return m
+}
+
+// Page is a page of items with its metadata, see httpx.PageResponse for the response envelope
+type Page[T any] struct {
+	Items []T  `json:"items" xml:"items"`
+	Meta  Meta `json:"meta" xml:"meta"`
+}
+
+// NewPage builds a page from items already fetched for p, e.g. by a LIMIT/OFFSET query
+func NewPage[T any](items []T, p Params, total int) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{Items: items, Meta: NewMeta(p, total)}
+}
+
+// Paginate returns page (1-based) of size items of an in-memory slice
+func Paginate[T any](items []T, page, size int) Page[T] {
+	p := Params{Page: max(page, 1), Size: size}
+	return NewPage(slicex.Paginate(items, p.Page, p.Size), p, len(items))
+}
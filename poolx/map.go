@@ -0,0 +1,28 @@
+package poolx
+
+import (
+	"context"
+	"github.com/chihqiang/gox/slicex"
+)
+
+// Map applies fn to every item with at most workers concurrent calls and returns the results
+// in input order. The first error cancels the remaining calls and is returned
+func Map[T, R any](ctx context.Context, items []T, workers int, fn func(ctx context.Context, item T) (R, error)) ([]R, error) {
+	results := make([]R, len(items))
+	indexes := make([]int, len(items))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	err := slicex.ForEachParallel(ctx, indexes, workers, func(ctx context.Context, i int) error {
+		r, err := fn(ctx, items[i])
+		if err != nil {
+			return err
+		}
+		results[i] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
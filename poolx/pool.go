@@ -0,0 +1,194 @@
+package poolx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/logx"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrPoolClosed is returned when submitting to a pool that has been closed or whose context is done
+var ErrPoolClosed = errors.New("poolx: pool is closed")
+
+// PanicHandler is called with the recovered value when a task panics
+type PanicHandler func(v any)
+
+// Option pool configuration structure
+type Option struct {
+	QueueSize int          // Capacity of the pending task queue, defaults to the worker count
+	OnPanic   PanicHandler // Panic reporter, defaults to logging through logx
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithQueueSize sets the capacity of the pending task queue
+func WithQueueSize(n int) OptionFunc {
+	return func(o *Option) { o.QueueSize = n }
+}
+
+// WithPanicHandler sets the handler that receives task panics
+func WithPanicHandler(h PanicHandler) OptionFunc {
+	return func(o *Option) { o.OnPanic = h }
+}
+
+// Pool is a bounded worker pool
+// A panicking task is recovered and reported, it never takes a worker down
+type Pool struct {
+	mu      sync.RWMutex // Guards closed against concurrent Submit/Close
+	closed  bool
+	done    chan struct{}  // Closed when the pool stops accepting tasks
+	senders sync.WaitGroup // Submit calls waiting for queue space, tasks is closed once they leave
+	tasks   chan func()
+	wg      sync.WaitGroup
+	active  atomic.Int64
+	workers int
+	onPanic PanicHandler
+
+	stopWatch func() bool // Detaches the context watcher
+}
+
+// New starts a pool with the given number of workers
+// When ctx is done the pool stops accepting tasks and drains the queue, like Close
+func New(ctx context.Context, workers int, opts ...OptionFunc) *Pool {
+	if workers <= 0 {
+		workers = 1
+	}
+	o := &Option{QueueSize: workers, OnPanic: defaultPanicHandler}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.QueueSize < 0 {
+		o.QueueSize = 0
+	}
+
+	p := &Pool{
+		done:    make(chan struct{}),
+		tasks:   make(chan func(), o.QueueSize),
+		workers: workers,
+		onPanic: o.OnPanic,
+	}
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go p.worker()
+	}
+	p.stopWatch = context.AfterFunc(ctx, p.shutdown)
+	return p
+}
+
+// Submit queues task for execution, blocking while the queue is full
+func (p *Pool) Submit(task func()) error {
+	return p.SubmitCtx(context.Background(), task)
+}
+
+// SubmitCtx is like Submit but gives up with ctx.Err() when ctx is done before the queue has room
+// Closing the pool unblocks waiting calls with ErrPoolClosed
+func (p *Pool) SubmitCtx(ctx context.Context, task func()) error {
+	p.mu.RLock()
+	if p.closed {
+		p.mu.RUnlock()
+		return ErrPoolClosed
+	}
+	p.senders.Add(1)
+	p.mu.RUnlock()
+	defer p.senders.Done()
+
+	select {
+	case p.tasks <- task:
+		return nil
+	case <-p.done:
+		return ErrPoolClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// SubmitWait queues task and waits for it to finish
+// A panic inside task is reported and returned as an error
+func (p *Pool) SubmitWait(task func()) error {
+	done := make(chan error, 1)
+	err := p.Submit(func() {
+		var taskErr error
+		defer func() { done <- taskErr }()
+		defer func() {
+			if v := recover(); v != nil {
+				taskErr = fmt.Errorf("poolx: task panicked: %v", v)
+				p.report(v)
+			}
+		}()
+		task()
+	})
+	if err != nil {
+		return err
+	}
+	return <-done
+}
+
+// Close stops accepting tasks, runs everything already queued and waits for the workers to exit
+func (p *Pool) Close() {
+	p.stopWatch()
+	p.shutdown()
+	p.wg.Wait()
+}
+
+// QueueLen returns the number of tasks waiting for a worker
+func (p *Pool) QueueLen() int {
+	return len(p.tasks)
+}
+
+// Active returns the number of tasks currently running
+func (p *Pool) Active() int {
+	return int(p.active.Load())
+}
+
+// Workers returns the number of workers
+func (p *Pool) Workers() int {
+	return p.workers
+}
+
+func (p *Pool) shutdown() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if !p.closed {
+		p.closed = true
+		close(p.done)
+		go func() {
+			// No sender registers once closed is set, the queue closes when the waiting ones leave
+			p.senders.Wait()
+			close(p.tasks)
+		}()
+	}
+}
+
+func (p *Pool) worker() {
+	defer p.wg.Done()
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+// run executes a task with panic isolation
+func (p *Pool) run(task func()) {
+	p.active.Add(1)
+	defer p.active.Add(-1)
+	defer func() {
+		if v := recover(); v != nil {
+			p.report(v)
+		}
+	}()
+	task()
+}
+
+func (p *Pool) report(v any) {
+	if p.onPanic != nil {
+		p.onPanic(v)
+	}
+}
+
+// defaultPanicHandler logs the panic and stack through logx
+func defaultPanicHandler(v any) {
+	logx.Error("poolx: task panicked: %v\n%s", v, debug.Stack())
+}
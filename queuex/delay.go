@@ -0,0 +1,119 @@
+package queuex
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// delayed is an item with the time it becomes available
+type delayed[T any] struct {
+	value T
+	at    time.Time
+	seq   uint64 // Insertion order, keeps items with equal deadlines FIFO
+}
+
+// DelayQueue is a thread-safe queue whose items become available once their deadline passes
+type DelayQueue[T any] struct {
+	mu     sync.Mutex
+	h      *itemHeap[delayed[T]]
+	seq    uint64
+	closed bool
+	notify signal
+}
+
+// NewDelay creates a delay queue
+func NewDelay[T any]() *DelayQueue[T] {
+	return &DelayQueue[T]{h: &itemHeap[delayed[T]]{less: func(a, b delayed[T]) bool {
+		if a.at.Equal(b.at) {
+			return a.seq < b.seq
+		}
+		return a.at.Before(b.at)
+	}}}
+}
+
+// Push adds v, available at time at
+func (q *DelayQueue[T]) Push(v T, at time.Time) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	q.seq++
+	heap.Push(q.h, delayed[T]{value: v, at: at, seq: q.seq})
+	q.notify.broadcast()
+	return nil
+}
+
+// PushAfter adds v, available after d
+func (q *DelayQueue[T]) PushAfter(v T, d time.Duration) error {
+	return q.Push(v, time.Now().Add(d))
+}
+
+// TryPop removes and returns the earliest item if its deadline has passed
+func (q *DelayQueue[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.h.Len() > 0 && !q.h.items[0].at.After(time.Now()) {
+		return heap.Pop(q.h).(delayed[T]).value, true
+	}
+	var zero T
+	return zero, false
+}
+
+// Pop removes and returns the earliest item, blocking until its deadline passes or ctx is done
+// After Close, pending items are still delivered at their deadline and ErrClosed is reported once empty
+func (q *DelayQueue[T]) Pop(ctx context.Context) (T, error) {
+	var zero T
+	for {
+		q.mu.Lock()
+		var wait time.Duration = -1
+		if q.h.Len() > 0 {
+			head := q.h.items[0]
+			if wait = time.Until(head.at); wait <= 0 {
+				heap.Pop(q.h)
+				q.mu.Unlock()
+				return head.value, nil
+			}
+		} else if q.closed {
+			q.mu.Unlock()
+			return zero, ErrClosed
+		}
+		notify := q.notify.wait()
+		q.mu.Unlock()
+
+		var timer *time.Timer
+		var fire <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			fire = timer.C
+		}
+		select {
+		case <-fire:
+		case <-notify:
+		case <-ctx.Done():
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		if ctx.Err() != nil {
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of queued items, including ones not yet due
+func (q *DelayQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.h.Len()
+}
+
+// Close rejects further pushes and wakes up blocked Pop calls
+func (q *DelayQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notify.broadcast()
+}
@@ -0,0 +1,115 @@
+package queuex
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// itemHeap implements heap.Interface over a less function
+type itemHeap[T any] struct {
+	items []T
+	less  func(a, b T) bool
+}
+
+func (h *itemHeap[T]) Len() int           { return len(h.items) }
+func (h *itemHeap[T]) Less(i, j int) bool { return h.less(h.items[i], h.items[j]) }
+func (h *itemHeap[T]) Swap(i, j int)      { h.items[i], h.items[j] = h.items[j], h.items[i] }
+func (h *itemHeap[T]) Push(x any)         { h.items = append(h.items, x.(T)) }
+func (h *itemHeap[T]) Pop() any {
+	var zero T
+	n := len(h.items) - 1
+	v := h.items[n]
+	h.items[n] = zero
+	h.items = h.items[:n]
+	return v
+}
+
+// PriorityQueue is a thread-safe queue returning the item ordered first by less
+type PriorityQueue[T any] struct {
+	mu     sync.Mutex
+	h      *itemHeap[T]
+	closed bool
+	notify signal
+}
+
+// NewPriority creates a priority queue, less(a, b) reports whether a must be popped before b
+func NewPriority[T any](less func(a, b T) bool) *PriorityQueue[T] {
+	return &PriorityQueue[T]{h: &itemHeap[T]{less: less}}
+}
+
+// Push adds v to the queue
+func (q *PriorityQueue[T]) Push(v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	heap.Push(q.h, v)
+	q.notify.broadcast()
+	return nil
+}
+
+// TryPop removes and returns the first item without blocking
+func (q *PriorityQueue[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return heap.Pop(q.h).(T), true
+}
+
+// Pop removes and returns the first item, blocking until one is available or ctx is done
+// After Close, remaining items are still returned and ErrClosed is reported once empty
+func (q *PriorityQueue[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		if q.h.Len() > 0 {
+			v := heap.Pop(q.h).(T)
+			q.mu.Unlock()
+			return v, nil
+		}
+		if q.closed {
+			q.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		wait := q.notify.wait()
+		q.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Peek returns the first item without removing it
+func (q *PriorityQueue[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.h.Len() == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.h.items[0], true
+}
+
+// Len returns the number of queued items
+func (q *PriorityQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.h.Len()
+}
+
+// Close rejects further pushes and wakes up blocked Pop calls
+func (q *PriorityQueue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notify.broadcast()
+}
@@ -0,0 +1,152 @@
+package queuex
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	ErrFull   = errors.New("queuex: queue is full")
+	ErrClosed = errors.New("queuex: queue is closed")
+)
+
+// signal wakes up goroutines blocked in Pop, it must be used with the owning queue's mutex held
+type signal struct {
+	ch chan struct{}
+}
+
+// wait returns a channel closed by the next broadcast
+func (s *signal) wait() <-chan struct{} {
+	if s.ch == nil {
+		s.ch = make(chan struct{})
+	}
+	return s.ch
+}
+
+// broadcast wakes up every waiter
+func (s *signal) broadcast() {
+	if s.ch != nil {
+		close(s.ch)
+		s.ch = nil
+	}
+}
+
+// Queue is a thread-safe FIFO queue backed by a ring buffer
+type Queue[T any] struct {
+	mu       sync.Mutex
+	buf      []T
+	head     int
+	size     int
+	capacity int // 0 means unbounded
+	closed   bool
+	notify   signal
+}
+
+// New creates a FIFO queue, capacity > 0 bounds it and makes Push fail with ErrFull when full
+func New[T any](capacity int) *Queue[T] {
+	initial := capacity
+	if initial <= 0 {
+		initial = 16
+	}
+	return &Queue[T]{buf: make([]T, initial), capacity: max(capacity, 0)}
+}
+
+// Push appends v to the queue
+func (q *Queue[T]) Push(v T) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		return ErrClosed
+	}
+	if q.size == len(q.buf) {
+		if q.capacity > 0 {
+			return ErrFull
+		}
+		q.grow()
+	}
+	q.buf[(q.head+q.size)%len(q.buf)] = v
+	q.size++
+	q.notify.broadcast()
+	return nil
+}
+
+// TryPop removes and returns the oldest item without blocking
+func (q *Queue[T]) TryPop() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.pop()
+}
+
+// Pop removes and returns the oldest item, blocking until one is available or ctx is done
+// After Close, remaining items are still returned and ErrClosed is reported once empty
+func (q *Queue[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		if v, ok := q.pop(); ok {
+			q.mu.Unlock()
+			return v, nil
+		}
+		if q.closed {
+			q.mu.Unlock()
+			var zero T
+			return zero, ErrClosed
+		}
+		wait := q.notify.wait()
+		q.mu.Unlock()
+
+		select {
+		case <-wait:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Peek returns the oldest item without removing it
+func (q *Queue[T]) Peek() (T, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.size == 0 {
+		var zero T
+		return zero, false
+	}
+	return q.buf[q.head], true
+}
+
+// Len returns the number of queued items
+func (q *Queue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+// Close rejects further pushes and wakes up blocked Pop calls
+func (q *Queue[T]) Close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	q.notify.broadcast()
+}
+
+// pop removes the head item, q.mu must be held
+func (q *Queue[T]) pop() (T, bool) {
+	var zero T
+	if q.size == 0 {
+		return zero, false
+	}
+	v := q.buf[q.head]
+	q.buf[q.head] = zero // Release the reference for the GC
+	q.head = (q.head + 1) % len(q.buf)
+	q.size--
+	return v, true
+}
+
+// grow doubles the ring buffer, q.mu must be held
+func (q *Queue[T]) grow() {
+	buf := make([]T, len(q.buf)*2)
+	n := copy(buf, q.buf[q.head:])
+	copy(buf[n:], q.buf[:q.head])
+	q.buf, q.head = buf, 0
+}
@@ -0,0 +1,63 @@
+package randx
+
+// Shuffle shuffles s in place using the default generator
+func Shuffle[T any](s []T) {
+	r := Default()
+	for i := len(s) - 1; i > 0; i-- {
+		j := r.IntN(i + 1)
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// Pick returns a random element of s, ok is false when s is empty
+func Pick[T any](s []T) (v T, ok bool) {
+	if len(s) == 0 {
+		return v, false
+	}
+	return s[Default().IntN(len(s))], true
+}
+
+// PickN returns n distinct random elements of s (all of them, shuffled, if n >= len(s))
+func PickN[T any](s []T, n int) []T {
+	if n <= 0 {
+		return nil
+	}
+	n = min(n, len(s))
+	out := make([]T, 0, n)
+	for _, i := range Default().Perm(len(s))[:n] {
+		out = append(out, s[i])
+	}
+	return out
+}
+
+// WeightedPick returns a random element of items with probability proportional to its weight
+// Non-positive weights are never picked; ok is false when no element has a positive weight
+// or the slices differ in length
+func WeightedPick[T any](items []T, weights []float64) (v T, ok bool) {
+	if len(items) == 0 || len(items) != len(weights) {
+		return v, false
+	}
+	total := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+		}
+	}
+	if total <= 0 {
+		return v, false
+	}
+	target := Default().Float64() * total
+	last := -1
+	for i, w := range weights {
+		if w <= 0 {
+			continue
+		}
+		last = i
+		if target < w {
+			return items[i], true
+		}
+		target -= w
+	}
+	// Floating point rounding can leave a tiny remainder, fall back to the last eligible item
+	return items[last], true
+}
@@ -0,0 +1,97 @@
+package randx
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+)
+
+// Rand is a concurrency-safe random number generator
+type Rand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+// NewCrypto returns a Rand backed by crypto/rand, suitable for security-sensitive choices
+func NewCrypto() *Rand {
+	return &Rand{r: rand.New(cryptoSource{})}
+}
+
+// NewSeeded returns a deterministic Rand, the same seed always yields the same sequence
+// Use it in tests; never for anything security-sensitive
+func NewSeeded(seed uint64) *Rand {
+	return &Rand{r: rand.New(rand.NewPCG(seed, seed^0x9e3779b97f4a7c15))}
+}
+
+var std atomic.Pointer[Rand]
+
+func init() {
+	std.Store(NewCrypto())
+}
+
+// Default returns the generator used by the package-level functions
+func Default() *Rand {
+	return std.Load()
+}
+
+// SetDefault replaces the generator used by the package-level functions,
+// e.g. SetDefault(NewSeeded(1)) for reproducible tests
+func SetDefault(r *Rand) {
+	std.Store(r)
+}
+
+// IntN returns a uniform int in [0, n), it panics if n <= 0
+func (r *Rand) IntN(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.IntN(n)
+}
+
+// Int64N returns a uniform int64 in [0, n), it panics if n <= 0
+func (r *Rand) Int64N(n int64) int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Int64N(n)
+}
+
+// Float64 returns a uniform float64 in [0.0, 1.0)
+func (r *Rand) Float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Float64()
+}
+
+// Perm returns a random permutation of [0, n)
+func (r *Rand) Perm(n int) []int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.r.Perm(n)
+}
+
+// IntN returns a uniform int in [0, n) from the default generator
+func IntN(n int) int {
+	return Default().IntN(n)
+}
+
+// IntRange returns a uniform int in [min, max], it panics if max < min
+func IntRange(min, max int) int {
+	return min + Default().IntN(max-min+1)
+}
+
+// Float64 returns a uniform float64 in [0.0, 1.0) from the default generator
+func Float64() float64 {
+	return Default().Float64()
+}
+
+// cryptoSource adapts crypto/rand to rand.Source
+type cryptoSource struct{}
+
+func (cryptoSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		panic("randx: crypto/rand unavailable: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,62 @@
+package randx
+
+import (
+	crand "crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"math/big"
+)
+
+const (
+	// AlphaNum is the default alphabet for String
+	AlphaNum = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// Digits is an alphabet for numeric codes such as SMS verification codes
+	Digits = "0123456789"
+)
+
+// Bytes returns n bytes from crypto/rand
+// Token helpers always use crypto/rand, even when a seeded default is installed
+func Bytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := crand.Read(b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// Token returns a URL-safe base64 token made of n random bytes, e.g. Token(32) for session IDs
+func Token(n int) (string, error) {
+	b, err := Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// TokenHex returns a hex token made of n random bytes
+func TokenHex(n int) (string, error) {
+	b, err := Bytes(n)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// String returns a crypto-random string of length n drawn uniformly from alphabet,
+// AlphaNum is used when alphabet is empty
+func String(n int, alphabet string) (string, error) {
+	if alphabet == "" {
+		alphabet = AlphaNum
+	}
+	chars := []rune(alphabet)
+	max := big.NewInt(int64(len(chars)))
+	out := make([]rune, n)
+	for i := range out {
+		idx, err := crand.Int(crand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		out[i] = chars[idx.Int64()]
+	}
+	return string(out), nil
+}
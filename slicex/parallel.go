@@ -0,0 +1,69 @@
+package slicex
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ForEachParallel calls fn for every item using at most workers goroutines
+// The first error (or recovered panic) cancels the context passed to the remaining calls
+// and is returned once all started calls have finished
+func ForEachParallel[T any](ctx context.Context, items []T, workers int, fn func(ctx context.Context, item T) error) error {
+	if workers <= 0 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		next     = make(chan T)
+	)
+	fail := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	for i := 0; i < min(workers, len(items)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for item := range next {
+				if err := safeCall(ctx, item, fn); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+feed:
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+			break feed
+		case next <- item:
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// safeCall runs fn converting a panic into an error
+func safeCall[T any](ctx context.Context, item T, fn func(context.Context, T) error) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = fmt.Errorf("slicex: panic: %v", v)
+		}
+	}()
+	return fn(ctx, item)
+}
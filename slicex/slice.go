@@ -0,0 +1,141 @@
+package slicex
+
+import "math/rand/v2"
+
+// Chunk splits s into consecutive sub-slices of at most size elements
+// The chunks share the backing array of s
+func Chunk[S ~[]T, T any](s S, size int) []S {
+	if size <= 0 {
+		return nil
+	}
+	chunks := make([]S, 0, (len(s)+size-1)/size)
+	for size < len(s) {
+		s, chunks = s[size:], append(chunks, s[:size:size])
+	}
+	if len(s) > 0 {
+		chunks = append(chunks, s)
+	}
+	return chunks
+}
+
+// Uniq returns the elements of s without duplicates, keeping the first occurrence order
+func Uniq[S ~[]T, T comparable](s S) S {
+	seen := make(map[T]struct{}, len(s))
+	out := make(S, 0, len(s))
+	for _, v := range s {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+// UniqBy is like Uniq but compares elements by the key returned from fn
+func UniqBy[S ~[]T, T any, K comparable](s S, fn func(T) K) S {
+	seen := make(map[K]struct{}, len(s))
+	out := make(S, 0, len(s))
+	for _, v := range s {
+		k := fn(v)
+		if _, ok := seen[k]; ok {
+			continue
+		}
+		seen[k] = struct{}{}
+		out = append(out, v)
+	}
+	return out
+}
+
+// Difference returns the elements of a that are not in b, keeping the order of a
+func Difference[S ~[]T, T comparable](a, b S) S {
+	exclude := toSet(b)
+	out := make(S, 0, len(a))
+	for _, v := range a {
+		if _, ok := exclude[v]; !ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Intersect returns the unique elements present in both a and b, keeping the order of a
+func Intersect[S ~[]T, T comparable](a, b S) S {
+	include := toSet(b)
+	out := make(S, 0)
+	for _, v := range Uniq(a) {
+		if _, ok := include[v]; ok {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// GroupBy groups the elements of s by the key returned from fn, preserving order within groups
+func GroupBy[S ~[]T, T any, K comparable](s S, fn func(T) K) map[K]S {
+	groups := make(map[K]S)
+	for _, v := range s {
+		k := fn(v)
+		groups[k] = append(groups[k], v)
+	}
+	return groups
+}
+
+// IndexBy maps each element of s by the key returned from fn, later elements win on conflicts
+func IndexBy[S ~[]T, T any, K comparable](s S, fn func(T) K) map[K]T {
+	index := make(map[K]T, len(s))
+	for _, v := range s {
+		index[fn(v)] = v
+	}
+	return index
+}
+
+// Map returns a new slice with fn applied to every element
+func Map[S ~[]T, T, R any](s S, fn func(T) R) []R {
+	out := make([]R, len(s))
+	for i, v := range s {
+		out[i] = fn(v)
+	}
+	return out
+}
+
+// Filter returns a new slice with the elements for which keep returns true
+func Filter[S ~[]T, T any](s S, keep func(T) bool) S {
+	out := make(S, 0, len(s))
+	for _, v := range s {
+		if keep(v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// Shuffle returns a shuffled copy of s using a non-cryptographic source
+func Shuffle[S ~[]T, T any](s S) S {
+	out := make(S, len(s))
+	copy(out, s)
+	rand.Shuffle(len(out), func(i, j int) { out[i], out[j] = out[j], out[i] })
+	return out
+}
+
+// Paginate returns the elements of page (1-based) when s is split into pages of size elements
+// Out-of-range pages return an empty slice
+func Paginate[S ~[]T, T any](s S, page, size int) S {
+	if page < 1 || size <= 0 {
+		return S{}
+	}
+	start := (page - 1) * size
+	if start >= len(s) || start < 0 {
+		return S{}
+	}
+	end := min(start+size, len(s))
+	return s[start:end]
+}
+
+func toSet[S ~[]T, T comparable](s S) map[T]struct{} {
+	set := make(map[T]struct{}, len(s))
+	for _, v := range s {
+		set[v] = struct{}{}
+	}
+	return set
+}
@@ -0,0 +1,95 @@
+package streamx
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// result is the outcome of one ParallelMap call
+type result[R any] struct {
+	value R
+	err   error
+}
+
+// ParallelMap applies fn to the values of seq with at most workers concurrent calls and
+// yields the results in input order. At most workers results are buffered ahead of the consumer,
+// so a slow consumer also slows down the reading of seq. Errors, including recovered panics,
+// are yielded alongside the value and do not stop the stream; stop ranging to abort,
+// which cancels the context passed to the calls still running. If ctx is done the stream ends
+// with its error
+func ParallelMap[T, R any](ctx context.Context, seq iter.Seq[T], workers int, fn func(ctx context.Context, v T) (R, error)) iter.Seq2[R, error] {
+	if workers <= 0 {
+		workers = 1
+	}
+	return func(yield func(R, error) bool) {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		// pending holds one result channel per started call, in input order
+		pending := make(chan chan result[R], workers)
+		slots := make(chan struct{}, workers)
+		go func() {
+			defer close(pending)
+			for v := range seq {
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					return
+				}
+				out := make(chan result[R], 1)
+				select {
+				case pending <- out:
+				case <-ctx.Done():
+					return
+				}
+				go func() {
+					defer func() { <-slots }()
+					r, err := safeApply(ctx, fn, v)
+					out <- result[R]{r, err}
+				}()
+			}
+		}()
+
+		for out := range pending {
+			res := <-out
+			if !yield(res.value, res.err) {
+				return
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			var zero R
+			yield(zero, err)
+		}
+	}
+}
+
+// ParallelFilter keeps the values of seq for which keep reports true, evaluating it with
+// at most workers concurrent calls and preserving input order
+func ParallelFilter[T any](ctx context.Context, seq iter.Seq[T], workers int, keep func(ctx context.Context, v T) (bool, error)) iter.Seq2[T, error] {
+	type kept struct {
+		value T
+		ok    bool
+	}
+	return func(yield func(T, error) bool) {
+		results := ParallelMap(ctx, seq, workers, func(ctx context.Context, v T) (kept, error) {
+			ok, err := keep(ctx, v)
+			return kept{v, ok}, err
+		})
+		for r, err := range results {
+			if (err != nil || r.ok) && !yield(r.value, err) {
+				return
+			}
+		}
+	}
+}
+
+// safeApply runs fn converting a panic into an error
+func safeApply[T, R any](ctx context.Context, fn func(context.Context, T) (R, error), v T) (r R, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = fmt.Errorf("streamx: panic: %v", p)
+		}
+	}()
+	return fn(ctx, v)
+}
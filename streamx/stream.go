@@ -0,0 +1,209 @@
+package streamx
+
+import (
+	"context"
+	"iter"
+)
+
+// Of returns a sequence over values
+func Of[T any](values ...T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range values {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// FromChan returns a sequence over the values received from ch until it is closed
+func FromChan[T any](ch <-chan T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range ch {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// ToChan sends the values of seq on the returned channel, which is closed when seq is
+// exhausted or ctx is done. Cancel ctx when abandoning the channel early to release the goroutine
+func ToChan[T any](ctx context.Context, seq iter.Seq[T], buffer int) <-chan T {
+	ch := make(chan T, max(buffer, 0))
+	go func() {
+		defer close(ch)
+		for v := range seq {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// Map returns a sequence of fn applied to every value of seq
+func Map[T, R any](seq iter.Seq[T], fn func(T) R) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for v := range seq {
+			if !yield(fn(v)) {
+				return
+			}
+		}
+	}
+}
+
+// FlatMap returns the concatenation of the sequences fn produces for every value of seq
+func FlatMap[T, R any](seq iter.Seq[T], fn func(T) iter.Seq[R]) iter.Seq[R] {
+	return func(yield func(R) bool) {
+		for v := range seq {
+			for r := range fn(v) {
+				if !yield(r) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Filter returns the values of seq for which keep reports true
+func Filter[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if keep(v) && !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Take returns at most the first n values of seq, seq is not advanced past them
+func Take[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		if n <= 0 {
+			return
+		}
+		i := 0
+		for v := range seq {
+			if !yield(v) {
+				return
+			}
+			if i++; i >= n {
+				return
+			}
+		}
+	}
+}
+
+// TakeWhile returns the leading values of seq for which keep reports true
+func TakeWhile[T any](seq iter.Seq[T], keep func(T) bool) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range seq {
+			if !keep(v) || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Skip returns the values of seq after the first n
+func Skip[T any](seq iter.Seq[T], n int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		i := 0
+		for v := range seq {
+			if i < n {
+				i++
+				continue
+			}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Distinct returns the values of seq skipping repeats, remembering every value seen
+func Distinct[T comparable](seq iter.Seq[T]) iter.Seq[T] {
+	return DistinctBy(seq, func(v T) T { return v })
+}
+
+// DistinctBy returns the values of seq whose key has not been seen before
+func DistinctBy[T any, K comparable](seq iter.Seq[T], key func(T) K) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		seen := make(map[K]struct{})
+		for v := range seq {
+			k := key(v)
+			if _, ok := seen[k]; ok {
+				continue
+			}
+			seen[k] = struct{}{}
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Chunk groups the values of seq into slices of size, the last one may be shorter
+func Chunk[T any](seq iter.Seq[T], size int) iter.Seq[[]T] {
+	return func(yield func([]T) bool) {
+		if size <= 0 {
+			size = 1
+		}
+		chunk := make([]T, 0, size)
+		for v := range seq {
+			chunk = append(chunk, v)
+			if len(chunk) == size {
+				if !yield(chunk) {
+					return
+				}
+				chunk = make([]T, 0, size)
+			}
+		}
+		if len(chunk) > 0 {
+			yield(chunk)
+		}
+	}
+}
+
+// Enumerate pairs every value of seq with its zero-based index
+func Enumerate[T any](seq iter.Seq[T]) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for v := range seq {
+			if !yield(i, v) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+// Reduce folds seq into a single value starting from init
+func Reduce[T, R any](seq iter.Seq[T], init R, fn func(acc R, v T) R) R {
+	acc := init
+	for v := range seq {
+		acc = fn(acc, v)
+	}
+	return acc
+}
+
+// Count consumes seq and returns the number of values
+func Count[T any](seq iter.Seq[T]) int {
+	n := 0
+	for range seq {
+		n++
+	}
+	return n
+}
+
+// First returns the first value of seq
+func First[T any](seq iter.Seq[T]) (T, bool) {
+	for v := range seq {
+		return v, true
+	}
+	var zero T
+	return zero, false
+}
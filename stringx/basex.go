@@ -0,0 +1,169 @@
+package stringx
+
+import (
+	"fmt"
+	"math"
+)
+
+const (
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	// Bitcoin alphabet, excludes visually ambiguous 0, O, I and l
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+)
+
+var (
+	base62 = newBaseX(base62Alphabet)
+	base58 = newBaseX(base58Alphabet)
+)
+
+// Base62Encode encodes b using the 0-9A-Za-z alphabet, leading zero bytes are preserved
+func Base62Encode(b []byte) string {
+	return base62.encode(b)
+}
+
+// Base62Decode decodes a string produced by Base62Encode
+func Base62Decode(s string) ([]byte, error) {
+	return base62.decode(s)
+}
+
+// Base62EncodeUint64 encodes n using the 0-9A-Za-z alphabet
+func Base62EncodeUint64(n uint64) string {
+	return base62.encodeUint64(n)
+}
+
+// Base62DecodeUint64 decodes a string produced by Base62EncodeUint64
+func Base62DecodeUint64(s string) (uint64, error) {
+	return base62.decodeUint64(s)
+}
+
+// Base58Encode encodes b using the Bitcoin Base58 alphabet, leading zero bytes are preserved
+func Base58Encode(b []byte) string {
+	return base58.encode(b)
+}
+
+// Base58Decode decodes a string produced by Base58Encode
+func Base58Decode(s string) ([]byte, error) {
+	return base58.decode(s)
+}
+
+// Base58EncodeUint64 encodes n using the Bitcoin Base58 alphabet
+func Base58EncodeUint64(n uint64) string {
+	return base58.encodeUint64(n)
+}
+
+// Base58DecodeUint64 decodes a string produced by Base58EncodeUint64
+func Base58DecodeUint64(s string) (uint64, error) {
+	return base58.decodeUint64(s)
+}
+
+// baseX implements arbitrary-radix encoding over a fixed alphabet
+type baseX struct {
+	alphabet string
+	radix    int
+	index    [256]int // Reverse lookup table, -1 for characters outside the alphabet
+}
+
+func newBaseX(alphabet string) *baseX {
+	bx := &baseX{alphabet: alphabet, radix: len(alphabet)}
+	for i := range bx.index {
+		bx.index[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		bx.index[alphabet[i]] = i
+	}
+	return bx
+}
+
+// encode treats b as a big-endian number and converts it to the target radix
+func (bx *baseX) encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+	// Digits in the target radix, little-endian
+	digits := make([]byte, 0, len(b)*138/100+1)
+	for _, c := range b[zeros:] {
+		carry := int(c)
+		for i := range digits {
+			carry += int(digits[i]) << 8
+			digits[i] = byte(carry % bx.radix)
+			carry /= bx.radix
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%bx.radix))
+			carry /= bx.radix
+		}
+	}
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = bx.alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = bx.alphabet[d]
+	}
+	return string(out)
+}
+
+// decode reverses encode, rejecting any character outside the alphabet
+func (bx *baseX) decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == bx.alphabet[0] {
+		zeros++
+	}
+	// Bytes of the decoded number, little-endian
+	bytes := make([]byte, 0, len(s))
+	for i := zeros; i < len(s); i++ {
+		carry := bx.index[s[i]]
+		if carry < 0 {
+			return nil, fmt.Errorf("invalid base%d character %q at position %d", bx.radix, s[i], i)
+		}
+		for j := range bytes {
+			carry += int(bytes[j]) * bx.radix
+			bytes[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			bytes = append(bytes, byte(carry))
+			carry >>= 8
+		}
+	}
+	out := make([]byte, zeros+len(bytes))
+	for i, c := range bytes {
+		out[len(out)-1-i] = c
+	}
+	return out, nil
+}
+
+func (bx *baseX) encodeUint64(n uint64) string {
+	if n == 0 {
+		return bx.alphabet[:1]
+	}
+	var buf [64]byte
+	i := len(buf)
+	radix := uint64(bx.radix)
+	for n > 0 {
+		i--
+		buf[i] = bx.alphabet[n%radix]
+		n /= radix
+	}
+	return string(buf[i:])
+}
+
+func (bx *baseX) decodeUint64(s string) (uint64, error) {
+	if s == "" {
+		return 0, fmt.Errorf("empty base%d string", bx.radix)
+	}
+	radix := uint64(bx.radix)
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := bx.index[s[i]]
+		if d < 0 {
+			return 0, fmt.Errorf("invalid base%d character %q at position %d", bx.radix, s[i], i)
+		}
+		if n > (math.MaxUint64-uint64(d))/radix {
+			return 0, fmt.Errorf("base%d value %q overflows uint64", bx.radix, s)
+		}
+		n = n*radix + uint64(d)
+	}
+	return n, nil
+}
@@ -0,0 +1,72 @@
+package stringx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// SnakeCase converts s to snake_case, e.g. "HTTPServerID" -> "http_server_id"
+func SnakeCase(s string) string {
+	return joinCase(caseWords(s), "_", strings.ToLower)
+}
+
+// KebabCase converts s to kebab-case, e.g. "userName" -> "user-name"
+func KebabCase(s string) string {
+	return joinCase(caseWords(s), "-", strings.ToLower)
+}
+
+// CamelCase converts s to camelCase, e.g. "user_name" -> "userName"
+func CamelCase(s string) string {
+	words := caseWords(s)
+	if len(words) == 0 {
+		return ""
+	}
+	return strings.ToLower(words[0]) + joinCase(words[1:], "", capitalize)
+}
+
+// PascalCase converts s to PascalCase, e.g. "user-name" -> "UserName"
+func PascalCase(s string) string {
+	return joinCase(caseWords(s), "", capitalize)
+}
+
+// Capitalize uppercases the first letter of s
+func Capitalize(s string) string {
+	for i, r := range s {
+		return string(unicode.ToUpper(r)) + s[i+len(string(r)):]
+	}
+	return s
+}
+
+func capitalize(s string) string {
+	return Capitalize(strings.ToLower(s))
+}
+
+func joinCase(words []string, sep string, fn func(string) string) string {
+	for i, w := range words {
+		words[i] = fn(w)
+	}
+	return strings.Join(words, sep)
+}
+
+// caseWords splits s on separators and case boundaries, keeping acronyms together:
+// "HTTPServer_v2" -> ["HTTP", "Server", "v2"]
+func caseWords(s string) []string {
+	var words []string
+	for _, part := range splitWords(s) {
+		runes := []rune(part)
+		start := 0
+		for i := 1; i < len(runes); i++ {
+			prev, cur := runes[i-1], runes[i]
+			boundary := unicode.IsLower(prev) && unicode.IsUpper(cur) ||
+				// End of an acronym: "HTTPServer" splits before "Server"
+				unicode.IsUpper(prev) && unicode.IsUpper(cur) && i+1 < len(runes) && unicode.IsLower(runes[i+1]) ||
+				unicode.IsDigit(prev) != unicode.IsDigit(cur) && unicode.IsUpper(cur)
+			if boundary {
+				words = append(words, string(runes[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(runes[start:]))
+	}
+	return words
+}
@@ -0,0 +1,48 @@
+package stringx
+
+// ChunkByLen splits s into chunks of n runes each, the last chunk may be shorter
+// Multi-byte characters are never split, e.g. ChunkByLen("中文abc", 2) -> ["中文", "ab", "c"]
+func ChunkByLen(s string, n int) []string {
+	if n <= 0 || s == "" {
+		return nil
+	}
+	var chunks []string
+	count, start := 0, 0
+	for i := range s {
+		if count == n {
+			chunks = append(chunks, s[start:i])
+			start, count = i, 0
+		}
+		count++
+	}
+	return append(chunks, s[start:])
+}
+
+// SplitByLens splits s into consecutive parts whose rune lengths are given by lens
+// If s runs out early the remaining parts are empty; any text left over after the
+// last length is returned as an extra trailing part
+// e.g. SplitByLens("20240131XYZ", 4, 2, 2) -> ["2024", "01", "31", "XYZ"]
+func SplitByLens(s string, lens ...int) []string {
+	parts := make([]string, 0, len(lens)+1)
+	rest := s
+	for _, l := range lens {
+		end := len(rest)
+		count := 0
+		for i := range rest {
+			if count == l {
+				end = i
+				break
+			}
+			count++
+		}
+		if l <= 0 {
+			end = 0
+		}
+		parts = append(parts, rest[:end])
+		rest = rest[end:]
+	}
+	if rest != "" {
+		parts = append(parts, rest)
+	}
+	return parts
+}
@@ -0,0 +1,53 @@
+package stringx
+
+import "strings"
+
+// ContainsAny reports whether s contains at least one of subs
+func ContainsAny(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if strings.Contains(s, sub) {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsAll reports whether s contains every one of subs
+func ContainsAll(s string, subs ...string) bool {
+	for _, sub := range subs {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
+
+// HasAnyPrefix reports whether s begins with at least one of prefixes
+func HasAnyPrefix(s string, prefixes ...string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAnySuffix reports whether s ends with at least one of suffixes
+func HasAnySuffix(s string, suffixes ...string) bool {
+	for _, suffix := range suffixes {
+		if strings.HasSuffix(s, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// EqualsAnyFold reports whether s equals one of candidates under Unicode case-folding
+func EqualsAnyFold(s string, candidates ...string) bool {
+	for _, c := range candidates {
+		if strings.EqualFold(s, c) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,65 @@
+package stringx
+
+import (
+	"os"
+	"strings"
+)
+
+// Expand replaces $VAR, ${VAR} and ${VAR:-default} in s using lookup
+// The default is used when the variable is unset or empty; "$$" produces a literal '$'
+// Unknown variables without a default expand to an empty string, like a POSIX shell
+func Expand(s string, lookup func(string) (string, bool)) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 >= len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+		next := s[i+1]
+		switch {
+		case next == '$':
+			// Escaped dollar sign
+			sb.WriteByte('$')
+			i++
+		case next == '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				// Unterminated, keep the rest verbatim
+				sb.WriteString(s[i:])
+				return sb.String()
+			}
+			inner := s[i+2 : i+2+end]
+			name, def, hasDef := strings.Cut(inner, ":-")
+			val, ok := lookup(name)
+			if hasDef && (!ok || val == "") {
+				val = def
+			}
+			sb.WriteString(val)
+			i += 2 + end
+		case isVarNameByte(next, true):
+			j := i + 1
+			for j < len(s) && isVarNameByte(s[j], j == i+1) {
+				j++
+			}
+			val, _ := lookup(s[i+1 : j])
+			sb.WriteString(val)
+			i = j - 1
+		default:
+			sb.WriteByte('$')
+		}
+	}
+	return sb.String()
+}
+
+// ExpandEnv expands s using the process environment, see Expand
+func ExpandEnv(s string) string {
+	return Expand(s, os.LookupEnv)
+}
+
+// isVarNameByte reports whether c may appear in a bare $VAR name at the given position
+func isVarNameByte(c byte, first bool) bool {
+	if c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') {
+		return true
+	}
+	return !first && '0' <= c && c <= '9'
+}
@@ -0,0 +1,38 @@
+package stringx
+
+import (
+	"github.com/clipperhouse/uax29/v2/graphemes"
+	"strings"
+)
+
+// Graphemes splits s into user-perceived characters (extended grapheme clusters)
+// Emoji sequences and combining marks stay together, e.g. "👨‍👩‍👧" is a single element
+func Graphemes(s string) []string {
+	var gs []string
+	iter := graphemes.FromString(s)
+	for iter.Next() {
+		gs = append(gs, iter.Value())
+	}
+	return gs
+}
+
+// LenGraphemes returns the number of grapheme clusters in s
+func LenGraphemes(s string) int {
+	n := 0
+	iter := graphemes.FromString(s)
+	for iter.Next() {
+		n++
+	}
+	return n
+}
+
+// Reverse reverses s by grapheme cluster, so emoji and combined characters are not mangled
+func Reverse(s string) string {
+	gs := Graphemes(s)
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := len(gs) - 1; i >= 0; i-- {
+		sb.WriteString(gs[i])
+	}
+	return sb.String()
+}
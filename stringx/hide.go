@@ -0,0 +1,87 @@
+package stringx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Hide masks s with '*', keeping keepStart leading and keepEnd trailing runes visible
+// If s is too short to keep both ends, everything after the first rune is masked
+func Hide(s string, keepStart, keepEnd int) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	if keepStart < 0 {
+		keepStart = 0
+	}
+	if keepEnd < 0 {
+		keepEnd = 0
+	}
+	if keepStart+keepEnd >= len(r) {
+		keepStart, keepEnd = 1, 0
+		if len(r) == 1 {
+			keepStart = 0
+		}
+	}
+	for i := keepStart; i < len(r)-keepEnd; i++ {
+		r[i] = '*'
+	}
+	return string(r)
+}
+
+// HideName masks a personal name
+// Chinese names keep the first and last character ("张*", "欧阳*锋"),
+// Western names keep the first letter of each word ("J*** S****")
+func HideName(name string) string {
+	name = strings.TrimSpace(name)
+	r := []rune(name)
+	if len(r) == 0 {
+		return name
+	}
+	if unicode.Is(unicode.Han, r[0]) {
+		if len(r) <= 2 {
+			return Hide(name, 1, 0)
+		}
+		return Hide(name, 1, 1)
+	}
+	words := strings.Fields(name)
+	for i, w := range words {
+		words[i] = Hide(w, 1, 0)
+	}
+	return strings.Join(words, " ")
+}
+
+// addressCityMarkers end the province/city part of a Chinese address, checked in order
+var addressCityMarkers = []string{"市", "自治州", "州", "盟", "地区"}
+
+// HideAddress keeps the province and city of a Chinese address and masks the detail part
+// with a fixed "****" so the original length is not revealed,
+// e.g. "广东省深圳市南山区科技园1号" -> "广东省深圳市****"
+func HideAddress(addr string) string {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return addr
+	}
+	for _, marker := range addressCityMarkers {
+		if idx := strings.Index(addr, marker); idx >= 0 {
+			end := idx + len(marker)
+			if end == len(addr) {
+				return addr
+			}
+			return addr[:end] + "****"
+		}
+	}
+	// No recognizable city, keep a short prefix only
+	r := []rune(addr)
+	if len(r) <= 6 {
+		return Hide(addr, 1, 0)
+	}
+	return string(r[:6]) + "****"
+}
+
+// HidePlate masks a vehicle license plate, keeping the region prefix and the last character,
+// e.g. "京A12345" -> "京A****5", "粤BD12345" -> "粤B*****5"
+func HidePlate(plate string) string {
+	return Hide(strings.ToUpper(strings.TrimSpace(plate)), 2, 1)
+}
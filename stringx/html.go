@@ -0,0 +1,40 @@
+package stringx
+
+import (
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	// <script> and <style> blocks are removed together with their content
+	htmlBlockRegexp = regexp.MustCompile(`(?is)<(script|style)\b[^>]*>.*?</(script|style)\s*>`)
+	// Comments, doctype and start/end tags; a bare '<' not followed by a tag name is kept
+	htmlTagRegexp = regexp.MustCompile(`(?s)<!--.*?-->|</?[a-zA-Z][^>]*>|<![^>]*>`)
+)
+
+// StripTags removes HTML tags, comments and script/style blocks from s, leaving the text content
+// Entities are left untouched, use UnescapeHTML to decode them
+func StripTags(s string) string {
+	s = htmlBlockRegexp.ReplaceAllString(s, " ")
+	return htmlTagRegexp.ReplaceAllString(s, "")
+}
+
+// EscapeHTML escapes <, >, &, ' and " so s can be embedded in HTML
+func EscapeHTML(s string) string {
+	return html.EscapeString(s)
+}
+
+// UnescapeHTML decodes HTML entities such as &lt; and &#39;
+func UnescapeHTML(s string) string {
+	return html.UnescapeString(s)
+}
+
+// SanitizeText turns user-generated HTML into a safe single-line snippet:
+// tags are stripped, entities decoded, whitespace collapsed, and the result re-escaped for HTML output
+func SanitizeText(s string) string {
+	s = UnescapeHTML(StripTags(s))
+	// A second strip catches tags that were smuggled in as entities (&lt;script&gt;)
+	s = StripTags(s)
+	return EscapeHTML(strings.Join(strings.Fields(s), " "))
+}
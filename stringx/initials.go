@@ -0,0 +1,47 @@
+package stringx
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Initials returns up to max uppercase initials of name, for avatar placeholders
+// Western names use the first letter of each word, e.g. Initials("john ronald tolkien", 2) -> "JR"
+// Chinese names return the surname character, e.g. Initials("张三", 2) -> "张"
+func Initials(name string, max int) string {
+	name = strings.TrimSpace(name)
+	if name == "" || max <= 0 {
+		return ""
+	}
+	first := []rune(name)[0]
+	if unicode.Is(unicode.Han, first) {
+		return string(first)
+	}
+	var sb strings.Builder
+	count := 0
+	for _, word := range splitWords(name) {
+		if count == max {
+			break
+		}
+		sb.WriteRune(unicode.ToUpper([]rune(word)[0]))
+		count++
+	}
+	return sb.String()
+}
+
+// Acronym builds an uppercase acronym from the first letter of each word in phrase,
+// e.g. Acronym("Portable Network Graphics") -> "PNG", Acronym("as-soon-as possible") -> "ASAP"
+func Acronym(phrase string) string {
+	var sb strings.Builder
+	for _, word := range splitWords(phrase) {
+		sb.WriteRune(unicode.ToUpper([]rune(word)[0]))
+	}
+	return sb.String()
+}
+
+// splitWords splits s on whitespace, hyphens, underscores and dots, dropping empty words
+func splitWords(s string) []string {
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == '-' || r == '_' || r == '.'
+	})
+}
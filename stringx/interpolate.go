@@ -0,0 +1,89 @@
+package stringx
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MissingKeyPolicy decides how Interpolate treats placeholders without a value
+type MissingKeyPolicy int
+
+const (
+	MissingKeyError MissingKeyPolicy = iota // Return an error (default)
+	MissingKeyKeep                          // Keep the placeholder as-is in the output
+	MissingKeyEmpty                         // Replace the placeholder with an empty string
+)
+
+// InterpolateOptions configures Interpolate
+type InterpolateOptions struct {
+	Open    string           // Opening delimiter, defaults to "${"
+	Close   string           // Closing delimiter, defaults to "}"
+	Missing MissingKeyPolicy // Policy for keys that are absent from vars
+}
+
+// InterpolateOption functional configuration type for Interpolate
+type InterpolateOption func(*InterpolateOptions)
+
+// WithDelims sets the placeholder delimiters, e.g. WithDelims("{{", "}}")
+func WithDelims(open, close string) InterpolateOption {
+	return func(o *InterpolateOptions) {
+		o.Open = open
+		o.Close = close
+	}
+}
+
+// WithMissingKey sets the policy for placeholders whose key is not found
+func WithMissingKey(p MissingKeyPolicy) InterpolateOption {
+	return func(o *InterpolateOptions) { o.Missing = p }
+}
+
+// Interpolate replaces named placeholders in s with values from vars
+// A placeholder may carry a default value used when the key is missing: ${name:-guest}
+// Values are rendered with fmt.Sprint
+func Interpolate(s string, vars map[string]any, opts ...InterpolateOption) (string, error) {
+	o := &InterpolateOptions{Open: "${", Close: "}"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.Open == "" || o.Close == "" {
+		return "", fmt.Errorf("interpolate: delimiters cannot be empty")
+	}
+
+	var sb strings.Builder
+	for {
+		start := strings.Index(s, o.Open)
+		if start < 0 {
+			break
+		}
+		end := strings.Index(s[start+len(o.Open):], o.Close)
+		if end < 0 {
+			break // Unterminated placeholder, emit the rest verbatim
+		}
+		end += start + len(o.Open)
+
+		sb.WriteString(s[:start])
+		inner := s[start+len(o.Open) : end]
+		placeholder := s[start : end+len(o.Close)]
+		s = s[end+len(o.Close):]
+
+		key, def, hasDef := strings.Cut(inner, ":-")
+		key = strings.TrimSpace(key)
+		if v, ok := vars[key]; ok {
+			sb.WriteString(fmt.Sprint(v))
+			continue
+		}
+		if hasDef {
+			sb.WriteString(def)
+			continue
+		}
+		switch o.Missing {
+		case MissingKeyKeep:
+			sb.WriteString(placeholder)
+		case MissingKeyEmpty:
+		default:
+			return "", fmt.Errorf("interpolate: missing value for key %q", key)
+		}
+	}
+	sb.WriteString(s)
+	return sb.String(), nil
+}
@@ -0,0 +1,82 @@
+package stringx
+
+import (
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Comma formats an integer with ',' as thousands separator, e.g. 1234567 -> "1,234,567"
+func Comma(n int64) string {
+	s := strconv.FormatInt(n, 10)
+	if n < 0 {
+		return "-" + groupDigits(s[1:], ",")
+	}
+	return groupDigits(s, ",")
+}
+
+// FormatFloat formats f rounded to decimals places, grouping the integer part with thousandSep
+// and separating the fraction with decimalSep, e.g. FormatFloat(1234.5, 2, ",", ".") -> "1,234.50"
+func FormatFloat(f float64, decimals int, thousandSep, decimalSep string) string {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	if decimals < 0 {
+		decimals = 0
+	}
+	s := strconv.FormatFloat(math.Abs(f), 'f', decimals, 64)
+	intPart, fracPart, _ := strings.Cut(s, ".")
+
+	var sb strings.Builder
+	// Avoid rendering "-0.00" for values that round to zero
+	if f < 0 && strings.Trim(s, "0.") != "" {
+		sb.WriteByte('-')
+	}
+	sb.WriteString(groupDigits(intPart, thousandSep))
+	if fracPart != "" {
+		sb.WriteString(decimalSep)
+		sb.WriteString(fracPart)
+	}
+	return sb.String()
+}
+
+// Ordinal returns n with its English ordinal suffix, e.g. 1 -> "1st", 12 -> "12th", 23 -> "23rd"
+func Ordinal(n int) string {
+	suffix := "th"
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+	switch abs % 100 {
+	case 11, 12, 13:
+	default:
+		switch abs % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// groupDigits inserts sep between every three digits of an unsigned digit string
+func groupDigits(digits, sep string) string {
+	if len(digits) <= 3 || sep == "" {
+		return digits
+	}
+	var sb strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		sb.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if sb.Len() > 0 {
+			sb.WriteString(sep)
+		}
+		sb.WriteString(digits[i : i+3])
+	}
+	return sb.String()
+}
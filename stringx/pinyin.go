@@ -0,0 +1,72 @@
+package stringx
+
+import (
+	"github.com/mozillazg/go-pinyin"
+	"strings"
+	"unicode"
+)
+
+// PinyinOptions configures Pinyin
+type PinyinOptions struct {
+	Tone        bool // Keep tone marks, e.g. zhōng
+	FirstLetter bool // Return only the first letter of each syllable, e.g. z
+}
+
+// PinyinOption functional configuration type for Pinyin
+type PinyinOption func(*PinyinOptions)
+
+// WithTone keeps tone marks on the vowels
+func WithTone() PinyinOption {
+	return func(o *PinyinOptions) { o.Tone = true }
+}
+
+// WithFirstLetter returns only the first letter of each syllable (takes precedence over WithTone)
+func WithFirstLetter() PinyinOption {
+	return func(o *PinyinOptions) { o.FirstLetter = true }
+}
+
+// Pinyin converts Chinese text to pinyin, one element per Chinese character
+// Runs of non-Chinese characters are kept as single elements, whitespace is dropped,
+// e.g. Pinyin("张三 abc") -> ["zhang", "san", "abc"]
+func Pinyin(s string, opts ...PinyinOption) []string {
+	o := &PinyinOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	args := pinyin.NewArgs()
+	switch {
+	case o.FirstLetter:
+		args.Style = pinyin.FirstLetter
+	case o.Tone:
+		args.Style = pinyin.Tone
+	default:
+		args.Style = pinyin.Normal
+	}
+
+	var (
+		result []string
+		other  strings.Builder // Pending run of non-Chinese characters
+	)
+	flush := func() {
+		if other.Len() > 0 {
+			result = append(result, other.String())
+			other.Reset()
+		}
+	}
+	for _, r := range s {
+		if unicode.Is(unicode.Han, r) {
+			if py := pinyin.SinglePinyin(r, args); len(py) > 0 {
+				flush()
+				result = append(result, py[0])
+				continue
+			}
+		}
+		if unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		other.WriteRune(r)
+	}
+	flush()
+	return result
+}
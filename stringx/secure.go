@@ -0,0 +1,14 @@
+package stringx
+
+import "crypto/subtle"
+
+// SecureEqual compares a and b in constant time, for tokens, signatures and other secrets
+// The comparison time depends only on the lengths, never on the content
+func SecureEqual(a, b string) bool {
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// WipeBytes overwrites b with zeros so secrets don't linger in memory after use
+func WipeBytes(b []byte) {
+	clear(b)
+}
@@ -1,7 +1,7 @@
 package stringx
 
 import (
-	"github.com/samber/lo"
+	"github.com/chihqiang/gox/slicex"
 	"strings"
 )
 
@@ -22,5 +22,5 @@ func SplitUniq(ss []string, sep string) []string {
 	for _, s := range ss {
 		sp = append(sp, Split(s, sep)...)
 	}
-	return lo.Uniq(sp)
+	return slicex.Uniq(sp)
 }
@@ -0,0 +1,93 @@
+package stringx
+
+import (
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	emailRegexp   = regexp.MustCompile(`^[a-zA-Z0-9.!#$%&'*+/=?^_{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)+$`)
+	phoneCNRegexp = regexp.MustCompile(`^1[3-9]\d{9}$`)
+	idCardRegexp  = regexp.MustCompile(`^\d{17}[\dXx]$`)
+
+	// Weights and check codes defined by GB 11643-1999 for 18-digit ID card numbers
+	idCardWeights    = []int{7, 9, 10, 5, 8, 4, 2, 1, 6, 3, 7, 9, 10, 5, 8, 4, 2}
+	idCardCheckCodes = []byte("10X98765432")
+)
+
+// IsEmail reports whether s is a syntactically valid email address
+func IsEmail(s string) bool {
+	return len(s) <= 254 && emailRegexp.MatchString(s)
+}
+
+// IsPhoneCN reports whether s is a valid mainland China mobile phone number (11 digits)
+func IsPhoneCN(s string) bool {
+	return phoneCNRegexp.MatchString(s)
+}
+
+// IsIDCard reports whether s is a valid 18-digit mainland China resident ID card number
+// Both the birth date and the trailing check code are verified
+func IsIDCard(s string) bool {
+	if !idCardRegexp.MatchString(s) {
+		return false
+	}
+	// Birth date must be a real date that is not in the future
+	birth, err := time.Parse("20060102", s[6:14])
+	if err != nil || birth.After(time.Now()) {
+		return false
+	}
+	sum := 0
+	for i, w := range idCardWeights {
+		sum += int(s[i]-'0') * w
+	}
+	return idCardCheckCodes[sum%11] == byte(strings.ToUpper(s[17:])[0])
+}
+
+// IsURL reports whether s is an absolute URL with a scheme and host
+func IsURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	return u.Scheme != "" && u.Host != ""
+}
+
+// IsIPv4 reports whether s is a valid IPv4 address
+func IsIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+// IsIPv6 reports whether s is a valid IPv6 address
+func IsIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6()
+}
+
+// IsBankCard reports whether s is a 12-19 digit bank card number passing the Luhn check
+func IsBankCard(s string) bool {
+	if len(s) < 12 || len(s) > 19 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
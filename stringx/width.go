@@ -0,0 +1,44 @@
+package stringx
+
+import (
+	"github.com/mattn/go-runewidth"
+	"strings"
+)
+
+// Width returns the display width of s in terminal columns
+// Full-width CJK characters and most emoji count as 2 columns
+func Width(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// TruncateWidth shortens s to at most cols display columns, appending suffix when truncated
+// The suffix counts toward cols, e.g. TruncateWidth("中文字符串", 7, "...") -> "中文..."
+func TruncateWidth(s string, cols int, suffix string) string {
+	return runewidth.Truncate(s, cols, suffix)
+}
+
+// PadRightWidth pads s with spaces on the right up to cols display columns
+func PadRightWidth(s string, cols int) string {
+	if n := cols - Width(s); n > 0 {
+		return s + strings.Repeat(" ", n)
+	}
+	return s
+}
+
+// PadLeftWidth pads s with spaces on the left up to cols display columns
+func PadLeftWidth(s string, cols int) string {
+	if n := cols - Width(s); n > 0 {
+		return strings.Repeat(" ", n) + s
+	}
+	return s
+}
+
+// CenterWidth pads s with spaces on both sides to center it within cols display columns
+// When the padding is odd, the extra space goes to the right
+func CenterWidth(s string, cols int) string {
+	n := cols - Width(s)
+	if n <= 0 {
+		return s
+	}
+	return strings.Repeat(" ", n/2) + s + strings.Repeat(" ", n-n/2)
+}
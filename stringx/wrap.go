@@ -0,0 +1,80 @@
+package stringx
+
+import (
+	"github.com/mattn/go-runewidth"
+	"strings"
+)
+
+// Wrap wraps s on word boundaries so that no line exceeds width display columns
+// Full-width characters count as 2 columns; words wider than width are broken apart
+// Existing line breaks are preserved
+func Wrap(s string, width int) string {
+	return WrapIndent(s, width, "")
+}
+
+// WrapIndent wraps s like Wrap and prefixes every resulting line with indent
+// The indent counts toward the line width
+func WrapIndent(s string, width int, indent string) string {
+	avail := width - runewidth.StringWidth(indent)
+	if avail < 1 {
+		avail = 1
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		for _, line := range wrapParagraph(paragraph, avail) {
+			lines = append(lines, indent+line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// wrapParagraph wraps a single line of text that contains no line breaks
+func wrapParagraph(s string, width int) []string {
+	words := strings.Fields(s)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	var (
+		lines []string
+		cur   strings.Builder
+		curW  int
+	)
+	flush := func() {
+		lines = append(lines, cur.String())
+		cur.Reset()
+		curW = 0
+	}
+	for _, word := range words {
+		wordW := runewidth.StringWidth(word)
+		// Word fits on the current line (with a separating space if needed)
+		if curW > 0 && curW+1+wordW <= width {
+			cur.WriteByte(' ')
+			cur.WriteString(word)
+			curW += 1 + wordW
+			continue
+		}
+		if curW > 0 {
+			flush()
+		}
+		// Hard-break words that are wider than a whole line
+		for wordW > width {
+			head := runewidth.Truncate(word, width, "")
+			if head == "" {
+				// A single rune wider than the line, emit it on its own
+				r := []rune(word)
+				head = string(r[0])
+			}
+			lines = append(lines, head)
+			word = word[len(head):]
+			wordW = runewidth.StringWidth(word)
+		}
+		cur.WriteString(word)
+		curW = wordW
+	}
+	if curW > 0 {
+		flush()
+	}
+	return lines
+}
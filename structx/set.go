@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // SetDefault initializes the DefaultSetter and applies default values
@@ -84,9 +85,17 @@ func (sd *DefaultSetter) setString(field reflect.Value, defaultTag string) error
 	return nil
 }
 
-// setInt handles default value assignment for signed integer fields
+// setInt handles default value assignment for signed integer fields, time.Duration accepts "5s"
 func (sd *DefaultSetter) setInt(field reflect.Value, defaultTag string) error {
 	if field.Int() == 0 && defaultTag != "" {
+		if field.Type() == reflect.TypeOf(time.Duration(0)) {
+			d, err := time.ParseDuration(defaultTag)
+			if err != nil {
+				return fmt.Errorf("invalid duration value '%s': %w", defaultTag, err)
+			}
+			field.SetInt(int64(d))
+			return nil
+		}
 		val, err := strconv.ParseInt(defaultTag, 10, 64)
 		if err != nil {
 			return fmt.Errorf("invalid int value '%s': %w", defaultTag, err)
@@ -0,0 +1,69 @@
+package syncx
+
+import "sync"
+
+// keyedLock is a mutex shared by the holders and waiters of one key
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// KeyedMutex provides one mutex per key, locks are created on demand and released when unused
+// The zero value is ready to use
+type KeyedMutex[K comparable] struct {
+	mu    sync.Mutex
+	locks map[K]*keyedLock
+}
+
+// LockKey locks key and returns the function that unlocks it
+//
+//	unlock := km.LockKey(userID)
+//	defer unlock()
+func (m *KeyedMutex[K]) LockKey(key K) (unlock func()) {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = make(map[K]*keyedLock)
+	}
+	l, ok := m.locks[key]
+	if !ok {
+		l = &keyedLock{}
+		m.locks[key] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	l.mu.Lock()
+	return m.unlocker(key, l)
+}
+
+// TryLockKey locks key if no one holds or waits for it and reports whether it succeeded
+func (m *KeyedMutex[K]) TryLockKey(key K) (unlock func(), ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.locks == nil {
+		m.locks = make(map[K]*keyedLock)
+	}
+	if _, held := m.locks[key]; held {
+		return nil, false
+	}
+	l := &keyedLock{refs: 1}
+	l.mu.Lock()
+	m.locks[key] = l
+	return m.unlocker(key, l), true
+}
+
+// unlocker returns an idempotent unlock function releasing l and dropping it once unused
+func (m *KeyedMutex[K]) unlocker(key K, l *keyedLock) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Unlock()
+			m.mu.Lock()
+			l.refs--
+			if l.refs == 0 {
+				delete(m.locks, key)
+			}
+			m.mu.Unlock()
+		})
+	}
+}
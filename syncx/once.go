@@ -0,0 +1,94 @@
+package syncx
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// OnceValue returns a function that calls fn until it succeeds and then caches the value
+// Unlike sync.OnceValues, errors are not cached, so a failed initialization is retried
+// on the next call. Concurrent callers are serialized while fn runs
+func OnceValue[T any](fn func() (T, error)) func() (T, error) {
+	var (
+		mu    sync.Mutex
+		done  atomic.Bool
+		value T
+	)
+	return func() (T, error) {
+		if done.Load() {
+			return value, nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if done.Load() {
+			return value, nil
+		}
+		v, err := fn()
+		if err != nil {
+			return v, err
+		}
+		value = v
+		done.Store(true)
+		return value, nil
+	}
+}
+
+// OnceError returns a function that calls fn until it returns nil, then always returns nil
+func OnceError(fn func() error) func() error {
+	once := OnceValue(func() (struct{}, error) { return struct{}{}, fn() })
+	return func() error {
+		_, err := once()
+		return err
+	}
+}
+
+// Value is a typed wrapper around atomic.Pointer, the zero value holds the zero T
+type Value[T any] struct {
+	p atomic.Pointer[T]
+}
+
+// NewValue creates a Value holding v
+func NewValue[T any](v T) *Value[T] {
+	val := &Value[T]{}
+	val.Store(v)
+	return val
+}
+
+// Load returns the current value
+func (v *Value[T]) Load() T {
+	if p := v.p.Load(); p != nil {
+		return *p
+	}
+	var zero T
+	return zero
+}
+
+// Store sets the value
+func (v *Value[T]) Store(val T) {
+	v.p.Store(&val)
+}
+
+// Swap sets the value and returns the previous one
+func (v *Value[T]) Swap(val T) T {
+	if old := v.p.Swap(&val); old != nil {
+		return *old
+	}
+	var zero T
+	return zero
+}
+
+// Update atomically replaces the value with fn(current), retrying if another update raced it
+// fn may be called more than once and must not have side effects
+func (v *Value[T]) Update(fn func(T) T) T {
+	for {
+		old := v.p.Load()
+		var cur T
+		if old != nil {
+			cur = *old
+		}
+		next := fn(cur)
+		if v.p.CompareAndSwap(old, &next) {
+			return next
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package syncx
+
+import (
+	"fmt"
+	"sync"
+)
+
+// call is an in-flight or completed Do call
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+	dups  int
+}
+
+// Group deduplicates concurrent calls sharing the same key, the zero value is ready to use
+type Group[K comparable, V any] struct {
+	mu    sync.Mutex
+	calls map[K]*call[V]
+}
+
+// Do executes fn once for all concurrent callers with the same key and hands every caller the result
+// shared reports whether the result was given to more than one caller. If fn panics, the
+// panic is re-raised in the executing goroutine and the other callers receive an error
+func (g *Group[K, V]) Do(key K, fn func() (V, error)) (v V, err error, shared bool) {
+	g.mu.Lock()
+	if g.calls == nil {
+		g.calls = make(map[K]*call[V])
+	}
+	if c, ok := g.calls[key]; ok {
+		c.dups++
+		g.mu.Unlock()
+		c.wg.Wait()
+		return c.value, c.err, true
+	}
+	c := &call[V]{}
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.mu.Unlock()
+
+	func() {
+		defer func() {
+			r := recover()
+			if r != nil {
+				c.err = fmt.Errorf("syncx: singleflight call panicked: %v", r)
+			}
+			g.mu.Lock()
+			if g.calls[key] == c {
+				delete(g.calls, key)
+			}
+			g.mu.Unlock()
+			c.wg.Done()
+			if r != nil {
+				panic(r)
+			}
+		}()
+		c.value, c.err = fn()
+	}()
+
+	g.mu.Lock()
+	shared = c.dups > 0
+	g.mu.Unlock()
+	return c.value, c.err, shared
+}
+
+// Forget makes the next Do for key execute fn again instead of waiting for the in-flight call
+func (g *Group[K, V]) Forget(key K) {
+	g.mu.Lock()
+	delete(g.calls, key)
+	g.mu.Unlock()
+}
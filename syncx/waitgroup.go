@@ -0,0 +1,46 @@
+package syncx
+
+import (
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+)
+
+// WaitGroup runs functions in goroutines and collects their errors, the zero value is ready to use
+// A panic inside a function is recovered and reported as an error including the stack trace
+type WaitGroup struct {
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	errs []error
+}
+
+// Go runs fn in a new goroutine
+func (g *WaitGroup) Go(fn func() error) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				g.addError(fmt.Errorf("syncx: goroutine panicked: %v\n%s", r, debug.Stack()))
+			}
+		}()
+		if err := fn(); err != nil {
+			g.addError(err)
+		}
+	}()
+}
+
+// Wait blocks until every function has returned and joins their errors with errors.Join
+func (g *WaitGroup) Wait() error {
+	g.wg.Wait()
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return errors.Join(g.errs...)
+}
+
+func (g *WaitGroup) addError(err error) {
+	g.mu.Lock()
+	g.errs = append(g.errs, err)
+	g.mu.Unlock()
+}
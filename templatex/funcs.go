@@ -0,0 +1,169 @@
+package templatex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/chihqiang/gox/convx"
+	"github.com/chihqiang/gox/stringx"
+	"github.com/chihqiang/gox/timex"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FuncMap returns the default template functions, shared by text and html templates:
+//
+//	strings: upper lower title trim trimPrefix trimSuffix replace contains hasPrefix hasSuffix split join repeat
+//	case:    snake kebab camel pascal capitalize
+//	layout:  truncate wrap padLeft padRight hide initials
+//	numbers: comma formatFloat ordinal add sub mul div mod
+//	time:    now date ago duration
+//	logic:   default coalesce empty ternary
+//	data:    dict list toJSON toString toInt toFloat
+func FuncMap() map[string]any {
+	return map[string]any{
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"title":      title,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(sub, s string) bool { return strings.Contains(s, sub) },
+		"hasPrefix":  func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+		"hasSuffix":  func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+		"split":      func(sep, s string) []string { return strings.Split(s, sep) },
+		"join":       func(sep string, v any) string { return strings.Join(convx.ToStringSlice(v), sep) },
+		"repeat":     func(n int, s string) string { return strings.Repeat(s, n) },
+
+		"snake":      stringx.SnakeCase,
+		"kebab":      stringx.KebabCase,
+		"camel":      stringx.CamelCase,
+		"pascal":     stringx.PascalCase,
+		"capitalize": stringx.Capitalize,
+
+		"truncate": func(cols int, s string) string { return stringx.TruncateWidth(s, cols, "...") },
+		"wrap":     func(width int, s string) string { return stringx.Wrap(s, width) },
+		"padLeft":  func(cols int, s string) string { return stringx.PadLeftWidth(s, cols) },
+		"padRight": func(cols int, s string) string { return stringx.PadRightWidth(s, cols) },
+		"hide":     func(keepStart, keepEnd int, s string) string { return stringx.Hide(s, keepStart, keepEnd) },
+		"initials": func(s string) string { return stringx.Initials(s, 2) },
+
+		"comma":       func(v any) string { return stringx.Comma(convx.ToInt64(v)) },
+		"formatFloat": func(decimals int, v any) string { return stringx.FormatFloat(convx.ToFloat(v), decimals, ",", ".") },
+		"ordinal":     func(v any) string { return stringx.Ordinal(convx.ToInt(v)) },
+		"add":         func(a, b any) float64 { return convx.ToFloat(a) + convx.ToFloat(b) },
+		"sub":         func(a, b any) float64 { return convx.ToFloat(a) - convx.ToFloat(b) },
+		"mul":         func(a, b any) float64 { return convx.ToFloat(a) * convx.ToFloat(b) },
+		"div":         div,
+		"mod":         mod,
+
+		"now":      time.Now,
+		"date":     func(layout string, v any) string { return formatDate(layout, v) },
+		"ago":      func(v any) string { return timex.Ago(convx.ToTime(v)) },
+		"duration": func(d time.Duration) string { return timex.HumanizeDuration(d) },
+
+		"default":  defaultValue,
+		"coalesce": coalesce,
+		"empty":    empty,
+		"ternary": func(yes, no any, cond bool) any {
+			if cond {
+				return yes
+			}
+			return no
+		},
+
+		"dict":     dict,
+		"list":     func(v ...any) []any { return v },
+		"toJSON":   toJSON,
+		"toString": convx.ToString,
+		"toInt":    convx.ToInt64,
+		"toFloat":  convx.ToFloat,
+	}
+}
+
+// title uppercases the first letter of every space separated word
+func title(s string) string {
+	words := strings.Split(s, " ")
+	for i, w := range words {
+		words[i] = stringx.Capitalize(w)
+	}
+	return strings.Join(words, " ")
+}
+
+func div(a, b any) (float64, error) {
+	d := convx.ToFloat(b)
+	if d == 0 {
+		return 0, errors.New("templatex: division by zero")
+	}
+	return convx.ToFloat(a) / d, nil
+}
+
+func mod(a, b any) (int64, error) {
+	d := convx.ToInt64(b)
+	if d == 0 {
+		return 0, errors.New("templatex: division by zero")
+	}
+	return convx.ToInt64(a) % d, nil
+}
+
+// formatDate formats v (time.Time, timestamp or date string) with layout, "" for the zero time
+func formatDate(layout string, v any) string {
+	t := convx.ToTime(v)
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(layout)
+}
+
+// defaultValue returns def when v is empty, used as {{ .Name | default "anonymous" }}
+func defaultValue(def, v any) any {
+	if empty(v) {
+		return def
+	}
+	return v
+}
+
+// coalesce returns the first non-empty argument
+func coalesce(v ...any) any {
+	for _, x := range v {
+		if !empty(x) {
+			return x
+		}
+	}
+	return nil
+}
+
+// empty reports whether v is nil, zero, or an empty string, slice, map or channel
+func empty(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Array, reflect.Chan:
+		return rv.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return rv.IsNil()
+	default:
+		return rv.IsZero()
+	}
+}
+
+// dict builds a map from key/value pairs, handy for passing several values to a sub-template
+func dict(kv ...any) (map[string]any, error) {
+	if len(kv)%2 != 0 {
+		return nil, errors.New("templatex: dict expects an even number of arguments")
+	}
+	m := make(map[string]any, len(kv)/2)
+	for i := 0; i < len(kv); i += 2 {
+		m[fmt.Sprint(kv[i])] = kv[i+1]
+	}
+	return m, nil
+}
+
+func toJSON(v any) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,229 @@
+package templatex
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+)
+
+// Option registry configuration structure
+type Option struct {
+	Text       bool           // Use text/template instead of html/template (no contextual escaping)
+	Extensions []string       // File extensions to load, default .html, .tmpl and .gohtml
+	Funcs      map[string]any // Extra functions, merged over FuncMap
+	Reload     bool           // Re-parse templates on render when files changed, for development
+	LeftDelim  string         // Left action delimiter, default "{{"
+	RightDelim string         // Right action delimiter, default "}}"
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithText parses templates with text/template, e.g. for emails or config files
+func WithText() OptionFunc {
+	return func(o *Option) { o.Text = true }
+}
+
+// WithExtensions sets the file extensions loaded from the file system
+func WithExtensions(exts ...string) OptionFunc {
+	return func(o *Option) { o.Extensions = exts }
+}
+
+// WithFuncs adds template functions, overriding defaults with the same name
+func WithFuncs(funcs map[string]any) OptionFunc {
+	return func(o *Option) {
+		if o.Funcs == nil {
+			o.Funcs = make(map[string]any, len(funcs))
+		}
+		for k, v := range funcs {
+			o.Funcs[k] = v
+		}
+	}
+}
+
+// WithReload re-parses templates whenever a file changed, meant for development
+func WithReload(reload bool) OptionFunc {
+	return func(o *Option) { o.Reload = reload }
+}
+
+// WithDelims sets the action delimiters
+func WithDelims(left, right string) OptionFunc {
+	return func(o *Option) { o.LeftDelim, o.RightDelim = left, right }
+}
+
+// executor is the subset shared by html/template and text/template
+type executor interface {
+	ExecuteTemplate(w io.Writer, name string, data any) error
+}
+
+// Registry holds named templates loaded from a file system
+// Template names are slash separated paths relative to the root, e.g. "users/list.html";
+// every file can use templates defined in the others ({{ template "layout/base.html" . }})
+type Registry struct {
+	fsys    fs.FS
+	opt     Option
+	mu      sync.RWMutex
+	tmpl    executor
+	names   []string
+	version string // Fingerprint of file names, sizes and mod times at last load
+}
+
+// New loads every template file from fsys, typically an embed.FS or os.DirFS
+func New(fsys fs.FS, opts ...OptionFunc) (*Registry, error) {
+	r := &Registry{
+		fsys: fsys,
+		opt:  Option{Extensions: []string{".html", ".tmpl", ".gohtml"}},
+	}
+	for _, opt := range opts {
+		opt(&r.opt)
+	}
+	if err := r.Load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// NewFromDir loads every template file below dir
+func NewFromDir(dir string, opts ...OptionFunc) (*Registry, error) {
+	return New(os.DirFS(dir), opts...)
+}
+
+// Load (re)parses all templates, on error the previously loaded set is kept
+func (r *Registry) Load() error {
+	files, version, err := r.scan()
+	if err != nil {
+		return err
+	}
+	funcs := FuncMap()
+	for k, v := range r.opt.Funcs {
+		funcs[k] = v
+	}
+
+	var tmpl executor
+	if r.opt.Text {
+		t := texttemplate.New("").Funcs(funcs).Delims(r.opt.LeftDelim, r.opt.RightDelim)
+		for _, name := range files {
+			src, err := fs.ReadFile(r.fsys, name)
+			if err != nil {
+				return err
+			}
+			if _, err := t.New(name).Parse(string(src)); err != nil {
+				return fmt.Errorf("templatex: parse %s: %w", name, err)
+			}
+		}
+		tmpl = t
+	} else {
+		funcs["safeHTML"] = func(s string) htmltemplate.HTML { return htmltemplate.HTML(s) }
+		funcs["safeURL"] = func(s string) htmltemplate.URL { return htmltemplate.URL(s) }
+		t := htmltemplate.New("").Funcs(funcs).Delims(r.opt.LeftDelim, r.opt.RightDelim)
+		for _, name := range files {
+			src, err := fs.ReadFile(r.fsys, name)
+			if err != nil {
+				return err
+			}
+			if _, err := t.New(name).Parse(string(src)); err != nil {
+				return fmt.Errorf("templatex: parse %s: %w", name, err)
+			}
+		}
+		tmpl = t
+	}
+
+	r.mu.Lock()
+	r.tmpl, r.names, r.version = tmpl, files, version
+	r.mu.Unlock()
+	return nil
+}
+
+// Render executes the named template with data and writes the output to w
+// The output is buffered, so nothing is written when execution fails
+func (r *Registry) Render(w io.Writer, name string, data any) error {
+	var buf bytes.Buffer
+	if err := r.execute(&buf, name, data); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// RenderString executes the named template with data and returns the output
+func (r *Registry) RenderString(name string, data any) (string, error) {
+	var buf bytes.Buffer
+	if err := r.execute(&buf, name, data); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// Names returns the loaded template names, sorted
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return slices.Clone(r.names)
+}
+
+// Has reports whether a template named name is loaded
+func (r *Registry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, found := slices.BinarySearch(r.names, name)
+	return found
+}
+
+func (r *Registry) execute(w io.Writer, name string, data any) error {
+	if r.opt.Reload {
+		if _, version, err := r.scan(); err == nil && version != r.currentVersion() {
+			if err := r.Load(); err != nil {
+				return err
+			}
+		}
+	}
+	r.mu.RLock()
+	tmpl := r.tmpl
+	r.mu.RUnlock()
+	if !r.Has(name) {
+		return fmt.Errorf("templatex: template %q not found", name)
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
+
+func (r *Registry) currentVersion() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.version
+}
+
+// scan lists template files and fingerprints them to detect changes
+func (r *Registry) scan() ([]string, string, error) {
+	var (
+		files []string
+		sb    strings.Builder
+	)
+	err := fs.WalkDir(r.fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || !slices.Contains(r.opt.Extensions, path.Ext(p)) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, p)
+		fmt.Fprintf(&sb, "%s:%d:%d;", p, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	slices.Sort(files)
+	return files, sb.String(), nil
+}
@@ -0,0 +1,222 @@
+package timex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schedule computes the next activation time strictly after t
+type Schedule interface {
+	Next(t time.Time) time.Time
+}
+
+// CronSchedule is a parsed standard 5-field cron expression
+// Each field is a bit set of the allowed values
+type CronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	loc                           *time.Location
+}
+
+type cronField struct {
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = cronField{0, 59, nil}
+	hourField   = cronField{0, 23, nil}
+	domField    = cronField{1, 31, nil}
+	monthField  = cronField{1, 12, map[string]int{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	dowField = cronField{0, 7, map[string]int{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+
+	cronDescriptors = map[string]string{
+		"@yearly":   "0 0 1 1 *",
+		"@annually": "0 0 1 1 *",
+		"@monthly":  "0 0 1 * *",
+		"@weekly":   "0 0 * * 0",
+		"@daily":    "0 0 * * *",
+		"@midnight": "0 0 * * *",
+		"@hourly":   "0 * * * *",
+	}
+)
+
+// starBit marks a field written as "*" so day-of-month/day-of-week matching follows cron semantics
+const starBit = 1 << 63
+
+// ParseCron parses a cron expression "minute hour day-of-month month day-of-week"
+// Fields accept *, lists (1,2), ranges (1-5), steps (*/15, 0-30/5) and month/weekday names;
+// 7 is accepted as Sunday. Descriptors such as @daily and @hourly are supported, and
+// "@every 5m" returns a fixed interval schedule. The schedule is evaluated in time.Local
+func ParseCron(expr string) (Schedule, error) {
+	return ParseCronInLocation(expr, time.Local)
+}
+
+// ParseCronInLocation is like ParseCron but evaluates the schedule in loc
+func ParseCronInLocation(expr string, loc *time.Location) (Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "@every ") {
+		d, err := time.ParseDuration(strings.TrimSpace(expr[len("@every "):]))
+		if err != nil {
+			return nil, fmt.Errorf("timex: invalid @every duration: %w", err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("timex: @every duration must be positive")
+		}
+		return Every(d), nil
+	}
+	if spec, ok := cronDescriptors[strings.ToLower(expr)]; ok {
+		expr = spec
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("timex: cron expression %q must have 5 fields", expr)
+	}
+
+	var (
+		s   = &CronSchedule{loc: loc}
+		err error
+	)
+	if s.minute, err = parseCronField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseCronField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseCronField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseCronField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseCronField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	// Allow 7 as an alias of Sunday
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	return s, nil
+}
+
+// parseCronField parses one comma-separated cron field into a bit set
+func parseCronField(field string, f cronField) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			n, err := strconv.Atoi(stepPart)
+			if err != nil || n <= 0 {
+				return 0, fmt.Errorf("timex: invalid step %q in cron field %q", stepPart, field)
+			}
+			step = n
+		}
+
+		var lo, hi int
+		switch {
+		case rangePart == "*":
+			lo, hi = f.min, f.max
+			if !hasStep {
+				bits |= starBit
+			}
+		case strings.Contains(rangePart, "-"):
+			a, b, _ := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = f.value(a); err != nil {
+				return 0, err
+			}
+			if hi, err = f.value(b); err != nil {
+				return 0, err
+			}
+		default:
+			v, err := f.value(rangePart)
+			if err != nil {
+				return 0, err
+			}
+			lo, hi = v, v
+			if hasStep {
+				hi = f.max
+			}
+		}
+		if lo > hi {
+			return 0, fmt.Errorf("timex: invalid range %q in cron field %q", rangePart, field)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("timex: value %q out of range [%d, %d]", s, f.min, f.max)
+	}
+	return v, nil
+}
+
+// Next returns the first matching minute strictly after t, or the zero time if none is
+// found within five years (e.g. "0 0 30 2 *")
+func (s *CronSchedule) Next(t time.Time) time.Time {
+	origLoc := t.Location()
+	t = t.In(s.loc).Add(time.Minute - time.Duration(t.Second())*time.Second - time.Duration(t.Nanosecond()))
+	limit := t.AddDate(5, 0, 0)
+
+	for t.Before(limit) {
+		if s.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, s.loc)
+			continue
+		}
+		if s.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, s.loc)
+			continue
+		}
+		if s.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Truncate(time.Minute).Add(time.Minute)
+			continue
+		}
+		return t.In(origLoc)
+	}
+	return time.Time{}
+}
+
+// dayMatches applies cron day semantics: when both day fields are restricted, either may match
+func (s *CronSchedule) dayMatches(t time.Time) bool {
+	domMatch := s.dom&(1<<uint(t.Day())) != 0
+	dowMatch := s.dow&(1<<uint(t.Weekday())) != 0
+	if s.dom&starBit != 0 || s.dow&starBit != 0 {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// intervalSchedule fires at a fixed interval
+type intervalSchedule time.Duration
+
+// Every returns a Schedule that fires every d, d is rounded up to at least one millisecond
+func Every(d time.Duration) Schedule {
+	if d < time.Millisecond {
+		d = time.Millisecond
+	}
+	return intervalSchedule(d)
+}
+
+// Next implements Schedule
+func (i intervalSchedule) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(i))
+}
@@ -0,0 +1,83 @@
+package timex
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	Day  = 24 * time.Hour
+	Week = 7 * Day
+)
+
+// humanUnits are the units used by HumanizeDuration, largest first
+var humanUnits = []struct {
+	d    time.Duration
+	name string
+}{
+	{365 * Day, "year"},
+	{30 * Day, "month"},
+	{Day, "day"},
+	{time.Hour, "hour"},
+	{time.Minute, "minute"},
+	{time.Second, "second"},
+}
+
+// HumanizeDuration renders d using its two largest units, e.g. "2 days 3 hours", "45 seconds"
+// Durations below one second are rendered as "0 seconds"
+func HumanizeDuration(d time.Duration) string {
+	if d < 0 {
+		d = -d
+	}
+	var parts []string
+	for _, u := range humanUnits {
+		if d < u.d {
+			continue
+		}
+		n := d / u.d
+		d -= n * u.d
+		parts = append(parts, plural(int64(n), u.name))
+		if len(parts) == 2 {
+			break
+		}
+	}
+	if len(parts) == 0 {
+		return "0 seconds"
+	}
+	return strings.Join(parts, " ")
+}
+
+// Ago describes t relative to now using its largest unit, e.g. "3 minutes ago", "in 2 days", "just now"
+func Ago(t time.Time) string {
+	return relative(t, time.Now())
+}
+
+func relative(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+	if d < time.Second {
+		return "just now"
+	}
+	var s string
+	for _, u := range humanUnits {
+		if d >= u.d {
+			s = plural(int64(d/u.d), u.name)
+			break
+		}
+	}
+	if future {
+		return "in " + s
+	}
+	return s + " ago"
+}
+
+func plural(n int64, unit string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", unit)
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
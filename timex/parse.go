@@ -0,0 +1,95 @@
+package timex
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// layouts are tried in order by Parse, zone-less layouts are interpreted in the given location
+var layouts = []string{
+	time.RFC3339Nano,
+	time.RFC3339,
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+	"2006-1-2",
+	"2006/1/2 15:04:05",
+	"2006/1/2 15:04",
+	"2006/1/2",
+	"20060102150405",
+	"20060102",
+	time.RFC1123Z,
+	time.RFC1123,
+	time.RFC850,
+	time.RFC822Z,
+	time.RFC822,
+	time.ANSIC,
+	time.UnixDate,
+	time.RubyDate,
+	"2006年01月02日 15:04:05",
+	"2006年01月02日",
+	"2006年1月2日",
+}
+
+// Parse parses s by auto-detecting common layouts, zone-less values are interpreted in local time
+// Supported inputs include RFC3339, "2006-01-02 15:04:05", "2006/1/2", RFC1123 and
+// unix timestamps in seconds, milliseconds, microseconds or nanoseconds
+func Parse(s string) (time.Time, error) {
+	return ParseInLocation(s, time.Local)
+}
+
+// ParseInLocation is like Parse but interprets zone-less values in loc
+func ParseInLocation(s string, loc *time.Location) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, fmt.Errorf("timex: cannot parse empty string")
+	}
+	if t, ok := parseUnix(s); ok {
+		return t.In(loc), nil
+	}
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("timex: unrecognized time format %q", s)
+}
+
+// MustParse is like Parse but panics on error, for constants and tests
+func MustParse(s string) time.Time {
+	t, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// parseUnix detects unix timestamps by digit count: <=11 seconds, 13 millis, 16 micros, 19 nanos
+func parseUnix(s string) (time.Time, bool) {
+	digits := strings.TrimPrefix(s, "-")
+	if digits == "" || strings.Trim(digits, "0123456789") != "" {
+		return time.Time{}, false
+	}
+	// Compact dates such as 20060102 are handled by the layouts
+	if len(digits) == 8 || len(digits) == 14 {
+		return time.Time{}, false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch {
+	case len(digits) <= 11:
+		return time.Unix(n, 0), true
+	case len(digits) <= 13:
+		return time.UnixMilli(n), true
+	case len(digits) <= 16:
+		return time.UnixMicro(n), true
+	default:
+		return time.Unix(0, n), true
+	}
+}
@@ -0,0 +1,90 @@
+package timex
+
+import "time"
+
+// StartOfDay returns 00:00:00 of the day containing t, in t's location
+func StartOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfDay returns the last nanosecond of the day containing t
+func EndOfDay(t time.Time) time.Time {
+	return StartOfDay(t).AddDate(0, 0, 1).Add(-time.Nanosecond)
+}
+
+// StartOfWeek returns Monday 00:00:00 of the week containing t (ISO 8601 weeks)
+func StartOfWeek(t time.Time) time.Time {
+	offset := (int(t.Weekday()) + 6) % 7 // Days since Monday
+	return StartOfDay(t).AddDate(0, 0, -offset)
+}
+
+// EndOfWeek returns the last nanosecond of Sunday of the week containing t
+func EndOfWeek(t time.Time) time.Time {
+	return StartOfWeek(t).AddDate(0, 0, 7).Add(-time.Nanosecond)
+}
+
+// StartOfMonth returns 00:00:00 of the first day of the month containing t
+func StartOfMonth(t time.Time) time.Time {
+	y, m, _ := t.Date()
+	return time.Date(y, m, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfMonth returns the last nanosecond of the month containing t
+func EndOfMonth(t time.Time) time.Time {
+	return StartOfMonth(t).AddDate(0, 1, 0).Add(-time.Nanosecond)
+}
+
+// StartOfYear returns 00:00:00 of January 1st of the year containing t
+func StartOfYear(t time.Time) time.Time {
+	return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+}
+
+// EndOfYear returns the last nanosecond of the year containing t
+func EndOfYear(t time.Time) time.Time {
+	return StartOfYear(t).AddDate(1, 0, 0).Add(-time.Nanosecond)
+}
+
+// Between reports whether t lies within [start, end], inclusive on both ends
+func Between(t, start, end time.Time) bool {
+	return !t.Before(start) && !t.After(end)
+}
+
+// Overlaps reports whether the ranges [aStart, aEnd) and [bStart, bEnd) intersect
+func Overlaps(aStart, aEnd, bStart, bEnd time.Time) bool {
+	return aStart.Before(bEnd) && bStart.Before(aEnd)
+}
+
+// DaysBetween returns the number of calendar days from a to b, negative if b is before a
+// Both values are compared as dates in a's location, so DST changes don't skew the count
+func DaysBetween(a, b time.Time) int {
+	loc := a.Location()
+	ay, am, ad := a.Date()
+	by, bm, bd := b.In(loc).Date()
+	da := time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)
+	db := time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC)
+	return int(db.Sub(da) / Day)
+}
+
+// Range returns the times from start to end (inclusive) advancing by step
+// A non-positive step returns nil
+func Range(start, end time.Time, step time.Duration) []time.Time {
+	if step <= 0 {
+		return nil
+	}
+	var ts []time.Time
+	for t := start; !t.After(end); t = t.Add(step) {
+		ts = append(ts, t)
+	}
+	return ts
+}
+
+// Days returns the start of every calendar day from start's day to end's day (inclusive)
+func Days(start, end time.Time) []time.Time {
+	var ds []time.Time
+	last := StartOfDay(end)
+	for d := StartOfDay(start); !d.After(last); d = d.AddDate(0, 0, 1) {
+		ds = append(ds, d)
+	}
+	return ds
+}
@@ -0,0 +1,186 @@
+package timex
+
+import (
+	"context"
+	"fmt"
+	"github.com/chihqiang/gox/logx"
+	"math/rand/v2"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Job is a unit of work run by the Scheduler, ctx is cancelled when the scheduler stops
+type Job func(ctx context.Context)
+
+// JobOption job configuration structure
+type JobOption struct {
+	Name      string        // Job name used in panic reports
+	Jitter    time.Duration // Random delay in [0, Jitter) added to every activation
+	NoOverlap bool          // Skip an activation while the previous run is still in progress
+}
+
+// JobOptionFunc functional configuration type for jobs
+type JobOptionFunc func(*JobOption)
+
+// WithName names the job for panic reports
+func WithName(name string) JobOptionFunc {
+	return func(o *JobOption) { o.Name = name }
+}
+
+// WithJitter spreads activations by a random delay up to d, avoiding synchronized bursts
+func WithJitter(d time.Duration) JobOptionFunc {
+	return func(o *JobOption) { o.Jitter = d }
+}
+
+// WithoutOverlap skips activations while the previous run of the job is still running
+func WithoutOverlap() JobOptionFunc {
+	return func(o *JobOption) { o.NoOverlap = true }
+}
+
+// PanicHandler is called with the job name and the recovered value when a job panics
+type PanicHandler func(name string, v any)
+
+// defaultPanicHandler logs the panic and stack through logx
+func defaultPanicHandler(name string, v any) {
+	logx.Error("timex: job %s panicked: %v\n%s", name, v, debug.Stack())
+}
+
+type scheduledJob struct {
+	schedule Schedule
+	fn       Job
+	opt      JobOption
+	running  atomic.Bool
+}
+
+// Scheduler runs jobs on cron or fixed-interval schedules
+// Each job is isolated: a panicking job is recovered and reported without affecting others
+type Scheduler struct {
+	mu      sync.Mutex
+	jobs    []*scheduledJob
+	ctx     context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup // Tracks job loops and in-flight runs
+	onPanic PanicHandler
+}
+
+// NewScheduler creates an idle Scheduler, call Start to begin running jobs
+func NewScheduler() *Scheduler {
+	return &Scheduler{onPanic: defaultPanicHandler}
+}
+
+// SetPanicHandler replaces the handler used to report job panics
+func (s *Scheduler) SetPanicHandler(h PanicHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.onPanic = h
+}
+
+// Cron registers fn to run on a cron expression, see ParseCron for the syntax
+func (s *Scheduler) Cron(expr string, fn Job, opts ...JobOptionFunc) error {
+	schedule, err := ParseCron(expr)
+	if err != nil {
+		return err
+	}
+	s.Schedule(schedule, fn, append([]JobOptionFunc{WithName(expr)}, opts...)...)
+	return nil
+}
+
+// Every registers fn to run every d
+func (s *Scheduler) Every(d time.Duration, fn Job, opts ...JobOptionFunc) {
+	s.Schedule(Every(d), fn, append([]JobOptionFunc{WithName("@every " + d.String())}, opts...)...)
+}
+
+// Schedule registers fn with a custom Schedule
+// Jobs added after Start begin running immediately
+func (s *Scheduler) Schedule(schedule Schedule, fn Job, opts ...JobOptionFunc) {
+	job := &scheduledJob{schedule: schedule, fn: fn}
+	for _, opt := range opts {
+		opt(&job.opt)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if job.opt.Name == "" {
+		job.opt.Name = fmt.Sprintf("job-%d", len(s.jobs)+1)
+	}
+	s.jobs = append(s.jobs, job)
+	if s.ctx != nil {
+		s.startJob(s.ctx, job)
+	}
+}
+
+// Start begins running registered jobs in the background until ctx is done or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.ctx != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	for _, job := range s.jobs {
+		s.startJob(s.ctx, job)
+	}
+}
+
+// Stop cancels the scheduler context and waits for running jobs to return
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// startJob launches the activation loop of a job, s.mu must be held
+func (s *Scheduler) startJob(ctx context.Context, job *scheduledJob) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		timer := time.NewTimer(0)
+		<-timer.C
+		for {
+			next := job.schedule.Next(time.Now())
+			if next.IsZero() {
+				return // Schedule will never fire again
+			}
+			if job.opt.Jitter > 0 {
+				next = next.Add(rand.N(job.opt.Jitter))
+			}
+			timer.Reset(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			if job.opt.NoOverlap && !job.running.CompareAndSwap(false, true) {
+				continue
+			}
+			s.wg.Add(1)
+			go s.run(ctx, job)
+		}
+	}()
+}
+
+// run executes one activation with panic isolation
+func (s *Scheduler) run(ctx context.Context, job *scheduledJob) {
+	defer s.wg.Done()
+	defer func() {
+		if job.opt.NoOverlap {
+			job.running.Store(false)
+		}
+		if v := recover(); v != nil {
+			s.mu.Lock()
+			onPanic := s.onPanic
+			s.mu.Unlock()
+			if onPanic != nil {
+				onPanic(job.opt.Name, v)
+			}
+		}
+	}()
+	job.fn(ctx)
+}
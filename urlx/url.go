@@ -0,0 +1,155 @@
+package urlx
+
+import (
+	"net"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// defaultPorts maps schemes to the port implied when none is given
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ws":    "80",
+	"wss":   "443",
+	"ftp":   "21",
+}
+
+// Join appends path segments to base, collapsing duplicate slashes between them
+// The query of base is kept, a trailing slash on the last segment is preserved
+//
+//	Join("https://api.example.com/v1/", "/users/", "42") // https://api.example.com/v1/users/42
+func Join(base string, segments ...string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	p := u.Path
+	for _, seg := range segments {
+		if seg == "" {
+			continue
+		}
+		p = strings.TrimRight(p, "/") + "/" + strings.TrimLeft(seg, "/")
+	}
+	u.Path = p
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+// AddQuery appends the key/value pair to the query of rawURL, existing values are kept
+func AddQuery(rawURL, key, value string) (string, error) {
+	return editQuery(rawURL, func(q url.Values) { q.Add(key, value) })
+}
+
+// SetQuery sets key in the query of rawURL, replacing existing values
+func SetQuery(rawURL, key, value string) (string, error) {
+	return editQuery(rawURL, func(q url.Values) { q.Set(key, value) })
+}
+
+// DelQuery removes key from the query of rawURL
+func DelQuery(rawURL, key string) (string, error) {
+	return editQuery(rawURL, func(q url.Values) { q.Del(key) })
+}
+
+// AddValues merges values into the query of rawURL
+func AddValues(rawURL string, values url.Values) (string, error) {
+	return editQuery(rawURL, func(q url.Values) {
+		for k, vs := range values {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+	})
+}
+
+func editQuery(rawURL string, fn func(url.Values)) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	q := u.Query()
+	fn(q)
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Normalize returns a canonical form of rawURL so equivalent URLs compare equal:
+// scheme and host are lowercased, default ports are removed, dot segments are resolved,
+// an empty path becomes "/" and query parameters are sorted by key
+func Normalize(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", err
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Host)
+	if h, port, err := net.SplitHostPort(host); err == nil && defaultPorts[u.Scheme] == port {
+		host = h
+		if strings.Contains(h, ":") {
+			host = "[" + h + "]"
+		}
+	}
+	u.Host = host
+
+	if u.Path == "" {
+		if u.Host != "" {
+			u.Path = "/"
+		}
+	} else {
+		cleaned := path.Clean(u.Path)
+		if strings.HasSuffix(u.Path, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		u.Path = cleaned
+	}
+	u.RawPath = ""
+	if u.RawQuery != "" {
+		u.RawQuery = u.Query().Encode()
+	}
+	return u.String(), nil
+}
+
+// IsAbs reports whether rawURL is absolute, i.e. has a scheme and a host
+func IsAbs(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+// SameOrigin reports whether a and b share scheme, host and port (default ports are implied)
+func SameOrigin(a, b string) bool {
+	ua, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	ub, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	if ua.Host == "" || ub.Host == "" {
+		return false
+	}
+	return strings.EqualFold(ua.Scheme, ub.Scheme) &&
+		strings.EqualFold(ua.Hostname(), ub.Hostname()) &&
+		port(ua) == port(ub)
+}
+
+// ResolveReference resolves ref against base, ref may be relative or absolute
+func ResolveReference(base, ref string) (string, error) {
+	bu, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	ru, err := url.Parse(ref)
+	if err != nil {
+		return "", err
+	}
+	return bu.ResolveReference(ru).String(), nil
+}
+
+func port(u *url.URL) string {
+	if p := u.Port(); p != "" {
+		return p
+	}
+	return defaultPorts[strings.ToLower(u.Scheme)]
+}
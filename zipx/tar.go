@@ -0,0 +1,106 @@
+package zipx
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+)
+
+// TarGzDir writes the contents of src (a directory or a single file) to a new .tar.gz file dest
+func TarGzDir(src, dest string, opts ...OptionFunc) (err error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(dest)
+		}
+	}()
+	return WriteTarGz(f, src, opts...)
+}
+
+// WriteTarGz streams a gzip-compressed tar archive of src to w
+func WriteTarGz(w io.Writer, src string, opts ...OptionFunc) error {
+	o := newOption(opts)
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	err := walk(src, o.Exclude, func(name, path string, info fs.FileInfo) error {
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		return copyFile(tw, path)
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+// UntarGz extracts the .tar.gz file src into dest with the same protections as Unzip
+func UntarGz(src, dest string, opts ...OptionFunc) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return UntarGzReader(f, dest, opts...)
+}
+
+// UntarGzReader is like UntarGz but reads the archive from r, e.g. a request or response body
+func UntarGzReader(r io.Reader, dest string, opts ...OptionFunc) error {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gr.Close()
+
+	l := &limits{opt: newOption(opts)}
+	tr := tar.NewReader(gr)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path, err := safeJoin(dest, header.Name)
+		if err != nil {
+			return err
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := l.reserve(header.Size); err != nil {
+				return err
+			}
+			if err := l.writeFile(path, tr, header.FileInfo().Mode()); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,283 @@
+package zipx
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrUnsafePath    = errors.New("zipx: entry path escapes the destination")
+	ErrFileTooLarge  = errors.New("zipx: entry exceeds the maximum file size")
+	ErrTotalTooLarge = errors.New("zipx: archive exceeds the maximum total size")
+	ErrTooManyFiles  = errors.New("zipx: archive exceeds the maximum number of entries")
+)
+
+// Option archive configuration structure
+type Option struct {
+	MaxFileSize  int64    // Largest uncompressed entry accepted on extraction, default 512MiB, <0 disables
+	MaxTotalSize int64    // Largest uncompressed archive accepted on extraction, default 2GiB, <0 disables
+	MaxFiles     int      // Most entries accepted on extraction, default 100000, <0 disables
+	Exclude      []string // Glob patterns (base name or slash-separated relative path) skipped when archiving
+}
+
+// OptionFunc functional configuration type
+type OptionFunc func(*Option)
+
+// WithMaxFileSize bounds the size of a single extracted entry, protecting against zip bombs
+func WithMaxFileSize(n int64) OptionFunc {
+	return func(o *Option) { o.MaxFileSize = n }
+}
+
+// WithMaxTotalSize bounds the total extracted size
+func WithMaxTotalSize(n int64) OptionFunc {
+	return func(o *Option) { o.MaxTotalSize = n }
+}
+
+// WithMaxFiles bounds the number of extracted entries
+func WithMaxFiles(n int) OptionFunc {
+	return func(o *Option) { o.MaxFiles = n }
+}
+
+// WithExclude skips files matching one of the glob patterns when archiving, e.g. ".git" or "*.log"
+func WithExclude(patterns ...string) OptionFunc {
+	return func(o *Option) { o.Exclude = append(o.Exclude, patterns...) }
+}
+
+func newOption(opts []OptionFunc) *Option {
+	o := &Option{MaxFileSize: 512 << 20, MaxTotalSize: 2 << 30, MaxFiles: 100000}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// ZipDir writes the contents of src (a directory or a single file) to a new zip file dest
+func ZipDir(src, dest string, opts ...OptionFunc) (err error) {
+	f, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			_ = os.Remove(dest)
+		}
+	}()
+	return WriteZip(f, src, opts...)
+}
+
+// WriteZip streams a zip archive of src to w, e.g. an http.ResponseWriter after httpx.Attachment
+func WriteZip(w io.Writer, src string, opts ...OptionFunc) error {
+	o := newOption(opts)
+	zw := zip.NewWriter(w)
+	err := walk(src, o.Exclude, func(name, path string, info fs.FileInfo) error {
+		header, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+		if info.IsDir() {
+			header.Name += "/"
+			_, err = zw.CreateHeader(header)
+			return err
+		}
+		header.Method = zip.Deflate
+		dst, err := zw.CreateHeader(header)
+		if err != nil {
+			return err
+		}
+		return copyFile(dst, path)
+	})
+	if err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// Unzip extracts the zip file src into dest, rejecting entries that would escape dest
+// and enforcing the size and entry limits; symlinks and other special entries are skipped
+func Unzip(src, dest string, opts ...OptionFunc) error {
+	r, err := zip.OpenReader(src)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	return extractZip(&r.Reader, dest, newOption(opts))
+}
+
+// UnzipReader is like Unzip but reads the archive from r, e.g. an uploaded *os.File or bytes.Reader
+func UnzipReader(r io.ReaderAt, size int64, dest string, opts ...OptionFunc) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	return extractZip(zr, dest, newOption(opts))
+}
+
+func extractZip(r *zip.Reader, dest string, o *Option) error {
+	if o.MaxFiles >= 0 && len(r.File) > o.MaxFiles {
+		return ErrTooManyFiles
+	}
+	l := &limits{opt: o}
+	for _, f := range r.File {
+		path, err := safeJoin(dest, f.Name)
+		if err != nil {
+			return err
+		}
+		mode := f.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(path, 0o755); err != nil {
+				return err
+			}
+		case mode.IsRegular():
+			if err := l.reserve(int64(f.UncompressedSize64)); err != nil {
+				return err
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return err
+			}
+			err = l.writeFile(path, rc, mode)
+			rc.Close()
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// walk calls fn for every directory and regular file under src with its slash-separated
+// archive name; a single file is stored under its base name
+func walk(src string, exclude []string, fn func(name, path string, info fs.FileInfo) error) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fn(filepath.Base(src), src, info)
+	}
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if excluded(exclude, name) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return fn(name, path, info)
+	})
+}
+
+func excluded(patterns []string, name string) bool {
+	base := name[strings.LastIndex(name, "/")+1:]
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return true
+		}
+		if ok, _ := filepath.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func copyFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// safeJoin resolves an archive entry name inside dest, rejecting absolute and ".." paths (zip slip)
+func safeJoin(dest, name string) (string, error) {
+	name = filepath.FromSlash(strings.ReplaceAll(name, `\`, "/"))
+	if name == "" || !filepath.IsLocal(name) {
+		return "", fmt.Errorf("%w: %q", ErrUnsafePath, name)
+	}
+	return filepath.Join(dest, name), nil
+}
+
+// limits enforces the extraction limits across the entries of one archive
+type limits struct {
+	opt   *Option
+	files int
+	total int64
+}
+
+// reserve accounts for a new entry whose declared size is size
+func (l *limits) reserve(size int64) error {
+	l.files++
+	if l.opt.MaxFiles >= 0 && l.files > l.opt.MaxFiles {
+		return ErrTooManyFiles
+	}
+	if l.opt.MaxFileSize >= 0 && size > l.opt.MaxFileSize {
+		return ErrFileTooLarge
+	}
+	return nil
+}
+
+// writeFile copies r to path, counting the bytes actually written since declared sizes can lie
+func (l *limits) writeFile(path string, r io.Reader, mode fs.FileMode) (err error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm()|0o200)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	limit := int64(-1)
+	if l.opt.MaxFileSize >= 0 {
+		limit = l.opt.MaxFileSize
+	}
+	if l.opt.MaxTotalSize >= 0 && (limit < 0 || l.opt.MaxTotalSize-l.total < limit) {
+		limit = l.opt.MaxTotalSize - l.total
+	}
+	if limit >= 0 {
+		r = io.LimitReader(r, limit+1)
+	}
+	n, err := io.Copy(f, r)
+	l.total += n
+	if err != nil {
+		return err
+	}
+	if limit >= 0 && n > limit {
+		if l.opt.MaxTotalSize >= 0 && l.total > l.opt.MaxTotalSize {
+			return ErrTotalTooLarge
+		}
+		return ErrFileTooLarge
+	}
+	return nil
+}